@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"os"
+	authRepository "task-manager/internal/auth/repository"
+	authUseCase "task-manager/internal/auth/usecase"
+	"task-manager/pkg/config"
+	database "task-manager/pkg/database/postgres"
+	datebaseredis "task-manager/pkg/database/redis"
+	"task-manager/pkg/jwt"
+	"task-manager/pkg/logger"
+	"task-manager/pkg/rbac"
+)
+
+const usage = `usage: admin <command>
+
+commands:
+  grant EMAIL [ROLE]   assign ROLE (default admin) to a registered user`
+
+// Администрирование из командной строки: назначение первого администратора,
+// пока в системе нет никого с правом users:manage
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cfg := config.Load()
+	log := logger.Init(cfg.Environment)
+	defer log.Sync()
+
+	db := database.NewPostgres(cfg)
+	defer db.Close()
+
+	redis := datebaseredis.New(cfg)
+	defer redis.Close()
+
+	authRepo := authRepository.NewAuthRepository(db, log)
+	authUC := authUseCase.NewAuthUsecase(authRepo, jwt.NewDenylist(redis), cfg, log)
+
+	args := os.Args[1:]
+	switch {
+	case args[0] == "grant" && (len(args) == 2 || len(args) == 3):
+		role := rbac.RoleAdmin
+		if len(args) == 3 {
+			role = args[2]
+		}
+
+		user, err := authUC.GrantRole(context.Background(), args[1], role)
+		if err != nil {
+			log.Fatal("Failed to grant role",
+				zap.Error(err),
+				zap.String("email", args[1]),
+				zap.String("role", role),
+			)
+		}
+		fmt.Printf("user %d (%s) now has role %s\n", user.ID, user.Email, user.Role)
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"os"
+	"strconv"
+	"task-manager/migrations"
+	"task-manager/pkg/config"
+	"task-manager/pkg/database/migrate"
+	database "task-manager/pkg/database/postgres"
+	"task-manager/pkg/logger"
+)
+
+const usage = `usage: migrate <command>
+
+commands:
+  up           apply all pending migrations
+  down [N]     roll back N migrations (default 1)
+  status       print current schema version and pending migrations
+  force V      set schema version to V and clear the dirty flag`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cfg := config.Load()
+	log := logger.Init(cfg.Environment)
+	defer log.Sync()
+
+	db := database.NewPostgres(cfg)
+	defer db.Close()
+
+	migrator, err := migrate.New(db, migrations.FS, log)
+	if err != nil {
+		log.Fatal("Failed to load migrations", zap.Error(err))
+	}
+
+	if err := run(context.Background(), migrator, os.Args[1:]); err != nil {
+		log.Fatal("Migration command failed",
+			zap.Error(err),
+			zap.Strings("args", os.Args[1:]),
+		)
+	}
+}
+
+func run(ctx context.Context, migrator *migrate.Migrator, args []string) error {
+	switch args[0] {
+	case "up":
+		return migrator.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+			steps = n
+		}
+		return migrator.Down(ctx, steps)
+	case "force":
+		if len(args) < 2 {
+			return fmt.Errorf("force requires a version")
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || version < 0 {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		return migrator.Force(ctx, version)
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("version: %d (latest %d)\n", status.Version, status.Latest)
+		fmt.Printf("dirty:   %t\n", status.Dirty)
+		for _, m := range status.Pending {
+			fmt.Printf("pending: %d_%s\n", m.Version, m.Name)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+	}
+}
@@ -1,3 +1,114 @@
 package main
 
-//Джоба для чистки просрочек
+import (
+	"context"
+	"go.uber.org/zap"
+	"task-manager/internal/task"
+	"task-manager/internal/task/entity"
+	taskRepository "task-manager/internal/task/repository"
+	taskUseCase "task-manager/internal/task/usecase"
+	"task-manager/internal/webhook"
+	webhookRepository "task-manager/internal/webhook/repository"
+	webhookUseCase "task-manager/internal/webhook/usecase"
+	"task-manager/internal/worker"
+	"task-manager/pkg/config"
+	database "task-manager/pkg/database/postgres"
+	datebaseredis "task-manager/pkg/database/redis"
+	"task-manager/pkg/logger"
+	"task-manager/pkg/notifier"
+	"time"
+)
+
+// Воркер для фоновых задач: просрочки, повторяющиеся задачи, напоминания, вебхуки,
+// публикация событий задач из outbox и их раздача вебхукам и живым стримам
+func main() {
+	cfg := config.Load()
+	log := logger.Init(cfg.Environment)
+	defer log.Sync()
+
+	policy := entity.OverduePolicy(cfg.Worker.OverduePolicy)
+	if !policy.Valid() {
+		log.Fatal("Invalid overdue policy", zap.String("policy", cfg.Worker.OverduePolicy))
+	}
+
+	db := database.NewPostgres(cfg)
+	defer db.Close()
+
+	redis := datebaseredis.New(cfg)
+	defer redis.Close()
+
+	taskRepo := taskRepository.NewRepository(db, redis, log)
+	subtasks, err := task.NewSubtaskRulesFromConfig(cfg.Task)
+	if err != nil {
+		log.Fatal("Invalid subtask rules", zap.Error(err))
+	}
+	recurrenceRepo := taskRepository.NewRecurrenceRepository(db, redis, log)
+	workflow, err := task.NewWorkflowFromConfig(cfg.Task)
+	if err != nil {
+		log.Fatal("Invalid task workflow", zap.Error(err))
+	}
+
+	notify, err := notifier.New(cfg.Notifier, log)
+	if err != nil {
+		log.Fatal("Invalid notifier configuration", zap.Error(err))
+	}
+	reminderRepo := taskRepository.NewReminderRepository(db, redis, log)
+	reminderUC := taskUseCase.NewReminderUseCase(reminderRepo, taskRepo, notify, log)
+
+	webhookRepo := webhookRepository.NewRepository(db, log)
+	webhookUC := webhookUseCase.NewWebhookUseCase(webhookRepo, webhook.NewClient(cfg.Webhook.Timeout), log)
+
+	liveRepo := taskRepository.NewLiveEventRepository(redis, int64(cfg.Live.Backlog), cfg.Live.BacklogTTL, log)
+	liveUC := taskUseCase.NewLiveEventUseCase(liveRepo, log)
+
+	taskUC := taskUseCase.NewTaskUseCase(
+		taskRepo,
+		recurrenceRepo,
+		reminderUC,
+		workflow,
+		subtasks,
+		log,
+	)
+
+	outboxRelay := taskUseCase.NewOutboxRelay(taskRepository.NewOutboxRepository(db, redis, log), log)
+
+	// События задач из outbox - единственный источник для вебхуков и живых стримов.
+	// Группы создаются до запуска релея, чтобы не пропустить ни одного события.
+	eventRepo := taskRepository.NewEventStreamRepository(redis, log)
+	handlers := map[string]task.EventHandler{
+		task.EventGroupWebhooks: webhookUC,
+		task.EventGroupLive:     liveUC,
+	}
+	for group := range handlers {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err := eventRepo.CreateGroup(ctx, group)
+		cancel()
+		if err != nil {
+			log.Fatal("Failed to create task event consumer group", zap.Error(err), zap.String("group", group))
+		}
+	}
+
+	w := worker.New(cfg.Worker.ShutdownTimeout, log)
+	w.AddLeader("overdue_tasks", cfg.Worker.OverdueInterval, redis, cfg.Worker.LockTTL,
+		worker.OverdueJob(taskUC, policy, cfg.Worker.OverdueGracePeriod, log))
+	w.AddLeader("recurring_tasks", cfg.Worker.RecurrenceInterval, redis, cfg.Worker.LockTTL,
+		worker.RecurrenceJob(taskUC, log))
+	// Напоминания забираются из расписания атомарно, поэтому лок лидера не нужен
+	w.Add("reminders", cfg.Worker.ReminderInterval, worker.ReminderJob(reminderUC, log))
+	// Доставки вебхуков разбираются через SKIP LOCKED, лок лидера тоже не нужен
+	w.Add("webhooks", cfg.Worker.WebhookInterval, worker.WebhookJob(webhookUC, log))
+	// Порядок событий держится на единственном релее, поэтому он работает под локом лидера
+	w.AddLeader("task_outbox", cfg.Worker.OutboxInterval, redis, cfg.Worker.LockTTL,
+		worker.OutboxJob(outboxRelay, cfg.Worker.OutboxRetention, log))
+	// Группа читается по порядку одним потребителем, поэтому тоже под локом лидера
+	for group, handler := range handlers {
+		name := "task_events_" + group
+		consumer := taskUseCase.NewEventConsumer(eventRepo, group, handler, log)
+		w.AddLeader(name, cfg.Worker.EventsInterval, redis, cfg.Worker.LockTTL,
+			worker.EventsJob(consumer, log.With(zap.String("group", group))))
+	}
+
+	if err := w.Start(); err != nil {
+		log.Fatal("Worker failed", zap.Error(err))
+	}
+}
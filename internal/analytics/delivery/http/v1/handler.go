@@ -1,3 +1,86 @@
 package v1
 
-//ручки аналитики
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"task-manager/internal/analytics"
+	"task-manager/internal/analytics/dtos"
+	"task-manager/pkg/apperror"
+	"time"
+)
+
+type AnalyticsHandler struct {
+	uc  analytics.AnalyticsUseCase
+	log *zap.Logger
+}
+
+func NewAnalyticsHandler(uc analytics.AnalyticsUseCase, log *zap.Logger) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		uc:  uc,
+		log: log.Named("analytics_handler"),
+	}
+}
+
+// GetTaskStats возвращает статистику задач текущего пользователя за период
+func (h *AnalyticsHandler) GetTaskStats(c *gin.Context) {
+	req, ok := h.bindRange(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.uc.GetTaskStats(c.Request.Context(), req)
+	if err != nil {
+		h.log.Error("Failed to get task stats", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetFlowMetrics возвращает lead time и cycle time по задачам, завершенным за период
+func (h *AnalyticsHandler) GetFlowMetrics(c *gin.Context) {
+	req, ok := h.bindRange(c)
+	if !ok {
+		return
+	}
+
+	metrics, err := h.uc.GetFlowMetrics(c.Request.Context(), req)
+	if err != nil {
+		h.log.Error("Failed to get flow metrics", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, metrics)
+}
+
+func (h *AnalyticsHandler) bindRange(c *gin.Context) (dtos.StatsRequest, bool) {
+	from, err := parseTime(c.Query("from"))
+	if err != nil {
+		h.log.Warn("Invalid from parameter", zap.Error(err))
+		c.Error(apperror.Wrap(apperror.ErrInvalidInput, "invalid from parameter", err))
+		return dtos.StatsRequest{}, false
+	}
+
+	to, err := parseTime(c.Query("to"))
+	if err != nil {
+		h.log.Warn("Invalid to parameter", zap.Error(err))
+		c.Error(apperror.Wrap(apperror.ErrInvalidInput, "invalid to parameter", err))
+		return dtos.StatsRequest{}, false
+	}
+
+	return dtos.StatsRequest{From: from, To: to}, true
+}
+
+// parseTime принимает RFC3339 или дату в формате YYYY-MM-DD, пустое значение - нулевое время
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
@@ -1,3 +1,14 @@
 package v1
 
-//роутеры аналитики
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func (h *AnalyticsHandler) AnalyticsRoutes(router *gin.RouterGroup, auth gin.HandlerFunc) {
+	// Группа защищенных роутов аналитики
+	analyticsGroup := router.Group("/analytics").Use(auth)
+	{
+		analyticsGroup.GET("/tasks", h.GetTaskStats)
+		analyticsGroup.GET("/flow", h.GetFlowMetrics)
+	}
+}
@@ -0,0 +1,20 @@
+package dtos
+
+import "time"
+
+// DurationStats - распределение длительностей, в секундах
+type DurationStats struct {
+	AvgSeconds    *float64 `json:"avg_seconds,omitempty"`
+	MedianSeconds *float64 `json:"median_seconds,omitempty"`
+	P85Seconds    *float64 `json:"p85_seconds,omitempty"`
+}
+
+// FlowMetrics - lead time (создание -> done) и cycle time (первый in_progress -> done)
+// по задачам, завершенным за период
+type FlowMetrics struct {
+	From      time.Time     `json:"from"`
+	To        time.Time     `json:"to"`
+	Completed int64         `json:"completed"`
+	LeadTime  DurationStats `json:"lead_time"`
+	CycleTime DurationStats `json:"cycle_time"`
+}
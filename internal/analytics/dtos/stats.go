@@ -0,0 +1,21 @@
+package dtos
+
+import "time"
+
+// StatsRequest - период, за который считается статистика (по дате создания задач)
+type StatsRequest struct {
+	From time.Time
+	To   time.Time
+}
+
+// TaskStats - статистика задач пользователя за период
+type TaskStats struct {
+	From                 time.Time        `json:"from"`
+	To                   time.Time        `json:"to"`
+	Total                int64            `json:"total"`
+	ByStatus             map[string]int64 `json:"by_status"`
+	ByPriority           map[string]int64 `json:"by_priority"`
+	CompletionRate       float64          `json:"completion_rate"`
+	Overdue              int64            `json:"overdue"`
+	AvgTimeToDoneSeconds *float64         `json:"avg_time_to_done_seconds,omitempty"`
+}
@@ -0,0 +1,8 @@
+package analytics
+
+import "task-manager/pkg/apperror"
+
+var (
+	// ErrInvalidRange - некорректный период статистики
+	ErrInvalidRange = apperror.New(apperror.ErrInvalidInput, "invalid date range")
+)
@@ -0,0 +1,12 @@
+package analytics
+
+import (
+	"context"
+	"task-manager/internal/analytics/dtos"
+	"time"
+)
+
+type AnalyticsRepository interface {
+	GetTaskStats(ctx context.Context, from, to time.Time) (*dtos.TaskStats, error)
+	GetFlowMetrics(ctx context.Context, from, to time.Time) (*dtos.FlowMetrics, error)
+}
@@ -1,3 +1,210 @@
 package repository
 
-//репо аналитики
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go.uber.org/zap"
+	"task-manager/internal/analytics"
+	"task-manager/internal/analytics/dtos"
+	"task-manager/internal/task/entity"
+	"task-manager/pkg/identity"
+	"time"
+)
+
+type Repository struct {
+	db  *sql.DB
+	log *zap.Logger
+}
+
+func NewRepository(db *sql.DB, log *zap.Logger) analytics.AnalyticsRepository {
+	return &Repository{
+		db:  db,
+		log: log.Named("analytics_repository"),
+	}
+}
+
+func (r *Repository) GetTaskStats(ctx context.Context, from, to time.Time) (*dtos.TaskStats, error) {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.log.Debug("Calculating task stats",
+		zap.Int64("user_id", userID),
+		zap.Time("from", from),
+		zap.Time("to", to),
+	)
+
+	stats := &dtos.TaskStats{
+		From:       from,
+		To:         to,
+		ByStatus:   map[string]int64{},
+		ByPriority: map[string]int64{},
+	}
+
+	if err := r.countBy(ctx, "status", userID, from, to, stats.ByStatus); err != nil {
+		return nil, err
+	}
+	if err := r.countBy(ctx, "priority", userID, from, to, stats.ByPriority); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = $4),
+			COUNT(*) FILTER (WHERE status != $4 AND due_date < NOW()),
+			AVG(EXTRACT(EPOCH FROM completed_at - created_at)) FILTER (WHERE status = $4 AND completed_at IS NOT NULL)
+		FROM tasks
+		WHERE user_id = $1 AND created_at >= $2 AND created_at < $3
+		AND archived_at IS NULL`
+
+	var (
+		done    int64
+		avgDone sql.NullFloat64
+	)
+	err = r.db.QueryRowContext(ctx, query, userID, from, to, entity.StatusDone).
+		Scan(&stats.Total, &done, &stats.Overdue, &avgDone)
+	if err != nil {
+		r.log.Error("Failed to calculate task totals",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+		)
+		return nil, fmt.Errorf("failed to calculate task totals: %w", err)
+	}
+
+	if stats.Total > 0 {
+		stats.CompletionRate = float64(done) / float64(stats.Total)
+	}
+	if avgDone.Valid {
+		stats.AvgTimeToDoneSeconds = &avgDone.Float64
+	}
+
+	r.log.Debug("Task stats calculated",
+		zap.Int64("user_id", userID),
+		zap.Int64("total", stats.Total),
+	)
+	return stats, nil
+}
+
+// countBy считает задачи с группировкой по колонке column (status или priority)
+func (r *Repository) countBy(
+	ctx context.Context,
+	column string,
+	userID int64,
+	from, to time.Time,
+	dest map[string]int64,
+) error {
+	query := fmt.Sprintf(`
+		SELECT %[1]s, COUNT(*)
+		FROM tasks
+		WHERE user_id = $1 AND created_at >= $2 AND created_at < $3
+		AND archived_at IS NULL
+		GROUP BY %[1]s`, column)
+
+	rows, err := r.db.QueryContext(ctx, query, userID, from, to)
+	if err != nil {
+		r.log.Error("Failed to count tasks",
+			zap.Error(err),
+			zap.String("group_by", column),
+		)
+		return fmt.Errorf("failed to count tasks by %s: %w", column, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			key   string
+			count int64
+		)
+		if err := rows.Scan(&key, &count); err != nil {
+			return fmt.Errorf("failed to scan %s count: %w", column, err)
+		}
+		dest[key] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows iteration error: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) GetFlowMetrics(ctx context.Context, from, to time.Time) (*dtos.FlowMetrics, error) {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.log.Debug("Calculating flow metrics",
+		zap.Int64("user_id", userID),
+		zap.Time("from", from),
+		zap.Time("to", to),
+	)
+
+	// Берем последний переход в done за период и первый переход в in_progress до него
+	query := `
+		WITH done AS (
+			SELECT h.task_id, MAX(h.changed_at) AS done_at
+			FROM task_status_history h
+			JOIN tasks t ON t.id = h.task_id
+			WHERE t.user_id = $1 AND t.archived_at IS NULL AND h.new_status = $4
+			AND h.changed_at >= $2 AND h.changed_at < $3
+			GROUP BY h.task_id
+		), started AS (
+			SELECT h.task_id, MIN(h.changed_at) AS started_at
+			FROM task_status_history h
+			JOIN done d ON d.task_id = h.task_id
+			WHERE h.new_status = $5 AND h.changed_at <= d.done_at
+			GROUP BY h.task_id
+		), durations AS (
+			SELECT
+				EXTRACT(EPOCH FROM d.done_at - t.created_at) AS lead_time,
+				EXTRACT(EPOCH FROM d.done_at - s.started_at) AS cycle_time
+			FROM done d
+			JOIN tasks t ON t.id = d.task_id
+			LEFT JOIN started s ON s.task_id = d.task_id
+		)
+		SELECT
+			COUNT(*),
+			AVG(lead_time),
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY lead_time),
+			percentile_cont(0.85) WITHIN GROUP (ORDER BY lead_time),
+			AVG(cycle_time),
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY cycle_time),
+			percentile_cont(0.85) WITHIN GROUP (ORDER BY cycle_time)
+		FROM durations`
+
+	var (
+		metrics = &dtos.FlowMetrics{From: from, To: to}
+		lead    [3]sql.NullFloat64
+		cycle   [3]sql.NullFloat64
+	)
+	err = r.db.QueryRowContext(ctx, query, userID, from, to, entity.StatusDone, entity.StatusInProgress).
+		Scan(&metrics.Completed, &lead[0], &lead[1], &lead[2], &cycle[0], &cycle[1], &cycle[2])
+	if err != nil {
+		r.log.Error("Failed to calculate flow metrics",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+		)
+		return nil, fmt.Errorf("failed to calculate flow metrics: %w", err)
+	}
+
+	metrics.LeadTime = toDurationStats(lead)
+	metrics.CycleTime = toDurationStats(cycle)
+	return metrics, nil
+}
+
+func toDurationStats(values [3]sql.NullFloat64) dtos.DurationStats {
+	ptr := func(v sql.NullFloat64) *float64 {
+		if !v.Valid {
+			return nil
+		}
+		return &v.Float64
+	}
+	return dtos.DurationStats{
+		AvgSeconds:    ptr(values[0]),
+		MedianSeconds: ptr(values[1]),
+		P85Seconds:    ptr(values[2]),
+	}
+}
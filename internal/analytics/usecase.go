@@ -0,0 +1,11 @@
+package analytics
+
+import (
+	"context"
+	"task-manager/internal/analytics/dtos"
+)
+
+type AnalyticsUseCase interface {
+	GetTaskStats(ctx context.Context, req dtos.StatsRequest) (*dtos.TaskStats, error)
+	GetFlowMetrics(ctx context.Context, req dtos.StatsRequest) (*dtos.FlowMetrics, error)
+}
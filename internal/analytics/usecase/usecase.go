@@ -1,3 +1,85 @@
 package usecase
 
-//usecase аналитики
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"task-manager/internal/analytics"
+	"task-manager/internal/analytics/dtos"
+	"task-manager/pkg/apperror"
+	"time"
+)
+
+const (
+	defaultRange = 30 * 24 * time.Hour
+	maxRange     = 366 * 24 * time.Hour
+)
+
+type analyticsUseCase struct {
+	repo analytics.AnalyticsRepository
+	log  *zap.Logger
+}
+
+func NewAnalyticsUseCase(repo analytics.AnalyticsRepository, log *zap.Logger) analytics.AnalyticsUseCase {
+	return &analyticsUseCase{
+		repo: repo,
+		log:  log.Named("analytics_usecase"),
+	}
+}
+
+func (uc *analyticsUseCase) GetTaskStats(ctx context.Context, req dtos.StatsRequest) (*dtos.TaskStats, error) {
+	req, err := uc.normalizeRange(req)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := uc.repo.GetTaskStats(ctx, req.From, req.To)
+	if err != nil {
+		uc.log.Error("Failed to get task stats",
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("failed to get task stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+func (uc *analyticsUseCase) GetFlowMetrics(ctx context.Context, req dtos.StatsRequest) (*dtos.FlowMetrics, error) {
+	req, err := uc.normalizeRange(req)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics, err := uc.repo.GetFlowMetrics(ctx, req.From, req.To)
+	if err != nil {
+		uc.log.Error("Failed to get flow metrics",
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("failed to get flow metrics: %w", err)
+	}
+
+	return metrics, nil
+}
+
+// normalizeRange подставляет период по умолчанию и проверяет границы
+func (uc *analyticsUseCase) normalizeRange(req dtos.StatsRequest) (dtos.StatsRequest, error) {
+	if req.To.IsZero() {
+		req.To = time.Now()
+	}
+	if req.From.IsZero() {
+		req.From = req.To.Add(-defaultRange)
+	}
+
+	if !req.From.Before(req.To) {
+		uc.log.Warn("Validation failed: from is not before to",
+			zap.Time("from", req.From),
+			zap.Time("to", req.To),
+		)
+		return req, apperror.Wrap(apperror.ErrInvalidInput, "from must be before to", analytics.ErrInvalidRange)
+	}
+	if req.To.Sub(req.From) > maxRange {
+		return req, apperror.Wrap(apperror.ErrInvalidInput,
+			fmt.Sprintf("range must not exceed %s", maxRange), analytics.ErrInvalidRange)
+	}
+	return req, nil
+}
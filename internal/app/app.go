@@ -1,68 +1,204 @@
 package app
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"net/http"
 	server "task-manager/internal/app/http/v1"
+	"task-manager/migrations"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"go.uber.org/zap"
 
+	analyticsV1 "task-manager/internal/analytics/delivery/http/v1"
+	analyticsRepository "task-manager/internal/analytics/repository"
+	analyticsUseCase "task-manager/internal/analytics/usecase"
+
 	authV1 "task-manager/internal/auth/delivery/http/v1"
 	authRepository "task-manager/internal/auth/repository"
 	authUseCase "task-manager/internal/auth/usecase"
 
+	"task-manager/internal/task"
 	taskV1 "task-manager/internal/task/delivery/http/v1"
+	taskDtos "task-manager/internal/task/dtos"
 	taskRepository "task-manager/internal/task/repository"
 	taskUseCase "task-manager/internal/task/usecase"
+
+	"task-manager/internal/webhook"
+	webhookV1 "task-manager/internal/webhook/delivery/http/v1"
+	webhookRepository "task-manager/internal/webhook/repository"
+	webhookUseCase "task-manager/internal/webhook/usecase"
 	"task-manager/pkg/config"
+	"task-manager/pkg/database/migrate"
 	database "task-manager/pkg/database/postgres"
 	datebaseredis "task-manager/pkg/database/redis"
+	"task-manager/pkg/jwt"
 	"task-manager/pkg/middleware"
+	"task-manager/pkg/notifier"
+	"task-manager/pkg/response"
+	"task-manager/pkg/validation"
 )
 
 type App struct {
-	db     *sql.DB
-	redis  *datebaseredis.Client
-	server *server.Server
-	cfg    *config.Config
-	log    *zap.Logger
-	router *gin.RouterGroup
-	jwt    gin.HandlerFunc
+	db       *sql.DB
+	redis    *datebaseredis.Client
+	server   *server.Server
+	cfg      *config.Config
+	log      *zap.Logger
+	router   *gin.RouterGroup
+	jwt      gin.HandlerFunc
+	denylist *jwt.Denylist
 }
 
 func New(cfg *config.Config, log *zap.Logger) *App {
 	db := database.NewPostgres(cfg)
+	checkSchema(db, log)
 	redis := datebaseredis.New(cfg)
-	jwtMiddleware := middleware.AuthMiddleware(cfg, log)
+	denylist := jwt.NewDenylist(redis)
+	jwtMiddleware := middleware.AuthMiddleware(cfg, denylist, log)
+
+	setupValidation(log)
 
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
-	router.Use(gin.Recovery())
+	router.Use(middleware.RequestID(), gin.Recovery(), middleware.ErrorHandler(log))
+	router.NoRoute(func(c *gin.Context) {
+		response.Abort(c, http.StatusNotFound, "route not found")
+	})
 
 	return &App{
-		db:     db,
-		redis:  redis,
-		cfg:    cfg,
-		log:    log,
-		jwt:    jwtMiddleware,
-		router: router.Group("/"),
-		server: server.New(router, fmt.Sprintf(":%s", cfg.HTTP.Port), log),
+		db:       db,
+		redis:    redis,
+		cfg:      cfg,
+		log:      log,
+		jwt:      jwtMiddleware,
+		denylist: denylist,
+		router:   router.Group("/"),
+		server:   server.New(router, fmt.Sprintf(":%s", cfg.HTTP.Port), log),
+	}
+}
+
+// subtaskRules собирает ограничения иерархии задач из конфигурации
+func subtaskRules(cfg *config.Config, log *zap.Logger) task.SubtaskRules {
+	rules, err := task.NewSubtaskRulesFromConfig(cfg.Task)
+	if err != nil {
+		log.Fatal("Invalid subtask rules", zap.Error(err))
+	}
+	return rules
+}
+
+// newWorkflow создает workflow статусов из конфигурации
+func newWorkflow(cfg *config.Config, log *zap.Logger) *task.Workflow {
+	workflow, err := task.NewWorkflowFromConfig(cfg.Task)
+	if err != nil {
+		log.Fatal("Invalid task workflow", zap.Error(err))
+	}
+	return workflow
+}
+
+// newNotifier создает канал доставки напоминаний из конфигурации
+func newNotifier(cfg *config.Config, log *zap.Logger) notifier.Notifier {
+	n, err := notifier.New(cfg.Notifier, log)
+	if err != nil {
+		log.Fatal("Invalid notifier configuration", zap.Error(err))
+	}
+	return n
+}
+
+// newLiveEventUseCase создает рассылку живых событий задач с бэклогом из конфигурации
+func newLiveEventUseCase(cfg *config.Config, redis *datebaseredis.Client, log *zap.Logger) task.LiveEventUseCase {
+	repo := taskRepository.NewLiveEventRepository(redis, int64(cfg.Live.Backlog), cfg.Live.BacklogTTL, log)
+	return taskUseCase.NewLiveEventUseCase(repo, log)
+}
+
+// setupValidation подключает общий валидатор DTO к gin и к ответам об ошибках
+func setupValidation(log *zap.Logger) {
+	validator := validation.New()
+	if err := taskDtos.RegisterValidators(validator); err != nil {
+		log.Fatal("Failed to register task validators", zap.Error(err))
+	}
+
+	binding.Validator = validator
+	response.SetValidator(validator)
+}
+
+// checkSchema не дает запуститься, если схема БД не совпадает с вшитыми миграциями
+func checkSchema(db *sql.DB, log *zap.Logger) {
+	migrator, err := migrate.New(db, migrations.FS, log)
+	if err != nil {
+		log.Fatal("Failed to load migrations", zap.Error(err))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := migrator.Check(ctx); err != nil {
+		log.Fatal("Database schema check failed, run `migrate up`", zap.Error(err))
 	}
 }
 
 func (a *App) initModules() {
 	// Auth module
 	authRepo := authRepository.NewAuthRepository(a.db, a.log)
-	authUC := authUseCase.NewAuthUsecase(authRepo, a.cfg, a.log)
+	authUC := authUseCase.NewAuthUsecase(authRepo, a.denylist, a.cfg, a.log)
 	authHandler := authV1.NewAuthHandler(authUC, a.log)
-	authHandler.UserRoutes(a.router)
+	authHandler.UserRoutes(a.router, a.jwt)
 
 	// Task module
 	taskRepo := taskRepository.NewRepository(a.db, a.redis, a.log)
-	taskUC := taskUseCase.NewTaskUseCase(taskRepo, a.log)
+	recurrenceRepo := taskRepository.NewRecurrenceRepository(a.db, a.redis, a.log)
+	reminderRepo := taskRepository.NewReminderRepository(a.db, a.redis, a.log)
+	reminderUC := taskUseCase.NewReminderUseCase(reminderRepo, taskRepo, newNotifier(a.cfg, a.log), a.log)
+	webhookRepo := webhookRepository.NewRepository(a.db, a.log)
+	webhookUC := webhookUseCase.NewWebhookUseCase(webhookRepo, webhook.NewClient(a.cfg.Webhook.Timeout), a.log)
+	liveUC := newLiveEventUseCase(a.cfg, a.redis, a.log)
+	taskUC := taskUseCase.NewTaskUseCase(
+		taskRepo,
+		recurrenceRepo,
+		reminderUC,
+		newWorkflow(a.cfg, a.log),
+		subtaskRules(a.cfg, a.log),
+		a.log,
+	)
 	taskHandler := taskV1.NewTaskHandler(taskUC, a.log)
 	taskHandler.TaskRoutes(a.router, a.jwt)
+
+	labelRepo := taskRepository.NewLabelRepository(a.db, a.redis, a.log)
+	labelUC := taskUseCase.NewLabelUseCase(labelRepo, taskRepo, a.log)
+	labelHandler := taskV1.NewLabelHandler(labelUC, a.log)
+	labelHandler.LabelRoutes(a.router, a.jwt)
+
+	checklistRepo := taskRepository.NewChecklistRepository(a.db, a.redis, a.log)
+	checklistUC := taskUseCase.NewChecklistUseCase(checklistRepo, a.log)
+	checklistHandler := taskV1.NewChecklistHandler(checklistUC, a.log)
+	checklistHandler.ChecklistRoutes(a.router, a.jwt)
+
+	dependencyRepo := taskRepository.NewDependencyRepository(a.db, a.redis, a.log)
+	dependencyUC := taskUseCase.NewDependencyUseCase(dependencyRepo, taskRepo, a.log)
+	dependencyHandler := taskV1.NewDependencyHandler(dependencyUC, a.log)
+	dependencyHandler.DependencyRoutes(a.router, a.jwt)
+
+	reminderHandler := taskV1.NewReminderHandler(reminderUC, a.log)
+	reminderHandler.ReminderRoutes(a.router, a.jwt)
+
+	liveHandler := taskV1.NewLiveEventHandler(liveUC, a.cfg.Live.Heartbeat, a.server.Closing(), a.log)
+	liveHandler.LiveEventRoutes(a.router, a.jwt)
+
+	boardHandler := taskV1.NewBoardHandler(taskUC, liveUC, a.denylist, a.cfg.Live.Heartbeat, a.server.Closing(), a.log)
+	boardHandler.BoardRoutes(a.router, a.jwt)
+
+	// Webhook module
+	webhookHandler := webhookV1.NewWebhookHandler(webhookUC, a.log)
+	webhookHandler.WebhookRoutes(a.router, a.jwt)
+
+	// Analytics module
+	analyticsRepo := analyticsRepository.NewRepository(a.db, a.log)
+	analyticsUC := analyticsUseCase.NewAnalyticsUseCase(analyticsRepo, a.log)
+	analyticsHandler := analyticsV1.NewAnalyticsHandler(analyticsUC, a.log)
+	analyticsHandler.AnalyticsRoutes(a.router, a.jwt)
 }
 
 func (a *App) Run() error {
@@ -14,16 +14,28 @@ import (
 type Server struct {
 	httpServer *http.Server
 	logger     *zap.Logger
+	closing    chan struct{}
 }
 
 func New(handler http.Handler, addr string, logger *zap.Logger) *Server {
-	return &Server{
+	s := &Server{
 		httpServer: &http.Server{
 			Addr:    addr,
 			Handler: handler,
 		},
-		logger: logger,
+		logger:  logger,
+		closing: make(chan struct{}),
 	}
+	// Shutdown ждет завершения всех запросов, а стримы сами не завершаются:
+	// закрытие closing просит их отключиться
+	s.httpServer.RegisterOnShutdown(func() { close(s.closing) })
+	return s
+}
+
+// Closing закрывается в начале остановки сервера. Долгие запросы вроде SSE-стримов
+// должны завершаться по нему, иначе остановка упрется в таймаут.
+func (s *Server) Closing() <-chan struct{} {
+	return s.closing
 }
 
 func (s *Server) Start() error {
@@ -0,0 +1,135 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"task-manager/internal/auth/dtos"
+	"task-manager/pkg/apperror"
+	"task-manager/pkg/response"
+)
+
+// ListUsers возвращает список пользователей (для администраторов)
+func (h *AuthHandler) ListUsers(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	users, err := h.uc.ListUsers(c.Request.Context(), limit, offset)
+	if err != nil {
+		h.log.Error("Failed to list users", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
+
+// GetUser возвращает пользователя по ID (для администраторов)
+func (h *AuthHandler) GetUser(c *gin.Context) {
+	userID, ok := h.userIDParam(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.uc.GetUser(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// RevokeUserSessions отзывает все сессии пользователя, не блокируя учетную запись
+func (h *AuthHandler) RevokeUserSessions(c *gin.Context) {
+	userID, ok := h.userIDParam(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.uc.GetUser(c.Request.Context(), userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.uc.LogoutAll(c.Request.Context(), userID); err != nil {
+		h.log.Error("Failed to revoke user sessions",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+		)
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AssignRole назначает пользователю роль
+func (h *AuthHandler) AssignRole(c *gin.Context) {
+	userID, ok := h.userIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.InvalidBody(err))
+		return
+	}
+
+	if err := h.uc.SetUserRole(c.Request.Context(), userID, req.Role); err != nil {
+		h.log.Error("Failed to assign role",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+			zap.String("role", req.Role),
+		)
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DisableUser блокирует учетную запись и отзывает все ее сессии
+func (h *AuthHandler) DisableUser(c *gin.Context) {
+	h.setDisabled(c, true)
+}
+
+// EnableUser снимает блокировку учетной записи
+func (h *AuthHandler) EnableUser(c *gin.Context) {
+	h.setDisabled(c, false)
+}
+
+func (h *AuthHandler) setDisabled(c *gin.Context, disabled bool) {
+	userID, ok := h.userIDParam(c)
+	if !ok {
+		return
+	}
+
+	if disabled && userID == c.GetInt64("user_id") {
+		c.Error(apperror.New(apperror.ErrInvalidInput, "cannot disable own account"))
+		return
+	}
+
+	if err := h.uc.SetUserDisabled(c.Request.Context(), userID, disabled); err != nil {
+		h.log.Error("Failed to change user disabled state",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+			zap.Bool("disabled", disabled),
+		)
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandler) userIDParam(c *gin.Context) (int64, bool) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(apperror.Wrap(apperror.ErrInvalidInput, "invalid user id", err))
+		return 0, false
+	}
+	return userID, true
+}
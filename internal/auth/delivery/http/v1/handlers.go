@@ -1,11 +1,15 @@
 package v1
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	"net/http"
 	"task-manager/internal/auth"
 	"task-manager/internal/auth/dtos"
+	"task-manager/pkg/jwt"
+	"task-manager/pkg/middleware"
+	"task-manager/pkg/response"
 	"time"
 )
 
@@ -34,10 +38,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 			zap.Any("request", req),
 			zap.String("client_ip", c.ClientIP()),
 		)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid input",
-			"details": err.Error(),
-		})
+		c.Error(response.InvalidBody(err))
 		return
 	}
 
@@ -48,10 +49,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 			zap.String("email", req.Email),
 			zap.Duration("duration", time.Since(startTime)),
 		)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Registration failed",
-			"details": err.Error(),
-		})
+		c.Error(err)
 		return
 	}
 
@@ -78,10 +76,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			zap.Error(err),
 			zap.String("client_ip", c.ClientIP()),
 		)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid input",
-			"details": err.Error(),
-		})
+		c.Error(response.InvalidBody(err))
 		return
 	}
 
@@ -92,10 +87,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			zap.String("email", req.Email),
 			zap.Duration("duration", time.Since(startTime)),
 		)
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error":   "Invalid credentials",
-			"details": err.Error(),
-		})
+		c.Error(err)
 		return
 	}
 
@@ -106,3 +98,80 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	startTime := time.Now()
+	ctx := c.Request.Context()
+
+	var req dtos.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid refresh request",
+			zap.Error(err),
+			zap.String("client_ip", c.ClientIP()),
+		)
+		c.Error(response.InvalidBody(err))
+		return
+	}
+
+	response, err := h.uc.Refresh(ctx, req.RefreshToken)
+	if err != nil {
+		h.log.Warn("Refresh failed",
+			zap.Error(err),
+			zap.String("client_ip", c.ClientIP()),
+			zap.Duration("duration", time.Since(startTime)),
+		)
+		c.Error(err)
+		return
+	}
+
+	h.log.Info("Refresh successful",
+		zap.Int64("user_id", response.User.ID),
+		zap.Duration("duration", time.Since(startTime)),
+	)
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	claims, ok := c.MustGet(middleware.ClaimsKey).(*jwt.Claims)
+	if !ok {
+		h.log.Error("Invalid claims type in context")
+		c.Error(errors.New("invalid claims type in context"))
+		return
+	}
+
+	// Тело необязательное: если передан refresh token, отзываем и его семейство
+	var req dtos.LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(response.InvalidBody(err))
+			return
+		}
+	}
+
+	if err := h.uc.Logout(c.Request.Context(), claims, req.RefreshToken); err != nil {
+		h.log.Error("Logout failed",
+			zap.Error(err),
+			zap.Int64("user_id", claims.UserID),
+		)
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+
+	if err := h.uc.LogoutAll(c.Request.Context(), userID); err != nil {
+		h.log.Error("Logout from all sessions failed",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+		)
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -3,13 +3,33 @@ package v1
 import (
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"task-manager/pkg/middleware"
+	"task-manager/pkg/rbac"
 )
 
-func (h *AuthHandler) UserRoutes(router *gin.RouterGroup) {
+func (h *AuthHandler) UserRoutes(router *gin.RouterGroup, auth gin.HandlerFunc) {
 	public := router.Group("/auth")
 	{
 		public.POST("/register", h.Register)
 		public.POST("/login", h.Login)
+		public.POST("/refresh", h.Refresh)
+	}
+
+	protected := router.Group("/auth").Use(auth)
+	{
+		protected.POST("/logout", h.Logout)
+		protected.POST("/logout-all", h.LogoutAll)
+	}
+
+	// Группа администрирования пользователей
+	admin := router.Group("/admin/users").Use(auth)
+	{
+		admin.GET("", middleware.RequirePermission(rbac.PermUsersRead, h.log), h.ListUsers)
+		admin.GET("/:id", middleware.RequirePermission(rbac.PermUsersRead, h.log), h.GetUser)
+		admin.PUT("/:id/role", middleware.RequirePermission(rbac.PermUsersManage, h.log), h.AssignRole)
+		admin.POST("/:id/disable", middleware.RequirePermission(rbac.PermUsersManage, h.log), h.DisableUser)
+		admin.POST("/:id/enable", middleware.RequirePermission(rbac.PermUsersManage, h.log), h.EnableUser)
+		admin.POST("/:id/logout", middleware.RequirePermission(rbac.PermUsersManage, h.log), h.RevokeUserSessions)
 	}
 
 	router.GET("/healthz", func(c *gin.Context) {
@@ -2,12 +2,27 @@ package dtos
 
 // RegisterRequest - запрос на регистрацию
 type RegisterRequest struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=6"`
 }
 
 // LoginRequest - запрос на авторизацию
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=6"`
+}
+
+// RefreshRequest - запрос на обмен refresh token на новую пару токенов
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+// LogoutRequest - необязательный refresh token для отзыва вместе с access token
+type LogoutRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// AssignRoleRequest - запрос администратора на смену роли пользователя
+type AssignRoleRequest struct {
+	Role string `json:"role" validate:"required"`
 }
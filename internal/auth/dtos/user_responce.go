@@ -1,14 +1,21 @@
 package dtos
 
+import "time"
+
 // UserResponse - данные пользователя для ответа API
 type UserResponse struct {
-	ID        int64  `json:"id"`
-	Email     string `json:"email"`
-	CreatedAt string `json:"createdAt"` // Можно форматировать время
+	ID         int64   `json:"id"`
+	Email      string  `json:"email"`
+	Role       string  `json:"role"`
+	DisabledAt *string `json:"disabledAt,omitempty"`
+	CreatedAt  string  `json:"createdAt"` // Можно форматировать время
 }
 
-// LoginResponse - ответ с токеном
+// LoginResponse - ответ с парой токенов и временем их истечения
 type LoginResponse struct {
-	User        UserResponse `json:"user"`
-	AccessToken string       `json:"accessToken"`
+	User                  UserResponse `json:"user"`
+	AccessToken           string       `json:"accessToken"`
+	AccessTokenExpiresAt  time.Time    `json:"accessTokenExpiresAt"`
+	RefreshToken          string       `json:"refreshToken"`
+	RefreshTokenExpiresAt time.Time    `json:"refreshTokenExpiresAt"`
 }
@@ -4,9 +4,16 @@ import "time"
 
 // User - сущность для хранения в БД
 type User struct {
-	ID        int64     `db:"id" json:"id"`
-	Email     string    `db:"email" json:"email"`
-	Password  string    `db:"password" json:"-"` // Хеш пароля (не возвращаем в API)
-	CreatedAt time.Time `db:"created_at" json:"createdAt"`
-	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+	ID         int64      `db:"id" json:"id"`
+	Email      string     `db:"email" json:"email"`
+	Password   string     `db:"password" json:"-"` // Хеш пароля (не возвращаем в API)
+	Role       string     `db:"role" json:"role"`
+	DisabledAt *time.Time `db:"disabled_at" json:"disabledAt,omitempty"`
+	CreatedAt  time.Time  `db:"created_at" json:"createdAt"`
+	UpdatedAt  time.Time  `db:"updated_at" json:"updatedAt"`
+}
+
+// Disabled - учетная запись заблокирована администратором
+func (u *User) Disabled() bool {
+	return u.DisabledAt != nil
 }
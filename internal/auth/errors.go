@@ -0,0 +1,20 @@
+package auth
+
+import "task-manager/pkg/apperror"
+
+var (
+	// ErrInvalidCredentials - неверный email или пароль
+	ErrInvalidCredentials = apperror.New(apperror.ErrUnauthorized, "invalid credentials")
+	// ErrInvalidRefreshToken - refresh token не найден, отозван или истек
+	ErrInvalidRefreshToken = apperror.New(apperror.ErrUnauthorized, "invalid refresh token")
+	// ErrRefreshTokenReused - предъявлен уже ротированный refresh token, семейство отозвано
+	ErrRefreshTokenReused = apperror.New(apperror.ErrUnauthorized, "invalid refresh token")
+	// ErrUserDisabled - учетная запись заблокирована
+	ErrUserDisabled = apperror.New(apperror.ErrForbidden, "account is disabled")
+	// ErrUserNotFound - пользователь не найден
+	ErrUserNotFound = apperror.New(apperror.ErrNotFound, "user not found")
+	// ErrUnknownRole - роль не заведена в БД
+	ErrUnknownRole = apperror.New(apperror.ErrValidation, "unknown role")
+	// ErrEmailTaken - пользователь с таким email уже зарегистрирован
+	ErrEmailTaken = apperror.New(apperror.ErrConflict, "email is already registered")
+)
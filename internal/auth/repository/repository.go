@@ -4,11 +4,34 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 	"task-manager/internal/auth"
 	"task-manager/internal/auth/entity"
+	"time"
 )
 
+// uniqueViolation - код ошибки Postgres при нарушении уникального индекса
+const uniqueViolation = "23505"
+
+const userColumns = "id, email, password, role, disabled_at, created_at, updated_at"
+
+type userScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUser(row userScanner, user *entity.User) error {
+	return row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.Password,
+		&user.Role,
+		&user.DisabledAt,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+}
+
 type authRepository struct {
 	db  *sql.DB
 	log *zap.Logger
@@ -22,7 +45,7 @@ func (r *authRepository) CreateUser(ctx context.Context, user *entity.User) (*en
 	query := `
         INSERT INTO users (email, password)
         VALUES ($1, $2)
-        RETURNING id, created_at, updated_at
+        RETURNING id, role, created_at, updated_at
     `
 
 	r.log.Debug("Создание нового пользователя",
@@ -31,8 +54,15 @@ func (r *authRepository) CreateUser(ctx context.Context, user *entity.User) (*en
 	)
 
 	err := r.db.QueryRowContext(ctx, query, user.Email, user.Password).
-		Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+		Scan(&user.ID, &user.Role, &user.CreatedAt, &user.UpdatedAt)
 
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		r.log.Warn("Email уже зарегистрирован",
+			zap.String("email", user.Email),
+		)
+		return nil, auth.ErrEmailTaken
+	}
 	if err != nil {
 		r.log.Error("Ошибка создания нового пользователя",
 			zap.Error(err),
@@ -54,10 +84,9 @@ func (r *authRepository) GetUserByEmail(ctx context.Context, email string) (*ent
 	)
 
 	var user entity.User
-	query := "SELECT id, email, password, created_at, updated_at FROM users WHERE email = $1"
+	query := "SELECT " + userColumns + " FROM users WHERE email = $1"
 
-	err := r.db.QueryRowContext(ctx, query, email).
-		Scan(&user.ID, &user.Email, &user.Password, &user.CreatedAt, &user.UpdatedAt)
+	err := scanUser(r.db.QueryRowContext(ctx, query, email), &user)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -79,3 +108,297 @@ func (r *authRepository) GetUserByEmail(ctx context.Context, email string) (*ent
 	)
 	return &user, nil
 }
+
+func (r *authRepository) GetUserByID(ctx context.Context, id int64) (*entity.User, error) {
+	r.log.Debug("Поиск пользователя по ID",
+		zap.Int64("user_id", id),
+		zap.String("operation", "GetUserByID"),
+	)
+
+	var user entity.User
+	query := "SELECT " + userColumns + " FROM users WHERE id = $1"
+
+	err := scanUser(r.db.QueryRowContext(ctx, query, id), &user)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			r.log.Warn("Пользователь не найден",
+				zap.Int64("user_id", id),
+			)
+			return nil, nil
+		}
+
+		r.log.Error("Ошибка поиска пользователя",
+			zap.Error(err),
+			zap.Int64("user_id", id),
+		)
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (r *authRepository) CreateRefreshToken(ctx context.Context, token *entity.RefreshToken) error {
+	query := `
+        INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at
+    `
+
+	err := r.db.QueryRowContext(ctx, query, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt).
+		Scan(&token.ID, &token.CreatedAt)
+	if err != nil {
+		r.log.Error("Ошибка сохранения refresh token",
+			zap.Error(err),
+			zap.Int64("user_id", token.UserID),
+		)
+		return err
+	}
+
+	r.log.Debug("Refresh token сохранен",
+		zap.Int64("user_id", token.UserID),
+		zap.String("family_id", token.FamilyID.String()),
+	)
+	return nil
+}
+
+func (r *authRepository) RotateRefreshToken(ctx context.Context, oldHash string, next *entity.RefreshToken) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var current entity.RefreshToken
+	query := `
+        SELECT id, user_id, family_id, expires_at, rotated_at, revoked_at
+        FROM refresh_tokens
+        WHERE token_hash = $1
+        FOR UPDATE
+    `
+	err = tx.QueryRowContext(ctx, query, oldHash).Scan(
+		&current.ID,
+		&current.UserID,
+		&current.FamilyID,
+		&current.ExpiresAt,
+		&current.RotatedAt,
+		&current.RevokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			r.log.Warn("Refresh token не найден")
+			return auth.ErrInvalidRefreshToken
+		}
+		r.log.Error("Ошибка поиска refresh token", zap.Error(err))
+		return err
+	}
+
+	if current.RevokedAt != nil {
+		r.log.Warn("Предъявлен отозванный refresh token",
+			zap.Int64("user_id", current.UserID),
+			zap.String("family_id", current.FamilyID.String()),
+		)
+		return auth.ErrInvalidRefreshToken
+	}
+
+	now := time.Now()
+	if current.RotatedAt != nil {
+		// Токен уже обменивали - скорее всего, он украден. Отзываем все семейство.
+		_, err = tx.ExecContext(ctx,
+			"UPDATE refresh_tokens SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL",
+			now, current.FamilyID,
+		)
+		if err != nil {
+			r.log.Error("Ошибка отзыва семейства refresh token", zap.Error(err))
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+
+		r.log.Warn("Повторное использование refresh token, семейство отозвано",
+			zap.Int64("user_id", current.UserID),
+			zap.String("family_id", current.FamilyID.String()),
+		)
+		return auth.ErrRefreshTokenReused
+	}
+
+	if !current.ExpiresAt.After(now) {
+		r.log.Warn("Refresh token истек",
+			zap.Int64("user_id", current.UserID),
+		)
+		return auth.ErrInvalidRefreshToken
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE refresh_tokens SET rotated_at = $1 WHERE id = $2", now, current.ID,
+	); err != nil {
+		r.log.Error("Ошибка ротации refresh token", zap.Error(err))
+		return err
+	}
+
+	next.UserID = current.UserID
+	next.FamilyID = current.FamilyID
+	err = tx.QueryRowContext(ctx, `
+        INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at
+    `, next.UserID, next.FamilyID, next.TokenHash, next.ExpiresAt).Scan(&next.ID, &next.CreatedAt)
+	if err != nil {
+		r.log.Error("Ошибка сохранения нового refresh token", zap.Error(err))
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	r.log.Debug("Refresh token ротирован",
+		zap.Int64("user_id", next.UserID),
+		zap.String("family_id", next.FamilyID.String()),
+	)
+	return nil
+}
+
+func (r *authRepository) RevokeRefreshTokenFamily(ctx context.Context, userID int64, hash string) error {
+	query := `
+        UPDATE refresh_tokens SET revoked_at = NOW()
+        WHERE revoked_at IS NULL AND family_id = (
+            SELECT family_id FROM refresh_tokens WHERE token_hash = $1 AND user_id = $2
+        )
+    `
+
+	if _, err := r.db.ExecContext(ctx, query, hash, userID); err != nil {
+		r.log.Error("Ошибка отзыва семейства refresh token",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+		)
+		return err
+	}
+	return nil
+}
+
+func (r *authRepository) RevokeUserRefreshTokens(ctx context.Context, userID int64) error {
+	query := "UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL"
+
+	result, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		r.log.Error("Ошибка отзыва refresh token пользователя",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+		)
+		return err
+	}
+
+	revoked, _ := result.RowsAffected()
+	r.log.Info("Refresh token пользователя отозваны",
+		zap.Int64("user_id", userID),
+		zap.Int64("revoked", revoked),
+	)
+	return nil
+}
+
+func (r *authRepository) GetRolePermissions(ctx context.Context, role string) ([]string, error) {
+	query := "SELECT permission FROM role_permissions WHERE role = $1 ORDER BY permission"
+
+	rows, err := r.db.QueryContext(ctx, query, role)
+	if err != nil {
+		r.log.Error("Ошибка получения прав роли",
+			zap.Error(err),
+			zap.String("role", role),
+		)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var permissions []string
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+	return permissions, rows.Err()
+}
+
+func (r *authRepository) ListUsers(ctx context.Context, limit, offset int) ([]*entity.User, error) {
+	query := "SELECT " + userColumns + " FROM users ORDER BY id LIMIT $1 OFFSET $2"
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		r.log.Error("Ошибка получения списка пользователей", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*entity.User
+	for rows.Next() {
+		var user entity.User
+		if err := scanUser(rows, &user); err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+	return users, rows.Err()
+}
+
+func (r *authRepository) SetUserRole(ctx context.Context, id int64, role string) error {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM roles WHERE name = $1)", role,
+	).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		r.log.Warn("Роль не найдена", zap.String("role", role))
+		return auth.ErrUnknownRole
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE users SET role = $1, updated_at = NOW() WHERE id = $2", role, id,
+	)
+	if err != nil {
+		r.log.Error("Ошибка назначения роли",
+			zap.Error(err),
+			zap.Int64("user_id", id),
+		)
+		return err
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return auth.ErrUserNotFound
+	}
+
+	r.log.Info("Роль пользователя изменена",
+		zap.Int64("user_id", id),
+		zap.String("role", role),
+	)
+	return nil
+}
+
+func (r *authRepository) SetUserDisabled(ctx context.Context, id int64, disabled bool) error {
+	query := "UPDATE users SET disabled_at = NULL, updated_at = NOW() WHERE id = $1"
+	if disabled {
+		query = "UPDATE users SET disabled_at = COALESCE(disabled_at, NOW()), updated_at = NOW() WHERE id = $1"
+	}
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		r.log.Error("Ошибка блокировки пользователя",
+			zap.Error(err),
+			zap.Int64("user_id", id),
+		)
+		return err
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return auth.ErrUserNotFound
+	}
+
+	r.log.Info("Статус учетной записи изменен",
+		zap.Int64("user_id", id),
+		zap.Bool("disabled", disabled),
+	)
+	return nil
+}
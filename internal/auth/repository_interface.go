@@ -8,4 +8,17 @@ import (
 type AuthRepository interface {
 	CreateUser(ctx context.Context, user *entity.User) (*entity.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*entity.User, error)
+	GetUserByID(ctx context.Context, id int64) (*entity.User, error)
+	GetRolePermissions(ctx context.Context, role string) ([]string, error)
+	ListUsers(ctx context.Context, limit, offset int) ([]*entity.User, error)
+	SetUserRole(ctx context.Context, id int64, role string) error
+	SetUserDisabled(ctx context.Context, id int64, disabled bool) error
+
+	CreateRefreshToken(ctx context.Context, token *entity.RefreshToken) error
+	// RotateRefreshToken помечает токен с хешем oldHash ротированным и сохраняет next
+	// в том же семействе. При повторном предъявлении ротированного токена отзывает
+	// все семейство и возвращает ErrRefreshTokenReused.
+	RotateRefreshToken(ctx context.Context, oldHash string, next *entity.RefreshToken) error
+	RevokeRefreshTokenFamily(ctx context.Context, userID int64, hash string) error
+	RevokeUserRefreshTokens(ctx context.Context, userID int64) error
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 	"task-manager/internal/auth"
@@ -15,13 +16,14 @@ import (
 )
 
 type authUsecase struct {
-	repo auth.AuthRepository
-	cfg  *config.Config
-	log  *zap.Logger
+	repo     auth.AuthRepository
+	denylist *jwt.Denylist
+	cfg      *config.Config
+	log      *zap.Logger
 }
 
-func NewAuthUsecase(repo auth.AuthRepository, cfg *config.Config, log *zap.Logger) auth.AuthUsecase {
-	return &authUsecase{repo, cfg, log}
+func NewAuthUsecase(repo auth.AuthRepository, denylist *jwt.Denylist, cfg *config.Config, log *zap.Logger) auth.AuthUsecase {
+	return &authUsecase{repo, denylist, cfg, log}
 }
 
 func (uc *authUsecase) Register(ctx context.Context, email, password string) (*dtos.UserResponse, error) {
@@ -60,11 +62,8 @@ func (uc *authUsecase) Register(ctx context.Context, email, password string) (*d
 		zap.Duration("duration", time.Since(startTime)),
 	)
 
-	return &dtos.UserResponse{
-		ID:        createdUser.ID,
-		Email:     createdUser.Email,
-		CreatedAt: createdUser.CreatedAt.Format(time.RFC3339),
-	}, nil
+	response := toUserResponse(createdUser)
+	return &response, nil
 }
 
 func (uc *authUsecase) Login(ctx context.Context, email, password string) (*dtos.LoginResponse, error) {
@@ -87,7 +86,7 @@ func (uc *authUsecase) Login(ctx context.Context, email, password string) (*dtos
 		uc.log.Warn("User not found",
 			zap.String("email", email),
 		)
-		return nil, errors.New("invalid credentials")
+		return nil, auth.ErrInvalidCredentials
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
@@ -95,10 +94,73 @@ func (uc *authUsecase) Login(ctx context.Context, email, password string) (*dtos
 			zap.String("email", email),
 			zap.Error(err),
 		)
-		return nil, errors.New("invalid credentials")
+		return nil, auth.ErrInvalidCredentials
+	}
+
+	if user.Disabled() {
+		uc.log.Warn("Login attempt for disabled user",
+			zap.Int64("user_id", user.ID),
+		)
+		return nil, auth.ErrUserDisabled
+	}
+
+	response, err := uc.issueTokens(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.log.Info("User successfully logged in",
+		zap.Int64("user_id", user.ID),
+		zap.String("email", user.Email),
+		zap.Duration("duration", time.Since(startTime)),
+	)
+
+	return response, nil
+}
+
+func (uc *authUsecase) Refresh(ctx context.Context, refreshToken string) (*dtos.LoginResponse, error) {
+	uc.log.Info("Starting token refresh",
+		zap.String("operation", "Refresh"),
+	)
+
+	token, hash, err := jwt.NewRefreshToken()
+	if err != nil {
+		uc.log.Error("Failed to generate refresh token", zap.Error(err))
+		return nil, err
+	}
+
+	next := &entity.RefreshToken{
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(uc.cfg.JWT.RefreshTTL),
+	}
+	if err := uc.repo.RotateRefreshToken(ctx, jwt.HashRefreshToken(refreshToken), next); err != nil {
+		if errors.Is(err, auth.ErrInvalidRefreshToken) || errors.Is(err, auth.ErrRefreshTokenReused) {
+			uc.log.Warn("Refresh token rejected", zap.Error(err))
+			return nil, err
+		}
+		uc.log.Error("Failed to rotate refresh token", zap.Error(err))
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+
+	user, err := uc.repo.GetUserByID(ctx, next.UserID)
+	if err != nil {
+		uc.log.Error("Failed to get user from repository",
+			zap.Error(err),
+			zap.Int64("user_id", next.UserID),
+		)
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	if user == nil {
+		return nil, auth.ErrInvalidRefreshToken
+	}
+	if user.Disabled() {
+		uc.log.Warn("Refresh attempt for disabled user",
+			zap.Int64("user_id", user.ID),
+		)
+		return nil, auth.ErrUserDisabled
 	}
 
-	token, err := jwt.GenerateToken(user.ID, uc.cfg)
+	accessToken, accessExpiresAt, err := uc.generateAccessToken(ctx, user)
 	if err != nil {
 		uc.log.Error("Failed to generate JWT token",
 			zap.Error(err),
@@ -107,19 +169,236 @@ func (uc *authUsecase) Login(ctx context.Context, email, password string) (*dtos
 		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 
-	uc.log.Info("User successfully logged in",
+	uc.log.Info("Tokens refreshed",
 		zap.Int64("user_id", user.ID),
-		zap.String("email", user.Email),
-		zap.String("accessToken", token),
-		zap.Duration("duration", time.Since(startTime)),
+		zap.String("family_id", next.FamilyID.String()),
 	)
 
 	return &dtos.LoginResponse{
-		User: dtos.UserResponse{
-			ID:        user.ID,
-			Email:     user.Email,
-			CreatedAt: user.CreatedAt.Format(time.RFC3339),
-		},
-		AccessToken: token,
+		User:                  toUserResponse(user),
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessExpiresAt,
+		RefreshToken:          token,
+		RefreshTokenExpiresAt: next.ExpiresAt,
 	}, nil
 }
+
+func (uc *authUsecase) Logout(ctx context.Context, claims *jwt.Claims, refreshToken string) error {
+	uc.log.Info("Starting user logout",
+		zap.Int64("user_id", claims.UserID),
+		zap.String("operation", "Logout"),
+	)
+
+	if err := uc.denylist.Revoke(ctx, claims); err != nil {
+		uc.log.Error("Failed to revoke access token",
+			zap.Error(err),
+			zap.Int64("user_id", claims.UserID),
+		)
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	if refreshToken != "" {
+		if err := uc.repo.RevokeRefreshTokenFamily(ctx, claims.UserID, jwt.HashRefreshToken(refreshToken)); err != nil {
+			return fmt.Errorf("repository error: %w", err)
+		}
+	}
+
+	uc.log.Info("User logged out",
+		zap.Int64("user_id", claims.UserID),
+		zap.String("jti", claims.ID),
+	)
+	return nil
+}
+
+func (uc *authUsecase) LogoutAll(ctx context.Context, userID int64) error {
+	uc.log.Info("Starting logout from all sessions",
+		zap.Int64("user_id", userID),
+		zap.String("operation", "LogoutAll"),
+	)
+
+	generation, err := uc.denylist.BumpGeneration(ctx, userID)
+	if err != nil {
+		uc.log.Error("Failed to bump token generation",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+		)
+		return fmt.Errorf("failed to revoke tokens: %w", err)
+	}
+
+	if err := uc.repo.RevokeUserRefreshTokens(ctx, userID); err != nil {
+		return fmt.Errorf("repository error: %w", err)
+	}
+
+	uc.log.Info("User logged out from all sessions",
+		zap.Int64("user_id", userID),
+		zap.Int64("generation", generation),
+	)
+	return nil
+}
+
+// generateAccessToken выпускает access token с ролью, правами и текущим поколением токенов пользователя
+func (uc *authUsecase) generateAccessToken(ctx context.Context, user *entity.User) (string, time.Time, error) {
+	permissions, err := uc.repo.GetRolePermissions(ctx, user.Role)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to get role permissions: %w", err)
+	}
+
+	generation, err := uc.denylist.Generation(ctx, user.ID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return jwt.GenerateToken(jwt.Subject{
+		UserID:      user.ID,
+		Role:        user.Role,
+		Permissions: permissions,
+		Generation:  generation,
+	}, uc.cfg)
+}
+
+// issueTokens выпускает access token и refresh token нового семейства
+func (uc *authUsecase) issueTokens(ctx context.Context, user *entity.User) (*dtos.LoginResponse, error) {
+	accessToken, accessExpiresAt, err := uc.generateAccessToken(ctx, user)
+	if err != nil {
+		uc.log.Error("Failed to generate JWT token",
+			zap.Error(err),
+			zap.Int64("user_id", user.ID),
+		)
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	refreshToken, hash, err := jwt.NewRefreshToken()
+	if err != nil {
+		uc.log.Error("Failed to generate refresh token", zap.Error(err))
+		return nil, err
+	}
+
+	stored := &entity.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  uuid.New(),
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(uc.cfg.JWT.RefreshTTL),
+	}
+	if err := uc.repo.CreateRefreshToken(ctx, stored); err != nil {
+		uc.log.Error("Failed to store refresh token",
+			zap.Error(err),
+			zap.Int64("user_id", user.ID),
+		)
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+
+	return &dtos.LoginResponse{
+		User:                  toUserResponse(user),
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: stored.ExpiresAt,
+	}, nil
+}
+
+func (uc *authUsecase) ListUsers(ctx context.Context, limit, offset int) ([]dtos.UserResponse, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	users, err := uc.repo.ListUsers(ctx, limit, offset)
+	if err != nil {
+		uc.log.Error("Failed to list users", zap.Error(err))
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+
+	response := make([]dtos.UserResponse, 0, len(users))
+	for _, user := range users {
+		response = append(response, toUserResponse(user))
+	}
+	return response, nil
+}
+
+func (uc *authUsecase) GetUser(ctx context.Context, userID int64) (*dtos.UserResponse, error) {
+	user, err := uc.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	if user == nil {
+		return nil, auth.ErrUserNotFound
+	}
+
+	response := toUserResponse(user)
+	return &response, nil
+}
+
+// GrantRole назначает роль пользователю с указанным email. Используется CLI
+// для назначения первого администратора, когда в системе еще некому это сделать.
+func (uc *authUsecase) GrantRole(ctx context.Context, email, role string) (*dtos.UserResponse, error) {
+	user, err := uc.repo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+	if user == nil {
+		return nil, auth.ErrUserNotFound
+	}
+
+	if err := uc.SetUserRole(ctx, user.ID, role); err != nil {
+		return nil, err
+	}
+	user.Role = role
+
+	response := toUserResponse(user)
+	return &response, nil
+}
+
+// SetUserRole меняет роль и отзывает выпущенные токены, чтобы новые права вступили в силу сразу
+func (uc *authUsecase) SetUserRole(ctx context.Context, userID int64, role string) error {
+	uc.log.Info("Changing user role",
+		zap.Int64("user_id", userID),
+		zap.String("role", role),
+		zap.String("operation", "SetUserRole"),
+	)
+
+	if err := uc.repo.SetUserRole(ctx, userID, role); err != nil {
+		if errors.Is(err, auth.ErrUnknownRole) || errors.Is(err, auth.ErrUserNotFound) {
+			return err
+		}
+		return fmt.Errorf("repository error: %w", err)
+	}
+
+	if _, err := uc.denylist.BumpGeneration(ctx, userID); err != nil {
+		return fmt.Errorf("failed to revoke tokens: %w", err)
+	}
+	return nil
+}
+
+// SetUserDisabled блокирует или разблокирует учетную запись; при блокировке отзывает все сессии
+func (uc *authUsecase) SetUserDisabled(ctx context.Context, userID int64, disabled bool) error {
+	uc.log.Info("Changing user disabled state",
+		zap.Int64("user_id", userID),
+		zap.Bool("disabled", disabled),
+		zap.String("operation", "SetUserDisabled"),
+	)
+
+	if err := uc.repo.SetUserDisabled(ctx, userID, disabled); err != nil {
+		if errors.Is(err, auth.ErrUserNotFound) {
+			return err
+		}
+		return fmt.Errorf("repository error: %w", err)
+	}
+
+	if disabled {
+		return uc.LogoutAll(ctx, userID)
+	}
+	return nil
+}
+
+func toUserResponse(user *entity.User) dtos.UserResponse {
+	response := dtos.UserResponse{
+		ID:        user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt.Format(time.RFC3339),
+	}
+	if user.DisabledAt != nil {
+		disabledAt := user.DisabledAt.Format(time.RFC3339)
+		response.DisabledAt = &disabledAt
+	}
+	return response
+}
@@ -3,9 +3,19 @@ package auth
 import (
 	"context"
 	"task-manager/internal/auth/dtos"
+	"task-manager/pkg/jwt"
 )
 
 type AuthUsecase interface {
 	Register(ctx context.Context, email, password string) (*dtos.UserResponse, error)
 	Login(ctx context.Context, email, password string) (*dtos.LoginResponse, error)
+	Refresh(ctx context.Context, refreshToken string) (*dtos.LoginResponse, error)
+	Logout(ctx context.Context, claims *jwt.Claims, refreshToken string) error
+	LogoutAll(ctx context.Context, userID int64) error
+
+	ListUsers(ctx context.Context, limit, offset int) ([]dtos.UserResponse, error)
+	GetUser(ctx context.Context, userID int64) (*dtos.UserResponse, error)
+	GrantRole(ctx context.Context, email, role string) (*dtos.UserResponse, error)
+	SetUserRole(ctx context.Context, userID int64, role string) error
+	SetUserDisabled(ctx context.Context, userID int64, disabled bool) error
 }
@@ -0,0 +1,31 @@
+package task
+
+import (
+	"fmt"
+	"task-manager/internal/task/entity"
+	"task-manager/pkg/config"
+)
+
+// NewWorkflowFromConfig строит workflow из таблицы переходов в конфигурации
+func NewWorkflowFromConfig(cfg config.Task) (*Workflow, error) {
+	transitions, err := ParseTransitions(cfg.Workflow)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TASK_WORKFLOW: %w", err)
+	}
+	return NewWorkflow(transitions), nil
+}
+
+// NewSubtaskRulesFromConfig строит ограничения иерархии задач из конфигурации
+func NewSubtaskRulesFromConfig(cfg config.Task) (SubtaskRules, error) {
+	rules := SubtaskRules{
+		MaxDepth:       cfg.MaxSubtaskDepth,
+		OnParentDelete: entity.ParentDeletePolicy(cfg.ParentDeletePolicy),
+	}
+	if !rules.OnParentDelete.Valid() {
+		return SubtaskRules{}, fmt.Errorf("invalid TASK_PARENT_DELETE_POLICY: %q", cfg.ParentDeletePolicy)
+	}
+	if rules.MaxDepth < 0 {
+		return SubtaskRules{}, fmt.Errorf("invalid TASK_MAX_SUBTASK_DEPTH: %d", cfg.MaxSubtaskDepth)
+	}
+	return rules, nil
+}
@@ -0,0 +1,398 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"sync"
+	"task-manager/internal/task"
+	"task-manager/internal/task/dtos"
+	"task-manager/internal/task/entity"
+	"task-manager/pkg/jwt"
+	"task-manager/pkg/middleware"
+	"task-manager/pkg/response"
+	"time"
+)
+
+const (
+	// boardWriteTimeout - сколько ждать записи одного сообщения клиенту
+	boardWriteTimeout = 10 * time.Second
+	// boardCommandTimeout - сколько может выполняться одна команда клиента
+	boardCommandTimeout = 10 * time.Second
+	// boardMaxMessage - предельный размер команды клиента в байтах
+	boardMaxMessage = 64 << 10
+	// boardQueue - сколько сообщений может ждать отправки медленному клиенту
+	boardQueue = 64
+	// boardCloseUnauthorized - код закрытия, когда токен соединения истек или отозван:
+	// клиент обновляет токен и подключается заново
+	boardCloseUnauthorized = 4401
+)
+
+// Каналы доски: все задачи пользователя или одна задача (task:<id>)
+const (
+	boardChannelTasks      = "tasks"
+	boardChannelTaskPrefix = "task:"
+)
+
+type BoardHandler struct {
+	tasks    task.TaskUseCase
+	live     task.LiveEventUseCase
+	denylist *jwt.Denylist
+	upgrader websocket.Upgrader
+	ping     time.Duration
+	closing  <-chan struct{}
+	log      *zap.Logger
+}
+
+// NewBoardHandler раз в ping проверяет, что клиент жив: соединение без ответа дольше
+// двух интервалов закрывается. Закрытие closing завершает все открытые соединения.
+// По denylist токен соединения перепроверяется перед каждой командой, меняющей задачи.
+func NewBoardHandler(
+	tasks task.TaskUseCase,
+	live task.LiveEventUseCase,
+	denylist *jwt.Denylist,
+	ping time.Duration,
+	closing <-chan struct{},
+	log *zap.Logger,
+) *BoardHandler {
+	return &BoardHandler{
+		tasks:    tasks,
+		live:     live,
+		denylist: denylist,
+		upgrader: websocket.Upgrader{
+			Subprotocols: []string{middleware.WebSocketTokenProtocol},
+		},
+		ping:    ping,
+		closing: closing,
+		log:     log.Named("board_handler"),
+	}
+}
+
+// Connect открывает WebSocket доски задач текущего пользователя. Клиент подписывается
+// на каналы и шлет команды; изменения задач приходят всем подписчикам через Redis.
+// Соединение живет не дольше access-токена: по его истечении оно закрывается с кодом 4401.
+func (h *BoardHandler) Connect(c *gin.Context) {
+	claims, ok := c.Value(middleware.ClaimsKey).(*jwt.Claims)
+	if !ok {
+		h.log.Error("Board connection without authenticated claims")
+		response.Abort(c, http.StatusUnauthorized, "missing or invalid access token")
+		return
+	}
+
+	// Подписка оформляется до апгрейда, пока ошибку можно вернуть обычным ответом
+	sub, err := h.live.Subscribe(c.Request.Context(), "")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer sub.Close()
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade уже ответил клиенту ошибкой
+		h.log.Debug("WebSocket upgrade failed", zap.Error(err))
+		return
+	}
+
+	s := &boardSession{
+		h:        h,
+		c:        c,
+		conn:     conn,
+		claims:   claims,
+		out:      make(chan dtos.BoardMessage, boardQueue),
+		done:     make(chan struct{}),
+		written:  make(chan struct{}),
+		channels: make(map[string]struct{}),
+	}
+	go s.write()
+	go s.forward(sub)
+	s.read()
+	s.stop()
+	<-s.written
+}
+
+// boardSession - одно WebSocket-соединение доски. Команды выполняются по очереди
+// в read, в соединение пишет только write.
+type boardSession struct {
+	h       *BoardHandler
+	c       *gin.Context
+	conn    *websocket.Conn
+	claims  *jwt.Claims
+	out     chan dtos.BoardMessage
+	done    chan struct{}
+	written chan struct{}
+	once    sync.Once
+
+	// closeCode и closeReason записываются до закрытия done и читаются после
+	closeCode   int
+	closeReason string
+
+	mu       sync.Mutex
+	channels map[string]struct{}
+}
+
+func (s *boardSession) stop() {
+	s.terminate(websocket.CloseNormalClosure, "")
+}
+
+// terminate завершает сессию; write закроет соединение с кодом code
+func (s *boardSession) terminate(code int, reason string) {
+	s.once.Do(func() {
+		s.closeCode, s.closeReason = code, reason
+		close(s.done)
+	})
+}
+
+// expired сообщает, что access-токен соединения истек
+func (s *boardSession) expired() <-chan time.Time {
+	if s.claims.ExpiresAt == nil {
+		return nil
+	}
+	return time.After(time.Until(s.claims.ExpiresAt.Time))
+}
+
+// send ставит сообщение в очередь отправки. Клиент, который не успевает читать,
+// отключается: после переподключения он перечитает доску.
+func (s *boardSession) send(msg dtos.BoardMessage) {
+	select {
+	case s.out <- msg:
+	case <-s.done:
+	default:
+		s.h.log.Warn("Board client is too slow, disconnecting")
+		s.stop()
+	}
+}
+
+func (s *boardSession) read() {
+	wait := 2 * s.h.ping
+	s.conn.SetReadLimit(boardMaxMessage)
+	s.conn.SetReadDeadline(time.Now().Add(wait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(wait))
+	})
+
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				s.h.log.Debug("Board connection closed", zap.Error(err))
+			}
+			return
+		}
+		s.conn.SetReadDeadline(time.Now().Add(wait))
+		s.handle(data)
+	}
+}
+
+func (s *boardSession) write() {
+	defer close(s.written)
+	defer s.conn.Close()
+
+	ping := time.NewTicker(s.h.ping)
+	defer ping.Stop()
+	expired := s.expired()
+
+	for {
+		select {
+		case msg := <-s.out:
+			s.conn.SetWriteDeadline(time.Now().Add(boardWriteTimeout))
+			if err := s.conn.WriteJSON(msg); err != nil {
+				s.stop()
+				return
+			}
+		case <-ping.C:
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(boardWriteTimeout)); err != nil {
+				s.stop()
+				return
+			}
+		case <-expired:
+			s.terminate(boardCloseUnauthorized, "token expired")
+		case <-s.h.closing:
+			s.close(websocket.CloseGoingAway, "server is shutting down")
+			s.stop()
+			return
+		case <-s.done:
+			s.close(s.closeCode, s.closeReason)
+			return
+		}
+	}
+}
+
+func (s *boardSession) close(code int, reason string) {
+	message := websocket.FormatCloseMessage(code, reason)
+	_ = s.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(boardWriteTimeout))
+}
+
+// forward рассылает события задач по каналам, на которые подписан клиент
+func (s *boardSession) forward(sub task.LiveSubscription) {
+	for event := range sub.Events() {
+		for _, channel := range s.matching(event) {
+			s.send(dtos.BoardMessage{
+				Type:    dtos.BoardEvent,
+				Channel: channel,
+				EventID: event.ID,
+				Event:   event.Data,
+			})
+		}
+	}
+	// Подписка на Redis оборвалась: клиент переподключится и получит свежую доску
+	s.stop()
+}
+
+func (s *boardSession) matching(event entity.LiveEvent) []string {
+	var payload struct {
+		TaskID uuid.UUID `json:"task_id"`
+	}
+	_ = json.Unmarshal(event.Data, &payload)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var channels []string
+	if _, ok := s.channels[boardChannelTasks]; ok {
+		channels = append(channels, boardChannelTasks)
+	}
+	if channel := boardChannelTaskPrefix + payload.TaskID.String(); payload.TaskID != uuid.Nil {
+		if _, ok := s.channels[channel]; ok {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// handle выполняет команду и отвечает на нее ack или error
+func (s *boardSession) handle(data []byte) {
+	var cmd dtos.BoardCommand
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		s.fail("", response.InvalidBody(err))
+		return
+	}
+	if err := binding.Validator.ValidateStruct(&cmd); err != nil {
+		s.fail(cmd.ID, response.InvalidBody(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(s.c.Request.Context(), boardCommandTimeout)
+	defer cancel()
+
+	ack := dtos.BoardMessage{Type: dtos.BoardAck, ID: cmd.ID}
+	var err error
+	switch cmd.Type {
+	case dtos.BoardSubscribe:
+		ack.Channel, err = s.subscribe(ctx, cmd.Channel)
+	case dtos.BoardUnsubscribe:
+		ack.Channel, err = s.unsubscribe(cmd.Channel)
+	case dtos.BoardMoveTask, dtos.BoardUpdateTask, dtos.BoardReopenTask:
+		if !s.authorized(ctx, cmd.ID) {
+			return
+		}
+		var t *entity.Task
+		switch cmd.Type {
+		case dtos.BoardMoveTask:
+			t, err = s.h.tasks.UpdateTask(ctx, cmd.TaskID, &dtos.UpdateTaskRequest{Status: &cmd.Status})
+		case dtos.BoardUpdateTask:
+			t, err = s.h.tasks.UpdateTask(ctx, cmd.TaskID, cmd.Changes)
+		case dtos.BoardReopenTask:
+			t, err = s.h.tasks.ReopenTask(ctx, cmd.TaskID)
+		}
+		if err == nil {
+			resp := dtos.ToTaskResponse(*t)
+			ack.Task = &resp
+		}
+	}
+	if err != nil {
+		s.fail(cmd.ID, err)
+		return
+	}
+	s.send(ack)
+}
+
+// authorized перепроверяет токен перед командой, меняющей задачи: за время жизни
+// соединения его могли отозвать (выход, смена пароля). С истекшим или отозванным
+// токеном соединение закрывается с кодом 4401.
+func (s *boardSession) authorized(ctx context.Context, id string) bool {
+	if s.claims.ExpiresAt != nil && !time.Now().Before(s.claims.ExpiresAt.Time) {
+		s.terminate(boardCloseUnauthorized, "token expired")
+		return false
+	}
+
+	revoked, err := s.h.denylist.IsRevoked(ctx, s.claims)
+	if err != nil {
+		s.fail(id, err)
+		return false
+	}
+	if revoked {
+		s.h.log.Warn("Revoked token used on board connection",
+			zap.Int64("user_id", s.claims.UserID),
+			zap.String("jti", s.claims.ID),
+		)
+		s.terminate(boardCloseUnauthorized, "token revoked")
+		return false
+	}
+	return true
+}
+
+func (s *boardSession) fail(id string, err error) {
+	problem := response.FromError(s.c, err)
+	if problem.Status >= http.StatusInternalServerError {
+		s.h.log.Error("Board command failed",
+			zap.Error(err),
+			zap.String("request_id", s.c.GetString(response.RequestIDKey)),
+		)
+	}
+	s.send(dtos.BoardMessage{Type: dtos.BoardError, ID: id, Error: &problem})
+}
+
+// subscribe подписывает клиента на канал. Подписка на задачу проверяет, что задача
+// принадлежит пользователю.
+func (s *boardSession) subscribe(ctx context.Context, name string) (string, error) {
+	channel, taskID, err := boardChannel(name)
+	if err != nil {
+		return "", err
+	}
+	if taskID != "" {
+		if _, err := s.h.tasks.GetTask(ctx, taskID); err != nil {
+			return "", err
+		}
+	}
+
+	s.mu.Lock()
+	s.channels[channel] = struct{}{}
+	s.mu.Unlock()
+	return channel, nil
+}
+
+func (s *boardSession) unsubscribe(name string) (string, error) {
+	channel, _, err := boardChannel(name)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	delete(s.channels, channel)
+	s.mu.Unlock()
+	return channel, nil
+}
+
+// boardChannel приводит имя канала к каноническому виду и для канала задачи
+// возвращает ее ID
+func boardChannel(name string) (channel string, taskID string, err error) {
+	if name == boardChannelTasks {
+		return name, "", nil
+	}
+
+	if id, ok := strings.CutPrefix(name, boardChannelTaskPrefix); ok {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return "", "", task.ErrNotFound
+		}
+		return boardChannelTaskPrefix + parsed.String(), parsed.String(), nil
+	}
+	return "", "", task.ErrUnknownChannel
+}
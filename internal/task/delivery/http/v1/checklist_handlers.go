@@ -0,0 +1,134 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"task-manager/internal/task"
+	"task-manager/internal/task/dtos"
+	"task-manager/pkg/response"
+)
+
+type ChecklistHandler struct {
+	uc  task.ChecklistUseCase
+	log *zap.Logger
+}
+
+func NewChecklistHandler(uc task.ChecklistUseCase, log *zap.Logger) *ChecklistHandler {
+	return &ChecklistHandler{
+		uc:  uc,
+		log: log.Named("checklist_handler"),
+	}
+}
+
+// ListItems возвращает чек-лист задачи в порядке позиций
+func (h *ChecklistHandler) ListItems(c *gin.Context) {
+	items, err := h.uc.ListItems(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// CreateItem добавляет пункт в конец чек-листа
+func (h *ChecklistHandler) CreateItem(c *gin.Context) {
+	var req dtos.CreateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid checklist item request", zap.Error(err))
+		c.Error(response.InvalidBody(err))
+		return
+	}
+
+	item, err := h.uc.CreateItem(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+// UpdateItem меняет текст или отметку выполнения пункта
+func (h *ChecklistHandler) UpdateItem(c *gin.Context) {
+	itemID, ok := checklistItemID(c)
+	if !ok {
+		return
+	}
+
+	var req dtos.UpdateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid checklist item request", zap.Error(err))
+		c.Error(response.InvalidBody(err))
+		return
+	}
+
+	item, err := h.uc.UpdateItem(c.Request.Context(), c.Param("id"), itemID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// ToggleItem переключает отметку выполнения пункта
+func (h *ChecklistHandler) ToggleItem(c *gin.Context) {
+	itemID, ok := checklistItemID(c)
+	if !ok {
+		return
+	}
+
+	item, err := h.uc.ToggleItem(c.Request.Context(), c.Param("id"), itemID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// DeleteItem удаляет пункт чек-листа
+func (h *ChecklistHandler) DeleteItem(c *gin.Context) {
+	itemID, ok := checklistItemID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.DeleteItem(c.Request.Context(), c.Param("id"), itemID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ReorderItems задает новый порядок всех пунктов чек-листа
+func (h *ChecklistHandler) ReorderItems(c *gin.Context) {
+	var req dtos.ReorderChecklistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid checklist reorder request", zap.Error(err))
+		c.Error(response.InvalidBody(err))
+		return
+	}
+
+	items, err := h.uc.ReorderItems(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// checklistItemID разбирает ID пункта из пути. Некорректный ID неотличим от несуществующего пункта.
+func checklistItemID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("itemId"), 10, 64)
+	if err != nil {
+		c.Error(task.ErrChecklistItemNotFound)
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,63 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"task-manager/internal/task"
+	"task-manager/internal/task/dtos"
+	"task-manager/pkg/response"
+)
+
+type DependencyHandler struct {
+	uc  task.DependencyUseCase
+	log *zap.Logger
+}
+
+func NewDependencyHandler(uc task.DependencyUseCase, log *zap.Logger) *DependencyHandler {
+	return &DependencyHandler{
+		uc:  uc,
+		log: log.Named("dependency_handler"),
+	}
+}
+
+// AddDependency помечает задачу заблокированной другой задачей
+func (h *DependencyHandler) AddDependency(c *gin.Context) {
+	var req dtos.AddDependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid dependency request", zap.Error(err))
+		c.Error(response.InvalidBody(err))
+		return
+	}
+
+	t, err := h.uc.AddDependency(c.Request.Context(), c.Param("id"), req.BlockerID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.ToTaskResponse(*t))
+}
+
+// RemoveDependency снимает блокировку задачи другой задачей
+func (h *DependencyHandler) RemoveDependency(c *gin.Context) {
+	t, err := h.uc.RemoveDependency(c.Request.Context(), c.Param("id"), c.Param("blockerId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.ToTaskResponse(*t))
+}
+
+// Plan возвращает порядок выполнения и критический путь: ?ids=a,b,c или все невыполненные задачи
+func (h *DependencyHandler) Plan(c *gin.Context) {
+	plan, err := h.uc.Plan(c.Request.Context(), queryList(c, "ids"))
+	if err != nil {
+		h.log.Error("Failed to build plan", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
@@ -0,0 +1,114 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"strings"
+	"task-manager/internal/task/dtos"
+	"task-manager/internal/task/entity"
+	"task-manager/pkg/apperror"
+	"time"
+)
+
+// bindFilter читает параметры фильтра из query:
+// status и priority (через запятую или повторением), due_before, due_after,
+// created_from, created_to, overdue, q, labels и labels_match (any или all).
+func bindFilter(c *gin.Context) (dtos.Filter, error) {
+	var (
+		filter dtos.Filter
+		fields []apperror.FieldError
+	)
+
+	for _, value := range queryList(c, "status") {
+		status := entity.Status(value)
+		if !status.Valid() {
+			fields = append(fields, apperror.FieldError{Field: "status", Message: fmt.Sprintf("unknown status %q", value)})
+			continue
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+
+	for _, value := range queryList(c, "priority") {
+		priority := entity.Priority(value)
+		if !priority.Valid() {
+			fields = append(fields, apperror.FieldError{Field: "priority", Message: fmt.Sprintf("unknown priority %q", value)})
+			continue
+		}
+		filter.Priorities = append(filter.Priorities, priority)
+	}
+
+	for _, param := range []struct {
+		name string
+		dest **time.Time
+	}{
+		{"due_before", &filter.DueBefore},
+		{"due_after", &filter.DueAfter},
+		{"created_from", &filter.CreatedFrom},
+		{"created_to", &filter.CreatedTo},
+	} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+		t, err := parseTime(value)
+		if err != nil {
+			fields = append(fields, apperror.FieldError{Field: param.name, Message: "must be RFC3339 or YYYY-MM-DD"})
+			continue
+		}
+		*param.dest = &t
+	}
+
+	if value := c.Query("overdue"); value != "" {
+		overdue, err := strconv.ParseBool(value)
+		if err != nil {
+			fields = append(fields, apperror.FieldError{Field: "overdue", Message: "must be a boolean"})
+		}
+		filter.OverdueOnly = overdue
+	}
+
+	filter.Search = strings.TrimSpace(c.Query("q"))
+
+	// Имена меток сравниваются без учета регистра, повторы убираются, чтобы не ломать режим all
+	seen := map[string]bool{}
+	for _, name := range queryList(c, "labels") {
+		name = strings.ToLower(name)
+		if !seen[name] {
+			seen[name] = true
+			filter.Labels = append(filter.Labels, name)
+		}
+	}
+	switch c.DefaultQuery("labels_match", "any") {
+	case "any":
+	case "all":
+		filter.LabelsMatchAll = true
+	default:
+		fields = append(fields, apperror.FieldError{Field: "labels_match", Message: "must be one of: any, all"})
+	}
+
+	if len(fields) > 0 {
+		return filter, apperror.New(apperror.ErrInvalidInput, "invalid filter parameters").WithFields(fields...)
+	}
+	return filter, nil
+}
+
+// queryList собирает значения параметра, переданного списком через запятую и/или несколько раз
+func queryList(c *gin.Context, name string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(name) {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// parseTime принимает RFC3339 или дату в формате YYYY-MM-DD
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
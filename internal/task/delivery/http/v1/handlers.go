@@ -1,13 +1,16 @@
 package v1
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	"net/http"
 	"strconv"
 	"task-manager/internal/task"
 	"task-manager/internal/task/dtos"
-	"task-manager/internal/task/entity"
+	"task-manager/pkg/identity"
+	"task-manager/pkg/response"
+	"time"
 )
 
 type TaskHandler struct {
@@ -27,20 +30,20 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 	var req dtos.CreateTaskRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.log.Warn("Invalid request format", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		c.Error(response.InvalidBody(err))
 		return
 	}
 
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		c.Error(identity.ErrNoUser)
 		return
 	}
 
 	uid, ok := userID.(int64)
 	if !ok {
 		h.log.Error("Invalid user_id type in context")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		c.Error(errors.New("invalid user_id type in context"))
 		return
 	}
 
@@ -49,11 +52,11 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 	task, err := h.uc.CreateTask(c.Request.Context(), &req)
 	if err != nil {
 		h.log.Error("Failed to create task", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, task)
+	c.JSON(http.StatusCreated, dtos.ToTaskResponse(*task))
 }
 
 // GetTask возвращает задачу по ID
@@ -61,12 +64,44 @@ func (h *TaskHandler) GetTask(c *gin.Context) {
 	id := c.Param("id")
 	task, err := h.uc.GetTask(c.Request.Context(), id)
 	if err != nil {
-		h.log.Error("Task not found", zap.Error(err), zap.String("task_id", id))
-		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		h.log.Error("Failed to get task", zap.Error(err), zap.String("task_id", id))
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, task)
+	c.JSON(http.StatusOK, dtos.ToTaskResponse(*task))
+}
+
+// GetTaskHistory возвращает историю смены статусов задачи
+func (h *TaskHandler) GetTaskHistory(c *gin.Context) {
+	id := c.Param("id")
+	history, err := h.uc.GetTaskHistory(c.Request.Context(), id)
+	if err != nil {
+		h.log.Error("Failed to get task history", zap.Error(err), zap.String("task_id", id))
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.ToStatusHistoryResponse(history, time.Now()))
+}
+
+// SearchTasks ищет задачи по названию и описанию: ?q=, limit, offset
+func (h *TaskHandler) SearchTasks(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	page, err := h.uc.SearchTasks(c.Request.Context(), dtos.SearchRequest{
+		Query:  c.Query("q"),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		h.log.Error("Failed to search tasks", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.ToSearchPageResponse(page))
 }
 
 // UpdateTask обновляет существующую задачу
@@ -75,18 +110,31 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	var req dtos.UpdateTaskRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.log.Warn("Invalid update request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		c.Error(response.InvalidBody(err))
 		return
 	}
 
 	task, err := h.uc.UpdateTask(c.Request.Context(), id, &req)
 	if err != nil {
 		h.log.Error("Failed to update task", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Update failed"})
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, task)
+	c.JSON(http.StatusOK, dtos.ToTaskResponse(*task))
+}
+
+// ReopenTask переоткрывает выполненную задачу
+func (h *TaskHandler) ReopenTask(c *gin.Context) {
+	id := c.Param("id")
+	task, err := h.uc.ReopenTask(c.Request.Context(), id)
+	if err != nil {
+		h.log.Error("Failed to reopen task", zap.Error(err), zap.String("task_id", id))
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.ToTaskResponse(*task))
 }
 
 // DeleteTask удаляет задачу
@@ -94,7 +142,7 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 	id := c.Param("id")
 	if err := h.uc.DeleteTask(c.Request.Context(), id); err != nil {
 		h.log.Error("Failed to delete task", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Deletion failed"})
+		c.Error(err)
 		return
 	}
 
@@ -103,21 +151,103 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 
 // ListTasks возвращает список задач с фильтрацией
 func (h *TaskHandler) ListTasks(c *gin.Context) {
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	filter, err := bindFilter(c)
+	if err != nil {
+		h.log.Warn("Invalid task filter", zap.Error(err))
+		c.Error(err)
+		return
+	}
 
-	tasks, err := h.uc.ListTasks(c.Request.Context(), dtos.Filter{
-		Status: entity.Status(c.Query("status")),
-	}, dtos.Pagination{
-		Limit:  limit,
-		Offset: offset,
-	})
+	pagination, err := bindPagination(c)
+	if err != nil {
+		h.log.Warn("Invalid pagination", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	page, err := h.uc.ListTasks(c.Request.Context(), filter, pagination)
 
 	if err != nil {
 		h.log.Error("Failed to list tasks", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal error"})
+		c.Error(err)
+		return
+	}
+
+	setLinkHeader(c, page)
+	c.JSON(http.StatusOK, dtos.ToTaskPageResponse(page))
+}
+
+// CreateSubtask создает подзадачу задачи из пути
+func (h *TaskHandler) CreateSubtask(c *gin.Context) {
+	var req dtos.CreateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid request format", zap.Error(err))
+		c.Error(response.InvalidBody(err))
+		return
+	}
+
+	subtask, err := h.uc.CreateSubtask(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.log.Error("Failed to create subtask", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dtos.ToTaskResponse(*subtask))
+}
+
+// ListSubtasks возвращает прямые подзадачи с теми же фильтрами и пагинацией, что и ListTasks
+func (h *TaskHandler) ListSubtasks(c *gin.Context) {
+	filter, err := bindFilter(c)
+	if err != nil {
+		h.log.Warn("Invalid task filter", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	pagination, err := bindPagination(c)
+	if err != nil {
+		h.log.Warn("Invalid pagination", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	page, err := h.uc.ListSubtasks(c.Request.Context(), c.Param("id"), filter, pagination)
+	if err != nil {
+		h.log.Error("Failed to list subtasks", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	setLinkHeader(c, page)
+	c.JSON(http.StatusOK, dtos.ToTaskPageResponse(page))
+}
+
+// ListAllTasks возвращает задачи всех пользователей (для администраторов)
+func (h *TaskHandler) ListAllTasks(c *gin.Context) {
+	filter, err := bindFilter(c)
+	if err != nil {
+		h.log.Warn("Invalid task filter", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	pagination, err := bindPagination(c)
+	if err != nil {
+		h.log.Warn("Invalid pagination", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	page, err := h.uc.ListAllTasks(c.Request.Context(), filter, pagination)
+
+	if err != nil {
+		h.log.Error("Failed to list all tasks", zap.Error(err))
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, tasks)
+	// Администратору нужен владелец задачи, поэтому задачи отдаются вместе с user_id
+	setLinkHeader(c, page)
+	c.JSON(http.StatusOK, page)
 }
@@ -0,0 +1,133 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"task-manager/internal/task"
+	"task-manager/internal/task/dtos"
+	"task-manager/pkg/response"
+)
+
+type LabelHandler struct {
+	uc  task.LabelUseCase
+	log *zap.Logger
+}
+
+func NewLabelHandler(uc task.LabelUseCase, log *zap.Logger) *LabelHandler {
+	return &LabelHandler{
+		uc:  uc,
+		log: log.Named("label_handler"),
+	}
+}
+
+// CreateLabel создает метку текущего пользователя
+func (h *LabelHandler) CreateLabel(c *gin.Context) {
+	var req dtos.CreateLabelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid label request", zap.Error(err))
+		c.Error(response.InvalidBody(err))
+		return
+	}
+
+	label, err := h.uc.CreateLabel(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, label)
+}
+
+// ListLabels возвращает метки текущего пользователя
+func (h *LabelHandler) ListLabels(c *gin.Context) {
+	labels, err := h.uc.ListLabels(c.Request.Context())
+	if err != nil {
+		h.log.Error("Failed to list labels", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, labels)
+}
+
+// UpdateLabel переименовывает метку или меняет ее цвет
+func (h *LabelHandler) UpdateLabel(c *gin.Context) {
+	id, ok := labelID(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dtos.UpdateLabelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid label request", zap.Error(err))
+		c.Error(response.InvalidBody(err))
+		return
+	}
+
+	label, err := h.uc.UpdateLabel(c.Request.Context(), id, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, label)
+}
+
+// DeleteLabel удаляет метку и ее привязки к задачам
+func (h *LabelHandler) DeleteLabel(c *gin.Context) {
+	id, ok := labelID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.uc.DeleteLabel(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AttachLabel привязывает метку к задаче
+func (h *LabelHandler) AttachLabel(c *gin.Context) {
+	id, ok := labelID(c, "labelId")
+	if !ok {
+		return
+	}
+
+	t, err := h.uc.AttachLabel(c.Request.Context(), c.Param("id"), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.ToTaskResponse(*t))
+}
+
+// DetachLabel отвязывает метку от задачи
+func (h *LabelHandler) DetachLabel(c *gin.Context) {
+	id, ok := labelID(c, "labelId")
+	if !ok {
+		return
+	}
+
+	t, err := h.uc.DetachLabel(c.Request.Context(), c.Param("id"), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.ToTaskResponse(*t))
+}
+
+// labelID разбирает ID метки из пути. Некорректный ID неотличим от несуществующей метки.
+func labelID(c *gin.Context, param string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(param), 10, 64)
+	if err != nil {
+		c.Error(task.ErrLabelNotFound)
+		return 0, false
+	}
+	return id, true
+}
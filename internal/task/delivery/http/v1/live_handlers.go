@@ -0,0 +1,92 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"task-manager/internal/task"
+	"task-manager/internal/task/entity"
+	"time"
+)
+
+type LiveEventHandler struct {
+	uc        task.LiveEventUseCase
+	heartbeat time.Duration
+	closing   <-chan struct{}
+	log       *zap.Logger
+}
+
+// NewLiveEventHandler раз в heartbeat шлет в стрим комментарий, чтобы прокси не закрыли
+// простаивающее соединение. Закрытие closing завершает все открытые стримы.
+func NewLiveEventHandler(
+	uc task.LiveEventUseCase,
+	heartbeat time.Duration,
+	closing <-chan struct{},
+	log *zap.Logger,
+) *LiveEventHandler {
+	return &LiveEventHandler{
+		uc:        uc,
+		heartbeat: heartbeat,
+		closing:   closing,
+		log:       log.Named("live_event_handler"),
+	}
+}
+
+// StreamEvents отдает события задач текущего пользователя как text/event-stream.
+// После обрыва браузер переподключается с Last-Event-ID и получает пропущенное.
+func (h *LiveEventHandler) StreamEvents(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	sub, err := h.uc.Subscribe(ctx, c.GetHeader("Last-Event-ID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.closing:
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-sub.Events():
+			if !ok {
+				// Подписка оборвалась: клиент переподключится и дочитает бэклог
+				h.log.Debug("Live subscription closed")
+				return
+			}
+			if err := writeEvent(c.Writer, event); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+// writeEvent пишет событие в формате SSE. Событие без ID (reset) не сдвигает
+// Last-Event-ID клиента.
+func writeEvent(w gin.ResponseWriter, event entity.LiveEvent) error {
+	if event.ID != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", event.ID); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, event.Data)
+	return err
+}
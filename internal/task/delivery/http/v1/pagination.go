@@ -0,0 +1,65 @@
+package v1
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"strings"
+	"task-manager/internal/task"
+	"task-manager/internal/task/dtos"
+	"task-manager/pkg/apperror"
+)
+
+// bindPagination читает limit, offset, sort и cursor из query. Курсор имеет приоритет над offset
+// и должен быть выдан для того же sort.
+func bindPagination(c *gin.Context) (dtos.Pagination, error) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	sort, err := dtos.ParseSort(c.Query("sort"))
+	if err != nil {
+		return dtos.Pagination{}, apperror.Wrap(apperror.ErrInvalidInput, "invalid sort parameter", err).
+			WithFields(apperror.FieldError{Field: "sort", Message: err.Error()})
+	}
+
+	pagination := dtos.Pagination{
+		Limit:  limit,
+		Offset: offset,
+		Sort:   sort,
+	}
+
+	if raw := c.Query("cursor"); raw != "" {
+		cursor, err := dtos.DecodeCursor(raw)
+		if err != nil || !cursor.Matches(sort) {
+			return pagination, task.ErrInvalidCursor
+		}
+		pagination.Cursor = cursor
+		pagination.Offset = 0
+	}
+
+	return pagination, nil
+}
+
+// setLinkHeader добавляет ссылки на соседние страницы (RFC 8288), сохраняя остальные параметры запроса
+func setLinkHeader(c *gin.Context, page *dtos.TaskPage) {
+	var links []string
+
+	link := func(cursor *string, rel string) {
+		if cursor == nil {
+			return
+		}
+		u := *c.Request.URL
+		query := u.Query()
+		query.Del("offset")
+		query.Set("cursor", *cursor)
+		u.RawQuery = query.Encode()
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, u.RequestURI(), rel))
+	}
+
+	link(page.NextCursor, "next")
+	link(page.PrevCursor, "prev")
+
+	if len(links) > 0 {
+		c.Header("Link", strings.Join(links, ", "))
+	}
+}
@@ -0,0 +1,68 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"task-manager/internal/task"
+	"task-manager/internal/task/dtos"
+	"task-manager/pkg/response"
+)
+
+type ReminderHandler struct {
+	uc  task.ReminderUseCase
+	log *zap.Logger
+}
+
+func NewReminderHandler(uc task.ReminderUseCase, log *zap.Logger) *ReminderHandler {
+	return &ReminderHandler{
+		uc:  uc,
+		log: log.Named("reminder_handler"),
+	}
+}
+
+// ListReminders возвращает напоминания задачи, начиная с самого раннего
+func (h *ReminderHandler) ListReminders(c *gin.Context) {
+	reminders, err := h.uc.ListReminders(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reminders)
+}
+
+// CreateReminder добавляет напоминание за before_minutes минут до срока задачи
+func (h *ReminderHandler) CreateReminder(c *gin.Context) {
+	var req dtos.CreateReminderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid reminder request", zap.Error(err))
+		c.Error(response.InvalidBody(err))
+		return
+	}
+
+	reminder, err := h.uc.CreateReminder(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, reminder)
+}
+
+// DeleteReminder удаляет напоминание
+func (h *ReminderHandler) DeleteReminder(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("reminderId"), 10, 64)
+	if err != nil {
+		c.Error(task.ErrReminderNotFound)
+		return
+	}
+
+	if err := h.uc.DeleteReminder(c.Request.Context(), c.Param("id"), id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -2,6 +2,8 @@ package v1
 
 import (
 	"github.com/gin-gonic/gin"
+	"task-manager/pkg/middleware"
+	"task-manager/pkg/rbac"
 )
 
 func (h *TaskHandler) TaskRoutes(router *gin.RouterGroup, auth gin.HandlerFunc) {
@@ -9,9 +11,81 @@ func (h *TaskHandler) TaskRoutes(router *gin.RouterGroup, auth gin.HandlerFunc)
 	taskGroup := router.Group("/tasks").Use(auth)
 	{
 		taskGroup.POST("", h.CreateTask)
+		taskGroup.GET("/search", h.SearchTasks)
 		taskGroup.GET("/:id", h.GetTask)
+		taskGroup.GET("/:id/history", h.GetTaskHistory)
+		taskGroup.POST("/:id/subtasks", h.CreateSubtask)
+		taskGroup.GET("/:id/subtasks", h.ListSubtasks)
 		taskGroup.PUT("/:id", h.UpdateTask)
+		taskGroup.POST("/:id/reopen", h.ReopenTask)
 		taskGroup.DELETE("/:id", h.DeleteTask)
 		taskGroup.GET("", h.ListTasks)
 	}
+
+	// Просмотр задач всех пользователей для администраторов
+	adminGroup := router.Group("/admin/tasks").Use(auth, middleware.RequirePermission(rbac.PermTasksReadAll, h.log))
+	{
+		adminGroup.GET("", h.ListAllTasks)
+	}
+}
+
+func (h *LabelHandler) LabelRoutes(router *gin.RouterGroup, auth gin.HandlerFunc) {
+	labelGroup := router.Group("/labels").Use(auth)
+	{
+		labelGroup.POST("", h.CreateLabel)
+		labelGroup.GET("", h.ListLabels)
+		labelGroup.PUT("/:id", h.UpdateLabel)
+		labelGroup.DELETE("/:id", h.DeleteLabel)
+	}
+
+	taskLabelGroup := router.Group("/tasks/:id/labels").Use(auth)
+	{
+		taskLabelGroup.PUT("/:labelId", h.AttachLabel)
+		taskLabelGroup.DELETE("/:labelId", h.DetachLabel)
+	}
+}
+
+func (h *ChecklistHandler) ChecklistRoutes(router *gin.RouterGroup, auth gin.HandlerFunc) {
+	checklistGroup := router.Group("/tasks/:id/checklist").Use(auth)
+	{
+		checklistGroup.GET("", h.ListItems)
+		checklistGroup.POST("", h.CreateItem)
+		checklistGroup.PUT("/order", h.ReorderItems)
+		checklistGroup.PATCH("/:itemId", h.UpdateItem)
+		checklistGroup.POST("/:itemId/toggle", h.ToggleItem)
+		checklistGroup.DELETE("/:itemId", h.DeleteItem)
+	}
+}
+
+func (h *DependencyHandler) DependencyRoutes(router *gin.RouterGroup, auth gin.HandlerFunc) {
+	taskGroup := router.Group("/tasks").Use(auth)
+	{
+		taskGroup.GET("/plan", h.Plan)
+		taskGroup.POST("/:id/dependencies", h.AddDependency)
+		taskGroup.DELETE("/:id/dependencies/:blockerId", h.RemoveDependency)
+	}
+}
+
+func (h *ReminderHandler) ReminderRoutes(router *gin.RouterGroup, auth gin.HandlerFunc) {
+	reminderGroup := router.Group("/tasks/:id/reminders").Use(auth)
+	{
+		reminderGroup.GET("", h.ListReminders)
+		reminderGroup.POST("", h.CreateReminder)
+		reminderGroup.DELETE("/:reminderId", h.DeleteReminder)
+	}
+}
+
+func (h *LiveEventHandler) LiveEventRoutes(router *gin.RouterGroup, auth gin.HandlerFunc) {
+	liveGroup := router.Group("/tasks").Use(auth)
+	{
+		liveGroup.GET("/events", h.StreamEvents)
+	}
+}
+
+func (h *BoardHandler) BoardRoutes(router *gin.RouterGroup, auth gin.HandlerFunc) {
+	// Браузер передает токен в Sec-WebSocket-Protocol, дальше он проверяется как обычно
+	wsGroup := router.Group("/ws").Use(middleware.WebSocketToken(), auth)
+	{
+		wsGroup.GET("", h.Connect)
+	}
 }
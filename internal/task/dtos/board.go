@@ -0,0 +1,45 @@
+package dtos
+
+import (
+	"encoding/json"
+	"task-manager/pkg/response"
+)
+
+// Команды, которые клиент доски шлет по WebSocket
+const (
+	BoardSubscribe   = "subscribe"
+	BoardUnsubscribe = "unsubscribe"
+	BoardMoveTask    = "move_task"
+	BoardUpdateTask  = "update_task"
+	BoardReopenTask  = "reopen_task"
+	BoardPing        = "ping"
+)
+
+// Сообщения, которые сервер шлет клиенту доски
+const (
+	BoardAck   = "ack"
+	BoardError = "error"
+	BoardEvent = "event"
+)
+
+// BoardCommand - команда клиента. ID возвращается в ack или error, чтобы клиент
+// сопоставил ответ с командой. move_task переносит карточку в колонку Status.
+type BoardCommand struct {
+	ID      string             `json:"id,omitempty" validate:"max=64"`
+	Type    string             `json:"type" validate:"required,oneof=subscribe unsubscribe move_task update_task reopen_task ping"`
+	Channel string             `json:"channel,omitempty" validate:"required_if=Type subscribe,required_if=Type unsubscribe"`
+	TaskID  string             `json:"task_id,omitempty" validate:"required_if=Type move_task,required_if=Type update_task,required_if=Type reopen_task"`
+	Status  string             `json:"status,omitempty" validate:"required_if=Type move_task,omitempty,task_status"`
+	Changes *UpdateTaskRequest `json:"changes,omitempty" validate:"required_if=Type update_task"`
+}
+
+// BoardMessage - ответ на команду (ack, error) или событие канала (event)
+type BoardMessage struct {
+	Type    string            `json:"type"`
+	ID      string            `json:"id,omitempty"`
+	Channel string            `json:"channel,omitempty"`
+	EventID string            `json:"event_id,omitempty"`
+	Event   json.RawMessage   `json:"event,omitempty"`
+	Task    *TaskResponse     `json:"task,omitempty"`
+	Error   *response.Problem `json:"error,omitempty"`
+}
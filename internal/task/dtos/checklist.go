@@ -0,0 +1,15 @@
+package dtos
+
+type CreateChecklistItemRequest struct {
+	Text string `json:"text" validate:"required,max=200"`
+}
+
+type UpdateChecklistItemRequest struct {
+	Text *string `json:"text,omitempty" validate:"omitempty,min=1,max=200"`
+	Done *bool   `json:"done,omitempty"`
+}
+
+// ReorderChecklistRequest - новый порядок пунктов: все ID пунктов задачи в нужной последовательности
+type ReorderChecklistRequest struct {
+	ItemIDs []int64 `json:"item_ids" validate:"required,min=1,unique"`
+}
@@ -4,9 +4,11 @@ import "time"
 
 type CreateTaskRequest struct {
 	UserID      int64
+	ParentID    *string   `json:"parent_id,omitempty" validate:"omitempty,uuid"`
 	Title       string    `json:"title" validate:"required,max=100"`
-	Description *string   `json:"description,omitempty" validate:"max=500"`
-	Status      string    `json:"status,omitempty" validate:"omitempty,oneof=pending in_progress done"`
-	Priority    string    `json:"priority,omitempty" validate:"omitempty,oneof=low medium high"`
+	Description *string   `json:"description,omitempty" validate:"omitempty,max=500"`
+	Priority    string    `json:"priority,omitempty" validate:"omitempty,task_priority"`
 	DueDate     time.Time `json:"due_date" validate:"required"`
+	Recurrence  *string   `json:"recurrence,omitempty" validate:"omitempty,max=200"`
+	Timezone    *string   `json:"timezone,omitempty" validate:"omitempty,timezone"`
 }
@@ -0,0 +1,14 @@
+package dtos
+
+import "task-manager/internal/task/entity"
+
+type AddDependencyRequest struct {
+	BlockerID string `json:"blocker_id" validate:"required,uuid"`
+}
+
+// DependencyPlan - порядок выполнения набора задач и его критический путь
+type DependencyPlan struct {
+	Order        []entity.TaskRef    `json:"order"`
+	CriticalPath []entity.TaskRef    `json:"critical_path"`
+	Dependencies []entity.Dependency `json:"dependencies"`
+}
@@ -1,9 +1,26 @@
 package dtos
 
-import "task-manager/internal/task/entity"
+import (
+	"github.com/google/uuid"
+	"task-manager/internal/task/entity"
+	"time"
+)
 
+// Filter - условия выборки списка задач. Пустые поля не ограничивают выборку,
+// значения внутри Statuses и Priorities объединяются по ИЛИ.
 type Filter struct {
-	Status   entity.Status
-	Priority entity.Priority
-	Search   string
+	Statuses    []entity.Status
+	Priorities  []entity.Priority
+	DueBefore   *time.Time
+	DueAfter    *time.Time
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	OverdueOnly bool
+	Search      string
+	// Labels - имена меток; задача подходит, если у нее есть любая из меток
+	// или, при LabelsMatchAll, все метки сразу
+	Labels         []string
+	LabelsMatchAll bool
+	// ParentID ограничивает выборку прямыми подзадачами задачи
+	ParentID *uuid.UUID
 }
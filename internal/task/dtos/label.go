@@ -0,0 +1,34 @@
+package dtos
+
+import "task-manager/internal/task/entity"
+
+// DefaultLabelColor - цвет метки, если он не указан при создании
+const DefaultLabelColor = "#808080"
+
+type CreateLabelRequest struct {
+	Name  string `json:"name" validate:"required,max=50"`
+	Color string `json:"color,omitempty" validate:"omitempty,label_color"`
+}
+
+type UpdateLabelRequest struct {
+	Name  *string `json:"name,omitempty" validate:"omitempty,min=1,max=50"`
+	Color *string `json:"color,omitempty" validate:"omitempty,label_color"`
+}
+
+type LabelResponse struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+func ToLabelResponses(labels []entity.Label) []LabelResponse {
+	out := make([]LabelResponse, 0, len(labels))
+	for _, label := range labels {
+		out = append(out, LabelResponse{
+			ID:    label.ID,
+			Name:  label.Name,
+			Color: label.Color,
+		})
+	}
+	return out
+}
@@ -1,6 +1,79 @@
 package dtos
 
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"task-manager/internal/task/entity"
+)
+
+// Pagination - параметры выборки страницы. Если задан Cursor, Offset игнорируется.
 type Pagination struct {
 	Limit  int
 	Offset int
+	Sort   Sort
+	Cursor *Cursor
+}
+
+// Cursor - позиция в списке задач: значения ключей сортировки и id последней задачи.
+// Sort фиксирует порядок, для которого выдан курсор. Backward означает выборку
+// страницы перед позицией, а не после нее.
+type Cursor struct {
+	Keys     []string  `json:"k"`
+	ID       uuid.UUID `json:"i"`
+	Sort     string    `json:"s"`
+	Backward bool      `json:"b,omitempty"`
+}
+
+var errMalformedCursor = errors.New("malformed cursor")
+
+// Encode возвращает непрозрачное представление курсора для передачи клиенту
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor разбирает курсор, полученный от клиента
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errMalformedCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == uuid.Nil {
+		return nil, errMalformedCursor
+	}
+
+	sort, err := ParseSort(c.Sort)
+	if err != nil || !sort.validKeys(c.Keys) {
+		return nil, errMalformedCursor
+	}
+	return &c, nil
+}
+
+// Matches проверяет, что курсор выдан для того же порядка сортировки
+func (c *Cursor) Matches(sort Sort) bool {
+	return c.Sort == sort.String()
+}
+
+// TaskPage - страница задач с курсорами соседних страниц и общим количеством по фильтру
+type TaskPage struct {
+	Items      []*entity.Task `json:"items"`
+	NextCursor *string        `json:"next_cursor"`
+	PrevCursor *string        `json:"prev_cursor"`
+	Total      int64          `json:"total"`
+}
+
+// CursorAfter - курсор на страницу, следующую за задачей t в порядке sort
+func CursorAfter(t *entity.Task, sort Sort) *string {
+	s := Cursor{Keys: sort.keys(t), ID: t.ID, Sort: sort.String()}.Encode()
+	return &s
+}
+
+// CursorBefore - курсор на страницу, предшествующую задаче t в порядке sort
+func CursorBefore(t *entity.Task, sort Sort) *string {
+	s := Cursor{Keys: sort.keys(t), ID: t.ID, Sort: sort.String(), Backward: true}.Encode()
+	return &s
 }
@@ -0,0 +1,6 @@
+package dtos
+
+// CreateReminderRequest - напоминание за before_minutes минут до срока (не больше 30 дней)
+type CreateReminderRequest struct {
+	BeforeMinutes *int `json:"before_minutes" validate:"required,min=0,max=43200"`
+}
@@ -0,0 +1,50 @@
+package dtos
+
+import "task-manager/internal/task/entity"
+
+// SearchRequest - полнотекстовый поиск по названию и описанию задач.
+// Query поддерживает "фразы в кавычках" и поиск по префиксу через слово*.
+type SearchRequest struct {
+	Query  string
+	Limit  int
+	Offset int
+}
+
+// SearchResult - найденная задача с релевантностью и подсвеченными фрагментами.
+// TitleHighlight и Snippet - HTML: текст экранирован, совпадения обернуты в <mark>.
+type SearchResult struct {
+	Task           *entity.Task
+	Rank           float64
+	TitleHighlight string
+	Snippet        string
+}
+
+type SearchPage struct {
+	Items []*SearchResult
+	Total int64
+}
+
+type SearchResultResponse struct {
+	Task           TaskResponse `json:"task"`
+	Rank           float64      `json:"rank"`
+	TitleHighlight string       `json:"title_highlight"`
+	Snippet        string       `json:"snippet,omitempty"`
+}
+
+type SearchPageResponse struct {
+	Items []SearchResultResponse `json:"items"`
+	Total int64                  `json:"total"`
+}
+
+func ToSearchPageResponse(page *SearchPage) SearchPageResponse {
+	items := make([]SearchResultResponse, 0, len(page.Items))
+	for _, result := range page.Items {
+		items = append(items, SearchResultResponse{
+			Task:           ToTaskResponse(*result.Task),
+			Rank:           result.Rank,
+			TitleHighlight: result.TitleHighlight,
+			Snippet:        result.Snippet,
+		})
+	}
+	return SearchPageResponse{Items: items, Total: page.Total}
+}
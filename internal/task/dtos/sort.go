@@ -0,0 +1,120 @@
+package dtos
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"task-manager/internal/task/entity"
+	"time"
+)
+
+// SortField - поле сортировки списка задач
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// Sort - порядок сортировки. Последним ключом всегда неявно идет id по возрастанию.
+type Sort []SortField
+
+// DefaultSort - порядок по умолчанию: ближайший срок первым
+var DefaultSort = Sort{{Field: "due_date"}}
+
+// sortKey - значение ключа задачи для курсора и проверка ключа, пришедшего в курсоре
+type sortKey struct {
+	value func(t *entity.Task) string
+	valid func(key string) bool
+}
+
+// sortKeys - разрешенные поля сортировки
+var sortKeys = map[string]sortKey{
+	"due_date":   timeKey(func(t *entity.Task) time.Time { return t.DueDate }),
+	"created_at": timeKey(func(t *entity.Task) time.Time { return t.CreatedAt }),
+	"updated_at": timeKey(func(t *entity.Task) time.Time { return t.UpdatedAt }),
+	"priority":   rankKey(func(t *entity.Task) int { return t.Priority.Rank() }),
+	"status":     rankKey(func(t *entity.Task) int { return t.Status.Rank() }),
+	"title": {
+		value: func(t *entity.Task) string { return t.Title },
+		valid: func(key string) bool { return len(key) <= 1000 },
+	},
+}
+
+func timeKey(get func(t *entity.Task) time.Time) sortKey {
+	return sortKey{
+		value: func(t *entity.Task) string { return get(t).Format(time.RFC3339Nano) },
+		valid: func(key string) bool {
+			_, err := time.Parse(time.RFC3339Nano, key)
+			return err == nil
+		},
+	}
+}
+
+func rankKey(get func(t *entity.Task) int) sortKey {
+	return sortKey{
+		value: func(t *entity.Task) string { return strconv.Itoa(get(t)) },
+		valid: func(key string) bool {
+			n, err := strconv.Atoi(key)
+			return err == nil && n >= 0 && n <= 100
+		},
+	}
+}
+
+// ParseSort разбирает строку вида "-priority,due_date": минус означает убывание.
+// Неизвестные и повторяющиеся поля отклоняются.
+func ParseSort(value string) (Sort, error) {
+	if strings.TrimSpace(value) == "" {
+		return DefaultSort, nil
+	}
+
+	var (
+		sort Sort
+		seen = map[string]bool{}
+	)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		field := SortField{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+
+		if _, ok := sortKeys[field.Field]; !ok {
+			return nil, fmt.Errorf("unknown sort field %q", field.Field)
+		}
+		if seen[field.Field] {
+			return nil, fmt.Errorf("duplicate sort field %q", field.Field)
+		}
+		seen[field.Field] = true
+		sort = append(sort, field)
+	}
+	return sort, nil
+}
+
+func (s Sort) String() string {
+	parts := make([]string, len(s))
+	for i, field := range s {
+		parts[i] = field.Field
+		if field.Desc {
+			parts[i] = "-" + field.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// keys возвращает значения ключей сортировки задачи в порядке s
+func (s Sort) keys(t *entity.Task) []string {
+	values := make([]string, len(s))
+	for i, field := range s {
+		values[i] = sortKeys[field.Field].value(t)
+	}
+	return values
+}
+
+// validKeys проверяет, что значения ключей курсора приводятся к типам полей сортировки
+func (s Sort) validKeys(keys []string) bool {
+	if len(s) != len(keys) {
+		return false
+	}
+	for i, field := range s {
+		if !sortKeys[field.Field].valid(keys[i]) {
+			return false
+		}
+	}
+	return true
+}
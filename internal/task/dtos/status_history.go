@@ -0,0 +1,54 @@
+package dtos
+
+import (
+	"task-manager/internal/task/entity"
+	"time"
+)
+
+// StatusChangeResponse - запись истории статусов с временем, проведенным в новом статусе
+type StatusChangeResponse struct {
+	OldStatus       *string   `json:"old_status,omitempty"`
+	NewStatus       string    `json:"new_status"`
+	ActorID         *int64    `json:"actor_id,omitempty"`
+	ChangedAt       time.Time `json:"changed_at"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	Current         bool      `json:"current"`
+}
+
+type StatusHistoryResponse struct {
+	Items []StatusChangeResponse `json:"items"`
+	// TimeInStatus - суммарное время в каждом статусе, в секундах
+	TimeInStatus map[string]float64 `json:"time_in_status"`
+}
+
+// ToStatusHistoryResponse считает время в каждом статусе; для текущего статуса - до now
+func ToStatusHistoryResponse(history []*entity.StatusChange, now time.Time) StatusHistoryResponse {
+	resp := StatusHistoryResponse{
+		Items:        make([]StatusChangeResponse, 0, len(history)),
+		TimeInStatus: map[string]float64{},
+	}
+
+	for i, change := range history {
+		end := now
+		if i+1 < len(history) {
+			end = history[i+1].ChangedAt
+		}
+		duration := end.Sub(change.ChangedAt).Seconds()
+
+		item := StatusChangeResponse{
+			NewStatus:       string(change.NewStatus),
+			ActorID:         change.ActorID,
+			ChangedAt:       change.ChangedAt,
+			DurationSeconds: duration,
+			Current:         i == len(history)-1,
+		}
+		if change.OldStatus != nil {
+			old := string(*change.OldStatus)
+			item.OldStatus = &old
+		}
+
+		resp.Items = append(resp.Items, item)
+		resp.TimeInStatus[string(change.NewStatus)] += duration
+	}
+	return resp
+}
@@ -6,25 +6,86 @@ import (
 )
 
 type TaskResponse struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description *string   `json:"description,omitempty"`
-	Status      string    `json:"status"`
-	Priority    string    `json:"priority"`
-	DueDate     time.Time `json:"due_date"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          string           `json:"id"`
+	ParentID    *string          `json:"parent_id,omitempty"`
+	Title       string           `json:"title"`
+	Description *string          `json:"description,omitempty"`
+	Status      string           `json:"status"`
+	Priority    string           `json:"priority"`
+	DueDate     time.Time        `json:"due_date"`
+	OverdueAt   *time.Time       `json:"overdue_at,omitempty"`
+	ArchivedAt  *time.Time       `json:"archived_at,omitempty"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	Labels      []LabelResponse  `json:"labels"`
+	Progress    entity.Progress  `json:"progress"`
+	Blocked     bool             `json:"blocked"`
+	Blockers    []entity.TaskRef `json:"blockers"`
+	SeriesID    *string          `json:"series_id,omitempty"`
+	Occurrence  *int             `json:"occurrence,omitempty"`
+	Recurrence  *string          `json:"recurrence,omitempty"`
+	Timezone    *string          `json:"timezone,omitempty"`
 }
 
 func ToTaskResponse(task entity.Task) TaskResponse {
+	var parentID *string
+	if task.ParentID != nil {
+		id := task.ParentID.String()
+		parentID = &id
+	}
+
+	var seriesID *string
+	if task.SeriesID != nil {
+		id := task.SeriesID.String()
+		seriesID = &id
+	}
+
 	return TaskResponse{
 		ID:          task.ID.String(),
+		ParentID:    parentID,
 		Title:       task.Title,
 		Description: task.Description,
 		Status:      string(task.Status),
 		Priority:    string(task.Priority),
 		DueDate:     task.DueDate,
+		OverdueAt:   task.OverdueAt,
+		ArchivedAt:  task.ArchivedAt,
+		CompletedAt: task.CompletedAt,
 		CreatedAt:   task.CreatedAt,
 		UpdatedAt:   task.UpdatedAt,
+		Labels:      ToLabelResponses(task.Labels),
+		Progress:    task.Progress,
+		Blocked:     task.Blocked,
+		Blockers:    task.Blockers,
+		SeriesID:    seriesID,
+		Occurrence:  task.Occurrence,
+		Recurrence:  task.Recurrence,
+		Timezone:    task.Timezone,
+	}
+}
+
+func ToTaskResponses(tasks []*entity.Task) []TaskResponse {
+	out := make([]TaskResponse, 0, len(tasks))
+	for _, task := range tasks {
+		out = append(out, ToTaskResponse(*task))
+	}
+	return out
+}
+
+// TaskPageResponse - страница задач в ответе API
+type TaskPageResponse struct {
+	Items      []TaskResponse `json:"items"`
+	NextCursor *string        `json:"next_cursor"`
+	PrevCursor *string        `json:"prev_cursor"`
+	Total      int64          `json:"total"`
+}
+
+func ToTaskPageResponse(page *TaskPage) TaskPageResponse {
+	return TaskPageResponse{
+		Items:      ToTaskResponses(page.Items),
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+		Total:      page.Total,
 	}
 }
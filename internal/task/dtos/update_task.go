@@ -5,7 +5,10 @@ import "time"
 type UpdateTaskRequest struct {
 	Title       *string    `json:"title,omitempty" validate:"omitempty,max=100"`
 	Description *string    `json:"description,omitempty" validate:"omitempty,max=500"`
-	Status      *string    `json:"status,omitempty" validate:"omitempty,oneof=pending in_progress done"`
-	Priority    *string    `json:"priority,omitempty" validate:"omitempty,oneof=low medium high"`
+	Status      *string    `json:"status,omitempty" validate:"omitempty,task_status"`
+	Priority    *string    `json:"priority,omitempty" validate:"omitempty,task_priority"`
 	DueDate     *time.Time `json:"due_date,omitempty"`
+	Scope       string     `json:"scope,omitempty" validate:"omitempty,oneof=this future"`
+	Recurrence  *string    `json:"recurrence,omitempty" validate:"omitempty,max=200"`
+	Timezone    *string    `json:"timezone,omitempty" validate:"omitempty,timezone"`
 }
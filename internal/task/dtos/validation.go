@@ -0,0 +1,29 @@
+package dtos
+
+import (
+	"regexp"
+	"task-manager/internal/task/entity"
+	"task-manager/pkg/validation"
+)
+
+// labelColorPattern - цвет метки #RRGGBB, ровно под колонку labels.color VARCHAR(7)
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// RegisterValidators добавляет теги task_status, task_priority и label_color, используемые в DTO задач
+func RegisterValidators(v *validation.Validator) error {
+	err := v.RegisterEnum("task_status", "must be one of: pending, in_progress, blocked, done, cancelled", func(value string) bool {
+		return entity.Status(value).Valid()
+	})
+	if err != nil {
+		return err
+	}
+
+	err = v.RegisterEnum("task_priority", "must be one of: low, medium, high", func(value string) bool {
+		return entity.Priority(value).Valid()
+	})
+	if err != nil {
+		return err
+	}
+
+	return v.RegisterEnum("label_color", "must be a color in #RRGGBB format", labelColorPattern.MatchString)
+}
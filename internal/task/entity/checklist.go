@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// ChecklistItem - шаг внутри задачи
+type ChecklistItem struct {
+	ID        int64     `json:"id"`
+	TaskID    uuid.UUID `json:"task_id"`
+	Text      string    `json:"text"`
+	Done      bool      `json:"done"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Progress - выполнение задачи по пунктам чек-листа и прямым подзадачам
+type Progress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// ParentDeletePolicy - что делать с подзадачами при удалении родителя
+type ParentDeletePolicy string
+
+const (
+	// ParentDeleteCascade - удалить все поддерево
+	ParentDeleteCascade ParentDeletePolicy = "cascade"
+	// ParentDeleteReparent - поднять прямые подзадачи на уровень удаляемой задачи
+	ParentDeleteReparent ParentDeletePolicy = "reparent"
+)
+
+func (p ParentDeletePolicy) Valid() bool {
+	switch p {
+	case ParentDeleteCascade, ParentDeleteReparent:
+		return true
+	}
+	return false
+}
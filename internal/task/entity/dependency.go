@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Dependency - ребро "BlockerID блокирует BlockedID"
+type Dependency struct {
+	BlockerID uuid.UUID `json:"blocker_id"`
+	BlockedID uuid.UUID `json:"blocked_id"`
+}
+
+// TaskRef - краткое представление связанной задачи
+type TaskRef struct {
+	ID      uuid.UUID `json:"id"`
+	Title   string    `json:"title"`
+	Status  Status    `json:"status"`
+	DueDate time.Time `json:"due_date"`
+}
@@ -0,0 +1,9 @@
+package entity
+
+// Fence - fencing token лока лидера, под которым фоновая джоба пишет данные.
+// Токен растет с каждым захватом лока Name, поэтому запись с токеном меньше уже
+// виденного пришла от прежнего лидера и отклоняется.
+type Fence struct {
+	Name  string
+	Token int64
+}
@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// Label - метка пользователя для группировки задач
+type Label struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
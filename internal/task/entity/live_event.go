@@ -0,0 +1,15 @@
+package entity
+
+import "encoding/json"
+
+// LiveEventReset - тип события, которым стрим сообщает, что часть событий после
+// Last-Event-ID уже вытеснена из бэклога и список задач нужно перечитать
+const LiveEventReset = "reset"
+
+// LiveEvent - событие задачи в живом стриме пользователя.
+// ID - ID записи в Redis Stream, Data - событие целиком в JSON.
+type LiveEvent struct {
+	ID   string
+	Type string
+	Data json.RawMessage
+}
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+// OutboxMessage - событие задачи, сохраненное в outbox до публикации.
+// Payload - событие целиком в JSON.
+type OutboxMessage struct {
+	ID          int64
+	EventID     uuid.UUID
+	Type        string
+	UserID      int64
+	TaskID      uuid.UUID
+	Payload     json.RawMessage
+	CreatedAt   time.Time
+	PublishedAt *time.Time
+}
+
+// StreamMessage - событие outbox, прочитанное из Redis Stream группой потребителей.
+// StreamID нужен для подтверждения обработки.
+type StreamMessage struct {
+	StreamID string
+	Payload  json.RawMessage
+}
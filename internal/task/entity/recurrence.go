@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// DefaultTimezone - часовой пояс серии, если клиент его не указал
+const DefaultTimezone = "UTC"
+
+// Series - повторяющаяся задача. Вхождения - обычные задачи с series_id,
+// следующее создается по правилу Rule из шаблона (Title, Description, Priority).
+// Правило вычисляется в часовом поясе Timezone (IANA), чтобы вхождения сохраняли
+// время суток при переходе на летнее время. Generated - номер последнего созданного
+// вхождения по правилу; пропущенные в прошлом вхождения тоже учитываются.
+type Series struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      int64      `json:"user_id"`
+	Rule        string     `json:"rule"`
+	Timezone    string     `json:"timezone"`
+	Title       string     `json:"title"`
+	Description *string    `json:"description,omitempty"`
+	Priority    Priority   `json:"priority"`
+	StartsAt    time.Time  `json:"starts_at"`
+	LastDueDate time.Time  `json:"last_due_date"`
+	Generated   int        `json:"generated"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// EditScope - к каким вхождениям серии применяется изменение задачи
+type EditScope string
+
+const (
+	EditScopeThis   EditScope = "this"
+	EditScopeFuture EditScope = "future"
+)
+
+func (s EditScope) Valid() bool {
+	switch s {
+	case EditScopeThis, EditScopeFuture:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Reminder - напоминание за BeforeMinutes минут до срока задачи
+type Reminder struct {
+	ID            int64      `json:"id"`
+	TaskID        uuid.UUID  `json:"task_id"`
+	BeforeMinutes int        `json:"before_minutes"`
+	FireAt        time.Time  `json:"fire_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	SentDueDate   *time.Time `json:"-"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// FireTime - момент срабатывания для срока dueDate
+func (r Reminder) FireTime(dueDate time.Time) time.Time {
+	return dueDate.Add(-time.Duration(r.BeforeMinutes) * time.Minute)
+}
+
+// SentFor сообщает, напоминали ли уже о сроке dueDate
+func (r Reminder) SentFor(dueDate time.Time) bool {
+	return r.SentDueDate != nil && r.SentDueDate.Equal(dueDate)
+}
+
+// DueReminder - сработавшее напоминание вместе с задачей и адресом владельца
+type DueReminder struct {
+	Reminder
+	Task  Task
+	Email string
+}
@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// StatusChange - запись о смене статуса задачи. OldStatus пуст для записи о создании.
+type StatusChange struct {
+	ID        int64     `json:"id"`
+	TaskID    uuid.UUID `json:"task_id"`
+	ActorID   *int64    `json:"actor_id,omitempty"`
+	OldStatus *Status   `json:"old_status,omitempty"`
+	NewStatus Status    `json:"new_status"`
+	ChangedAt time.Time `json:"changed_at"`
+}
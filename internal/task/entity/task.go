@@ -10,9 +10,40 @@ type Status string
 const (
 	StatusPending    Status = "pending"
 	StatusInProgress Status = "in_progress"
+	StatusBlocked    Status = "blocked"
 	StatusDone       Status = "done"
+	StatusCancelled  Status = "cancelled"
 )
 
+// Statuses - все статусы в порядке workflow. Какие из них доступны и как между ними
+// переходить, задает таблица переходов в конфигурации (TASK_WORKFLOW).
+var Statuses = []Status{StatusPending, StatusInProgress, StatusBlocked, StatusDone, StatusCancelled}
+
+func (s Status) Valid() bool {
+	switch s {
+	case StatusPending, StatusInProgress, StatusBlocked, StatusDone, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// Rank - порядковый номер статуса в workflow, используется для сортировки
+func (s Status) Rank() int {
+	switch s {
+	case StatusPending:
+		return 1
+	case StatusInProgress:
+		return 2
+	case StatusBlocked:
+		return 3
+	case StatusDone:
+		return 4
+	case StatusCancelled:
+		return 5
+	}
+	return 0
+}
+
 type Priority string
 
 const (
@@ -21,13 +52,74 @@ const (
 	PriorityHigh   Priority = "high"
 )
 
+// Priorities - все приоритеты по возрастанию
+var Priorities = []Priority{PriorityLow, PriorityMedium, PriorityHigh}
+
+// OverduePolicy - что делать с просроченными задачами в фоновой джобе
+type OverduePolicy string
+
+const (
+	OverduePolicyMark     OverduePolicy = "mark"
+	OverduePolicyArchive  OverduePolicy = "archive"
+	OverduePolicyEscalate OverduePolicy = "escalate"
+	OverduePolicyDelete   OverduePolicy = "delete"
+)
+
+// Destructive сообщает, что политика убирает задачу из работы (архивирует или удаляет).
+// Такие политики применяются только после grace period.
+func (p OverduePolicy) Destructive() bool {
+	return p == OverduePolicyArchive || p == OverduePolicyDelete
+}
+
+func (p OverduePolicy) Valid() bool {
+	switch p {
+	case OverduePolicyMark, OverduePolicyArchive, OverduePolicyEscalate, OverduePolicyDelete:
+		return true
+	}
+	return false
+}
+
 type Task struct {
-	ID          uuid.UUID `json:"id"`
-	Title       string    `json:"title"`
-	Description *string   `json:"description,omitempty"`
-	Status      Status    `json:"status"`
-	Priority    Priority  `json:"priority"`
-	DueDate     time.Time `json:"due_date"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uuid.UUID  `json:"id"`
+	UserID      int64      `json:"user_id"`
+	ParentID    *uuid.UUID `json:"parent_id,omitempty"`
+	Title       string     `json:"title"`
+	Description *string    `json:"description,omitempty"`
+	Status      Status     `json:"status"`
+	Priority    Priority   `json:"priority"`
+	DueDate     time.Time  `json:"due_date"`
+	OverdueAt   *time.Time `json:"overdue_at,omitempty"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Labels      []Label    `json:"labels"`
+	Progress    Progress   `json:"progress"`
+	Blocked     bool       `json:"blocked"`
+	Blockers    []TaskRef  `json:"blockers"`
+	SeriesID    *uuid.UUID `json:"series_id,omitempty"`
+	Occurrence  *int       `json:"occurrence,omitempty"`
+	Recurrence  *string    `json:"recurrence,omitempty"`
+	Timezone    *string    `json:"timezone,omitempty"`
+}
+
+func (p Priority) Valid() bool {
+	switch p {
+	case PriorityLow, PriorityMedium, PriorityHigh:
+		return true
+	}
+	return false
+}
+
+// Rank - вес приоритета, используется для сортировки (high > medium > low)
+func (p Priority) Rank() int {
+	switch p {
+	case PriorityLow:
+		return 1
+	case PriorityMedium:
+		return 2
+	case PriorityHigh:
+		return 3
+	}
+	return 0
 }
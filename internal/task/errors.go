@@ -0,0 +1,87 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+	"task-manager/internal/task/entity"
+	"task-manager/pkg/apperror"
+)
+
+var (
+	// ErrNotFound - задача не найдена или принадлежит другому пользователю
+	ErrNotFound = apperror.New(apperror.ErrNotFound, "task not found")
+	// ErrInvalidCursor - курсор пагинации поврежден или получен не от этого API
+	ErrInvalidCursor = apperror.New(apperror.ErrInvalidInput, "invalid cursor")
+	// ErrLabelNotFound - метка не найдена или принадлежит другому пользователю
+	ErrLabelNotFound = apperror.New(apperror.ErrNotFound, "label not found")
+	// ErrLabelExists - у пользователя уже есть метка с таким именем
+	ErrLabelExists = apperror.New(apperror.ErrConflict, "label with this name already exists")
+	// ErrChecklistItemNotFound - пункт чек-листа не найден в задаче
+	ErrChecklistItemNotFound = apperror.New(apperror.ErrNotFound, "checklist item not found")
+	// ErrDependencyCycle - новое ребро замкнуло бы цикл в графе зависимостей
+	ErrDependencyCycle = apperror.New(apperror.ErrConflict, "dependency would create a cycle")
+	// ErrDependencyNotFound - задача не блокируется указанной задачей
+	ErrDependencyNotFound = apperror.New(apperror.ErrNotFound, "dependency not found")
+	// ErrSeriesNotFound - серия повторяющейся задачи не найдена
+	ErrSeriesNotFound = apperror.New(apperror.ErrNotFound, "task series not found")
+	// ErrReminderNotFound - напоминание не найдено в задаче
+	ErrReminderNotFound = apperror.New(apperror.ErrNotFound, "reminder not found")
+	// ErrReminderExists - у задачи уже есть напоминание с таким смещением
+	ErrReminderExists = apperror.New(apperror.ErrConflict, "reminder with this offset already exists")
+	// ErrUnknownChannel - доска не знает канала с таким именем
+	ErrUnknownChannel = apperror.New(apperror.ErrInvalidInput, "unknown channel, expected tasks or task:<id>")
+	// ErrStaleFence - лок лидера перехвачен другой репликой, запись прежнего лидера отклонена
+	ErrStaleFence = apperror.New(apperror.ErrConflict, "leader lock fencing token is stale")
+)
+
+// ValidationError - некорректное значение поля задачи
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Reason)
+}
+
+func (e *ValidationError) Unwrap() error         { return apperror.ErrValidation }
+func (e *ValidationError) Kind() error           { return apperror.ErrValidation }
+func (e *ValidationError) PublicMessage() string { return e.Error() }
+
+// BlockedError - задачу нельзя начать или завершить, пока открыты блокирующие ее задачи
+type BlockedError struct {
+	Blockers []entity.TaskRef
+}
+
+func (e *BlockedError) Error() string {
+	titles := make([]string, 0, len(e.Blockers))
+	for _, blocker := range e.Blockers {
+		titles = append(titles, fmt.Sprintf("%q", blocker.Title))
+	}
+	return fmt.Sprintf("task is blocked by unfinished tasks: %s", strings.Join(titles, ", "))
+}
+
+func (e *BlockedError) Unwrap() error         { return apperror.ErrConflict }
+func (e *BlockedError) Kind() error           { return apperror.ErrConflict }
+func (e *BlockedError) PublicMessage() string { return e.Error() }
+
+// TransitionError - переход между статусами запрещен workflow
+type TransitionError struct {
+	From   entity.Status
+	To     entity.Status
+	Action Action
+}
+
+func (e *TransitionError) Error() string {
+	if e.Action == ActionReopen {
+		return fmt.Sprintf("task in status %s cannot be reopened", e.From)
+	}
+	if e.From == entity.StatusDone && e.Action == ActionUpdate {
+		return fmt.Sprintf("transition from %s to %s is not allowed, reopen the task instead", e.From, e.To)
+	}
+	return fmt.Sprintf("transition from %s to %s is not allowed", e.From, e.To)
+}
+
+func (e *TransitionError) Unwrap() error         { return apperror.ErrConflict }
+func (e *TransitionError) Kind() error           { return apperror.ErrConflict }
+func (e *TransitionError) PublicMessage() string { return e.Error() }
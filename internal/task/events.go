@@ -0,0 +1,78 @@
+package task
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"task-manager/internal/task/entity"
+	"time"
+)
+
+// EventType - тип события жизненного цикла задачи
+type EventType string
+
+const (
+	EventTaskCreated       EventType = "task.created"
+	EventTaskUpdated       EventType = "task.updated"
+	EventTaskStatusChanged EventType = "task.status_changed"
+	EventTaskDeleted       EventType = "task.deleted"
+)
+
+// EventTypes - все типы событий в порядке жизненного цикла
+var EventTypes = []EventType{
+	EventTaskCreated,
+	EventTaskUpdated,
+	EventTaskStatusChanged,
+	EventTaskDeleted,
+}
+
+func (t EventType) Valid() bool {
+	switch t {
+	case EventTaskCreated, EventTaskUpdated, EventTaskStatusChanged, EventTaskDeleted:
+		return true
+	}
+	return false
+}
+
+// Event - изменение задачи. Репозиторий пишет событие в outbox в транзакции изменения,
+// подписчики получают его из стрима tasks:events. Task - состояние после изменения,
+// для task.deleted не заполняется.
+type Event struct {
+	ID         uuid.UUID      `json:"id"`
+	Type       EventType      `json:"type"`
+	UserID     int64          `json:"user_id"`
+	TaskID     uuid.UUID      `json:"task_id"`
+	Task       *entity.Task   `json:"task,omitempty"`
+	OldStatus  *entity.Status `json:"old_status,omitempty"`
+	OccurredAt time.Time      `json:"occurred_at"`
+}
+
+func NewEvent(typ EventType, userID int64, taskID uuid.UUID, t *entity.Task) Event {
+	return Event{
+		ID:         uuid.New(),
+		Type:       typ,
+		UserID:     userID,
+		TaskID:     taskID,
+		Task:       t,
+		OccurredAt: time.Now(),
+	}
+}
+
+// Группы потребителей стрима событий задач: каждая получает все события независимо
+const (
+	EventGroupWebhooks = "webhooks"
+	EventGroupLive     = "live"
+)
+
+// EventHandler обрабатывает событие задачи из стрима outbox. Ошибка оставляет событие
+// неподтвержденным, и оно придет снова, поэтому обработка должна быть идемпотентной.
+type EventHandler interface {
+	Handle(ctx context.Context, event Event) error
+}
+
+// LiveSubscription - подписка на живые события задач пользователя.
+// Events закрывается после Close, при обрыве соединения с Redis или если подписчик
+// не успевает забирать события - клиент переподключается с Last-Event-ID.
+type LiveSubscription interface {
+	Events() <-chan entity.LiveEvent
+	Close() error
+}
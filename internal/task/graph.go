@@ -0,0 +1,118 @@
+package task
+
+import (
+	"github.com/google/uuid"
+	"sort"
+	"task-manager/internal/task/entity"
+)
+
+// TopologicalOrder упорядочивает задачи так, что каждая блокирующая задача идет раньше
+// блокируемой. Из готовых к выполнению задач первой берется задача с ближайшим сроком.
+// Ребра, один из концов которых не входит в tasks, игнорируются.
+func TopologicalOrder(tasks []entity.TaskRef, edges []entity.Dependency) ([]entity.TaskRef, error) {
+	byID := make(map[uuid.UUID]entity.TaskRef, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+
+	inDegree := make(map[uuid.UUID]int, len(tasks))
+	next := make(map[uuid.UUID][]uuid.UUID, len(tasks))
+	for _, edge := range edges {
+		if _, ok := byID[edge.BlockerID]; !ok {
+			continue
+		}
+		if _, ok := byID[edge.BlockedID]; !ok {
+			continue
+		}
+		next[edge.BlockerID] = append(next[edge.BlockerID], edge.BlockedID)
+		inDegree[edge.BlockedID]++
+	}
+
+	var ready []entity.TaskRef
+	for _, t := range tasks {
+		if inDegree[t.ID] == 0 {
+			ready = append(ready, t)
+		}
+	}
+
+	order := make([]entity.TaskRef, 0, len(tasks))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool {
+			if !ready[i].DueDate.Equal(ready[j].DueDate) {
+				return ready[i].DueDate.Before(ready[j].DueDate)
+			}
+			return ready[i].ID.String() < ready[j].ID.String()
+		})
+
+		current := ready[0]
+		ready = ready[1:]
+		order = append(order, current)
+
+		for _, id := range next[current.ID] {
+			inDegree[id]--
+			if inDegree[id] == 0 {
+				ready = append(ready, byID[id])
+			}
+		}
+	}
+
+	if len(order) != len(byID) {
+		return nil, ErrDependencyCycle
+	}
+	return order, nil
+}
+
+// CriticalPath возвращает самую длинную цепочку невыполненных задач, связанных зависимостями.
+// Ее длина - минимальное число последовательных шагов до завершения всего набора.
+func CriticalPath(order []entity.TaskRef, edges []entity.Dependency) []entity.TaskRef {
+	position := make(map[uuid.UUID]int, len(order))
+	for i, t := range order {
+		position[t.ID] = i
+	}
+
+	prev := make(map[uuid.UUID][]uuid.UUID, len(order))
+	for _, edge := range edges {
+		_, okBlocker := position[edge.BlockerID]
+		_, okBlocked := position[edge.BlockedID]
+		if okBlocker && okBlocked {
+			prev[edge.BlockedID] = append(prev[edge.BlockedID], edge.BlockerID)
+		}
+	}
+
+	length := make([]int, len(order))
+	from := make([]int, len(order))
+	best := -1
+	for i, t := range order {
+		weight := 1
+		if t.Status == entity.StatusDone {
+			weight = 0
+		}
+
+		from[i] = -1
+		for _, id := range prev[t.ID] {
+			j := position[id]
+			if from[i] == -1 || length[j] > length[from[i]] {
+				from[i] = j
+			}
+		}
+		if from[i] >= 0 {
+			length[i] = length[from[i]]
+		}
+		length[i] += weight
+
+		if length[i] > 0 && (best == -1 || length[i] > length[best]) {
+			best = i
+		}
+	}
+
+	var path []entity.TaskRef
+	for i := best; i >= 0; i = from[i] {
+		if order[i].Status != entity.StatusDone {
+			path = append(path, order[i])
+		}
+	}
+	for l, r := 0, len(path)-1; l < r; l, r = l+1, r-1 {
+		path[l], path[r] = path[r], path[l]
+	}
+	return path
+}
@@ -0,0 +1,168 @@
+package task
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"task-manager/internal/task/entity"
+	"testing"
+	"time"
+)
+
+func ref(n byte, status entity.Status, due time.Time) entity.TaskRef {
+	return entity.TaskRef{ID: uuid.UUID{n}, Title: string('A' + n - 1), Status: status, DueDate: due}
+}
+
+func edge(blocker, blocked entity.TaskRef) entity.Dependency {
+	return entity.Dependency{BlockerID: blocker.ID, BlockedID: blocked.ID}
+}
+
+func titles(refs []entity.TaskRef) string {
+	out := make([]byte, 0, len(refs))
+	for _, r := range refs {
+		out = append(out, r.Title...)
+	}
+	return string(out)
+}
+
+func TestTopologicalOrder(t *testing.T) {
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := ref(1, entity.StatusPending, day.AddDate(0, 0, 3))
+	b := ref(2, entity.StatusPending, day.AddDate(0, 0, 1))
+	c := ref(3, entity.StatusPending, day.AddDate(0, 0, 2))
+	d := ref(4, entity.StatusPending, day.AddDate(0, 0, 2))
+	outside := ref(9, entity.StatusPending, day)
+
+	tests := []struct {
+		name    string
+		tasks   []entity.TaskRef
+		edges   []entity.Dependency
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "no edges sorts by due date",
+			tasks: []entity.TaskRef{a, b, c},
+			want:  "BCA",
+		},
+		{
+			name:  "equal due dates fall back to id",
+			tasks: []entity.TaskRef{d, c},
+			want:  "CD",
+		},
+		{
+			name:  "blocker goes before blocked despite later due date",
+			tasks: []entity.TaskRef{a, b, c},
+			edges: []entity.Dependency{edge(a, b)},
+			want:  "CAB",
+		},
+		{
+			name:  "chain",
+			tasks: []entity.TaskRef{a, b, c},
+			edges: []entity.Dependency{edge(a, c), edge(c, b)},
+			want:  "ACB",
+		},
+		{
+			name:  "edges to tasks outside the set are ignored",
+			tasks: []entity.TaskRef{a, b},
+			edges: []entity.Dependency{edge(outside, b), edge(a, outside)},
+			want:  "BA",
+		},
+		{
+			name:    "two-node cycle",
+			tasks:   []entity.TaskRef{a, b},
+			edges:   []entity.Dependency{edge(a, b), edge(b, a)},
+			wantErr: ErrDependencyCycle,
+		},
+		{
+			name:    "cycle behind an acyclic prefix",
+			tasks:   []entity.TaskRef{a, b, c, d},
+			edges:   []entity.Dependency{edge(a, b), edge(b, c), edge(c, d), edge(d, b)},
+			wantErr: ErrDependencyCycle,
+		},
+		{
+			name:    "self loop",
+			tasks:   []entity.TaskRef{a},
+			edges:   []entity.Dependency{edge(a, a)},
+			wantErr: ErrDependencyCycle,
+		},
+		{
+			name: "empty set",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := TopologicalOrder(tt.tasks, tt.edges)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := titles(order); got != tt.want {
+				t.Errorf("order = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCriticalPath(t *testing.T) {
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := ref(1, entity.StatusPending, day)
+	b := ref(2, entity.StatusPending, day.AddDate(0, 0, 1))
+	c := ref(3, entity.StatusPending, day.AddDate(0, 0, 2))
+	d := ref(4, entity.StatusPending, day.AddDate(0, 0, 3))
+	doneA := ref(1, entity.StatusDone, day)
+	doneB := ref(2, entity.StatusDone, day.AddDate(0, 0, 1))
+
+	tests := []struct {
+		name  string
+		tasks []entity.TaskRef
+		edges []entity.Dependency
+		want  string
+	}{
+		{
+			name:  "independent tasks give a single step",
+			tasks: []entity.TaskRef{a, b},
+			want:  "A",
+		},
+		{
+			name:  "longest of two branches",
+			tasks: []entity.TaskRef{a, b, c, d},
+			edges: []entity.Dependency{edge(a, d), edge(b, c), edge(c, d)},
+			want:  "BCD",
+		},
+		{
+			name:  "done tasks do not count and are dropped from the path",
+			tasks: []entity.TaskRef{doneA, b, c},
+			edges: []entity.Dependency{edge(doneA, b), edge(b, c)},
+			want:  "BC",
+		},
+		{
+			name:  "all done gives an empty path",
+			tasks: []entity.TaskRef{doneA, doneB},
+			edges: []entity.Dependency{edge(doneA, doneB)},
+			want:  "",
+		},
+		{
+			name: "empty set",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := TopologicalOrder(tt.tasks, tt.edges)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := titles(CriticalPath(order, tt.edges)); got != tt.want {
+				t.Errorf("critical path = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package task
 
 import (
 	"context"
+	"github.com/google/uuid"
 	"task-manager/internal/task/dtos"
 	"task-manager/internal/task/entity"
 	"time"
@@ -11,11 +12,103 @@ type TaskRepository interface {
 	Create(ctx context.Context, task *entity.Task) error
 	GetByID(ctx context.Context, id string) (*entity.Task, error)
 	Update(ctx context.Context, task *entity.Task) error
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, policy entity.ParentDeletePolicy) error
+	Depth(ctx context.Context, id string) (int, error)
 	List(
 		ctx context.Context,
 		filter dtos.Filter,
 		pagination dtos.Pagination,
-	) ([]*entity.Task, error)
+	) (*dtos.TaskPage, error)
+	ListAll(
+		ctx context.Context,
+		filter dtos.Filter,
+		pagination dtos.Pagination,
+	) (*dtos.TaskPage, error)
+	Search(ctx context.Context, req dtos.SearchRequest) (*dtos.SearchPage, error)
 	GetOverdue(ctx context.Context, threshold time.Time) ([]*entity.Task, error)
+	GetStatusHistory(ctx context.Context, id string) ([]*entity.StatusChange, error)
+	ApplyOverduePolicy(
+		ctx context.Context,
+		fence entity.Fence,
+		policy entity.OverduePolicy,
+		onParentDelete entity.ParentDeletePolicy,
+		threshold time.Time,
+	) ([]*entity.Task, error)
+}
+
+type LabelRepository interface {
+	CreateLabel(ctx context.Context, label *entity.Label) error
+	GetLabel(ctx context.Context, id int64) (*entity.Label, error)
+	ListLabels(ctx context.Context) ([]entity.Label, error)
+	UpdateLabel(ctx context.Context, label *entity.Label) error
+	DeleteLabel(ctx context.Context, id int64) error
+	AttachLabel(ctx context.Context, taskID string, labelID int64) error
+	DetachLabel(ctx context.Context, taskID string, labelID int64) error
+}
+
+type ChecklistRepository interface {
+	ListItems(ctx context.Context, taskID string) ([]entity.ChecklistItem, error)
+	CreateItem(ctx context.Context, taskID string, item *entity.ChecklistItem) error
+	GetItem(ctx context.Context, taskID string, itemID int64) (*entity.ChecklistItem, error)
+	UpdateItem(ctx context.Context, item *entity.ChecklistItem) error
+	DeleteItem(ctx context.Context, taskID string, itemID int64) error
+	ReorderItems(ctx context.Context, taskID string, itemIDs []int64) error
+}
+
+type DependencyRepository interface {
+	AddDependency(ctx context.Context, blockedID, blockerID string) error
+	RemoveDependency(ctx context.Context, blockedID, blockerID string) error
+	Graph(ctx context.Context, ids []uuid.UUID) ([]entity.TaskRef, []entity.Dependency, error)
+}
+
+type RecurrenceRepository interface {
+	GetSeries(ctx context.Context, id uuid.UUID) (*entity.Series, error)
+	DueSeries(ctx context.Context, now time.Time, limit int) ([]*entity.Series, error)
+	CreateOccurrence(
+		ctx context.Context,
+		fence *entity.Fence,
+		series *entity.Series,
+		occurrence int,
+		dueDate time.Time,
+	) (*entity.Task, error)
+	FinishSeries(ctx context.Context, fence *entity.Fence, id uuid.UUID) error
+	ReplaceSeries(ctx context.Context, task *entity.Task, series *entity.Series) error
+}
+
+type ReminderRepository interface {
+	ListReminders(ctx context.Context, taskID string) ([]entity.Reminder, error)
+	CreateReminder(ctx context.Context, taskID string, reminder *entity.Reminder) error
+	DeleteReminder(ctx context.Context, taskID string, id int64) error
+	TaskReminders(ctx context.Context, taskID uuid.UUID) ([]entity.Reminder, error)
+	Schedule(ctx context.Context, id int64, at time.Time) error
+	Unschedule(ctx context.Context, ids ...int64) error
+	ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]int64, time.Time, error)
+	GetDue(ctx context.Context, ids []int64) ([]*entity.DueReminder, error)
+	MarkSent(ctx context.Context, id int64, dueDate time.Time) error
+	Ack(ctx context.Context, leaseUntil time.Time, ids ...int64) error
+}
+
+// OutboxRepository читает события из outbox, публикует их в Redis Stream и подтверждает
+type OutboxRepository interface {
+	Pending(ctx context.Context, limit int) ([]entity.OutboxMessage, error)
+	Publish(ctx context.Context, fence entity.Fence, message *entity.OutboxMessage) (string, error)
+	MarkPublished(ctx context.Context, fence entity.Fence, ids []int64, at time.Time) error
+	DeletePublished(ctx context.Context, before time.Time) (int64, error)
+}
+
+// EventStreamRepository читает стрим событий outbox от имени группы потребителей
+// и подтверждает обработанные записи
+type EventStreamRepository interface {
+	CreateGroup(ctx context.Context, group string) error
+	Read(ctx context.Context, group string, limit int) ([]entity.StreamMessage, error)
+	Ack(ctx context.Context, group string, ids ...string) error
+}
+
+// LiveEventRepository хранит ограниченный бэклог живых событий пользователя и
+// рассылает новые события всем репликам API
+type LiveEventRepository interface {
+	Append(ctx context.Context, userID int64, data []byte) (string, error)
+	Since(ctx context.Context, userID int64, after string) ([]entity.LiveEvent, error)
+	OldestID(ctx context.Context, userID int64) (string, error)
+	Subscribe(ctx context.Context, userID int64) (LiveSubscription, error)
 }
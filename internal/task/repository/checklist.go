@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"task-manager/internal/task"
+	"task-manager/internal/task/entity"
+	"task-manager/pkg/database/redis"
+	"task-manager/pkg/identity"
+	"time"
+)
+
+const checklistColumns = "id, task_id, text, done, position, created_at, updated_at"
+
+func scanChecklistItem(row rowScanner, item *entity.ChecklistItem) error {
+	return row.Scan(
+		&item.ID,
+		&item.TaskID,
+		&item.Text,
+		&item.Done,
+		&item.Position,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+}
+
+type ChecklistRepository struct {
+	db    *sql.DB
+	redis *redis.Client
+	log   *zap.Logger
+}
+
+func NewChecklistRepository(db *sql.DB, redis *redis.Client, log *zap.Logger) task.ChecklistRepository {
+	return &ChecklistRepository{
+		db:    db,
+		redis: redis,
+		log:   log.Named("checklist_repository"),
+	}
+}
+
+func (r *ChecklistRepository) ListItems(ctx context.Context, taskID string) ([]entity.ChecklistItem, error) {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := ownedTask(ctx, r.db, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + checklistColumns + ` FROM checklist_items WHERE task_id = $1 ORDER BY position, id`
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		r.log.Error("Failed to list checklist items",
+			zap.Error(err),
+			zap.String("task_id", taskID),
+		)
+		return nil, fmt.Errorf("failed to list checklist items: %w", err)
+	}
+	defer rows.Close()
+
+	items := []entity.ChecklistItem{}
+	for rows.Next() {
+		var item entity.ChecklistItem
+		if err := scanChecklistItem(rows, &item); err != nil {
+			return nil, fmt.Errorf("failed to scan checklist item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return items, nil
+}
+
+// lockChecklist проверяет, что задача принадлежит пользователю, и блокирует ее строку
+// до конца транзакции tx. Добавление и перестановка пунктов одной задачи идут
+// под этой блокировкой друг за другом, поэтому позиции не повторяются.
+func lockChecklist(ctx context.Context, tx *sql.Tx, taskID string, userID int64) (uuid.UUID, error) {
+	id, err := uuid.Parse(taskID)
+	if err != nil {
+		return uuid.Nil, task.ErrNotFound
+	}
+
+	err = tx.QueryRowContext(ctx,
+		`SELECT id FROM tasks WHERE id = $1 AND user_id = $2 FOR UPDATE`, id, userID,
+	).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, task.ErrNotFound
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to lock task: %w", err)
+	}
+	return id, nil
+}
+
+// CreateItem добавляет пункт в конец чек-листа задачи
+func (r *ChecklistRepository) CreateItem(ctx context.Context, taskID string, item *entity.ChecklistItem) error {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	id, err := lockChecklist(ctx, tx, taskID, userID)
+	if err != nil {
+		return err
+	}
+
+	item.TaskID = id
+	item.CreatedAt = time.Now()
+	item.UpdatedAt = item.CreatedAt
+
+	query := `
+		INSERT INTO checklist_items (task_id, text, done, position, created_at, updated_at)
+		SELECT $1, $2, FALSE, COALESCE(MAX(position), 0) + 1, $3, $3
+		FROM checklist_items WHERE task_id = $1
+		RETURNING id, position`
+
+	if err := tx.QueryRowContext(ctx, query, id, item.Text, item.CreatedAt).Scan(&item.ID, &item.Position); err != nil {
+		r.log.Error("Failed to create checklist item",
+			zap.Error(err),
+			zap.String("task_id", taskID),
+		)
+		return fmt.Errorf("failed to create checklist item: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit checklist item: %w", err)
+	}
+
+	invalidateTasks(ctx, r.redis, r.log, id)
+	return nil
+}
+
+func (r *ChecklistRepository) GetItem(ctx context.Context, taskID string, itemID int64) (*entity.ChecklistItem, error) {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := ownedTask(ctx, r.db, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var item entity.ChecklistItem
+	query := `SELECT ` + checklistColumns + ` FROM checklist_items WHERE id = $1 AND task_id = $2`
+	if err := scanChecklistItem(r.db.QueryRowContext(ctx, query, itemID, id), &item); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, task.ErrChecklistItemNotFound
+		}
+		return nil, fmt.Errorf("failed to get checklist item: %w", err)
+	}
+	return &item, nil
+}
+
+// UpdateItem сохраняет текст и отметку выполнения пункта
+func (r *ChecklistRepository) UpdateItem(ctx context.Context, item *entity.ChecklistItem) error {
+	item.UpdatedAt = time.Now()
+
+	query := `UPDATE checklist_items SET text = $1, done = $2, updated_at = $3 WHERE id = $4 AND task_id = $5`
+	result, err := r.db.ExecContext(ctx, query, item.Text, item.Done, item.UpdatedAt, item.ID, item.TaskID)
+	if err != nil {
+		r.log.Error("Failed to update checklist item",
+			zap.Error(err),
+			zap.Int64("item_id", item.ID),
+		)
+		return fmt.Errorf("failed to update checklist item: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return task.ErrChecklistItemNotFound
+	}
+
+	invalidateTasks(ctx, r.redis, r.log, item.TaskID)
+	return nil
+}
+
+func (r *ChecklistRepository) DeleteItem(ctx context.Context, taskID string, itemID int64) error {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	id, err := ownedTask(ctx, r.db, taskID, userID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM checklist_items WHERE id = $1 AND task_id = $2`, itemID, id)
+	if err != nil {
+		r.log.Error("Failed to delete checklist item",
+			zap.Error(err),
+			zap.Int64("item_id", itemID),
+		)
+		return fmt.Errorf("failed to delete checklist item: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return task.ErrChecklistItemNotFound
+	}
+
+	invalidateTasks(ctx, r.redis, r.log, id)
+	return nil
+}
+
+// ReorderItems выставляет позиции пунктов в порядке itemIDs.
+// Список должен содержать ровно все пункты задачи, иначе порядок не меняется.
+func (r *ChecklistRepository) ReorderItems(ctx context.Context, taskID string, itemIDs []int64) error {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Под блокировкой задачи пункт не добавится между подсчетом и перестановкой
+	id, err := lockChecklist(ctx, tx, taskID, userID)
+	if err != nil {
+		return err
+	}
+
+	var count int
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM checklist_items WHERE task_id = $1`, id).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to count checklist items: %w", err)
+	}
+	if count != len(itemIDs) {
+		return &task.ValidationError{Field: "item_ids", Reason: "must list every checklist item of the task exactly once"}
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE checklist_items c
+		SET position = o.position, updated_at = NOW()
+		FROM unnest($2::bigint[]) WITH ORDINALITY AS o(id, position)
+		WHERE c.id = o.id AND c.task_id = $1`, id, pq.Array(itemIDs))
+	if err != nil {
+		r.log.Error("Failed to reorder checklist items",
+			zap.Error(err),
+			zap.String("task_id", taskID),
+		)
+		return fmt.Errorf("failed to reorder checklist items: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); int(rowsAffected) != len(itemIDs) {
+		return &task.ValidationError{Field: "item_ids", Reason: "must list every checklist item of the task exactly once"}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit checklist reorder: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"task-manager/internal/task"
+	"task-manager/internal/task/entity"
+	"task-manager/pkg/database/redis"
+	"task-manager/pkg/identity"
+)
+
+// maxPlanTasks ограничивает размер набора задач для построения плана
+const maxPlanTasks = 500
+
+type DependencyRepository struct {
+	db    *sql.DB
+	redis *redis.Client
+	log   *zap.Logger
+}
+
+func NewDependencyRepository(db *sql.DB, redis *redis.Client, log *zap.Logger) task.DependencyRepository {
+	return &DependencyRepository{
+		db:    db,
+		redis: redis,
+		log:   log.Named("dependency_repository"),
+	}
+}
+
+// AddDependency добавляет ребро "blockerID блокирует blockedID". Изменения графа одного
+// пользователя сериализуются advisory lock, чтобы две параллельные вставки не замкнули цикл.
+func (r *DependencyRepository) AddDependency(ctx context.Context, blockedID, blockerID string) error {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	blocked, err := ownedTask(ctx, r.db, blockedID, userID)
+	if err != nil {
+		return err
+	}
+	blocker, err := ownedTask(ctx, r.db, blockerID, userID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`SELECT pg_advisory_xact_lock(hashtextextended('task_dependencies:' || $1::text, 0))`, userID); err != nil {
+		return fmt.Errorf("failed to lock dependency graph: %w", err)
+	}
+
+	// Цикл появится, если blocked уже (транзитивно) блокирует blocker
+	var cycle bool
+	err = tx.QueryRowContext(ctx, `
+		WITH RECURSIVE reachable AS (
+			SELECT blocked_id FROM task_dependencies WHERE blocker_id = $1
+			UNION
+			SELECT d.blocked_id FROM task_dependencies d JOIN reachable r ON d.blocker_id = r.blocked_id
+		)
+		SELECT EXISTS (SELECT 1 FROM reachable WHERE blocked_id = $2)`,
+		blocked, blocker,
+	).Scan(&cycle)
+	if err != nil {
+		return fmt.Errorf("failed to check dependency cycle: %w", err)
+	}
+	if cycle {
+		r.log.Warn("Dependency cycle rejected",
+			zap.String("blocked_id", blockedID),
+			zap.String("blocker_id", blockerID),
+		)
+		return task.ErrDependencyCycle
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO task_dependencies (blocker_id, blocked_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+		blocker, blocked); err != nil {
+		r.log.Error("Failed to add dependency",
+			zap.Error(err),
+			zap.String("blocked_id", blockedID),
+			zap.String("blocker_id", blockerID),
+		)
+		return fmt.Errorf("failed to add dependency: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit dependency: %w", err)
+	}
+
+	invalidateTasks(ctx, r.redis, r.log, blocked)
+	return nil
+}
+
+func (r *DependencyRepository) RemoveDependency(ctx context.Context, blockedID, blockerID string) error {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	blocked, err := ownedTask(ctx, r.db, blockedID, userID)
+	if err != nil {
+		return err
+	}
+	blocker, err := uuid.Parse(blockerID)
+	if err != nil {
+		return task.ErrDependencyNotFound
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM task_dependencies WHERE blocker_id = $1 AND blocked_id = $2`, blocker, blocked)
+	if err != nil {
+		r.log.Error("Failed to remove dependency",
+			zap.Error(err),
+			zap.String("blocked_id", blockedID),
+			zap.String("blocker_id", blockerID),
+		)
+		return fmt.Errorf("failed to remove dependency: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return task.ErrDependencyNotFound
+	}
+
+	invalidateTasks(ctx, r.redis, r.log, blocked)
+	return nil
+}
+
+// Graph возвращает задачи пользователя из ids и ребра между ними.
+// Без ids берутся все невыполненные задачи пользователя.
+func (r *DependencyRepository) Graph(ctx context.Context, ids []uuid.UUID) ([]entity.TaskRef, []entity.Dependency, error) {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := `
+		SELECT id, title, status, due_date FROM tasks
+		WHERE user_id = $1 AND archived_at IS NULL AND status <> $2
+		ORDER BY due_date, id
+		LIMIT $3`
+	args := []interface{}{userID, entity.StatusDone, maxPlanTasks}
+	if len(ids) > 0 {
+		query = `
+			SELECT id, title, status, due_date FROM tasks
+			WHERE user_id = $1 AND id = ANY($2::uuid[])
+			ORDER BY due_date, id
+			LIMIT $3`
+		args = []interface{}{userID, pq.Array(uuidStrings(ids)), maxPlanTasks}
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load plan tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		tasks    []entity.TaskRef
+		selected []string
+	)
+	for rows.Next() {
+		var ref entity.TaskRef
+		if err := rows.Scan(&ref.ID, &ref.Title, &ref.Status, &ref.DueDate); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan plan task: %w", err)
+		}
+		tasks = append(tasks, ref)
+		selected = append(selected, ref.ID.String())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	edgeRows, err := r.db.QueryContext(ctx, `
+		SELECT blocker_id, blocked_id FROM task_dependencies
+		WHERE blocker_id = ANY($1::uuid[]) AND blocked_id = ANY($1::uuid[])`,
+		pq.Array(selected))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load dependencies: %w", err)
+	}
+	defer edgeRows.Close()
+
+	var edges []entity.Dependency
+	for edgeRows.Next() {
+		var edge entity.Dependency
+		if err := edgeRows.Scan(&edge.BlockerID, &edge.BlockedID); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan dependency: %w", err)
+		}
+		edges = append(edges, edge)
+	}
+	return tasks, edges, edgeRows.Err()
+}
+
+// dependents возвращает задачи, которые блокирует id: их флаг blocked зависит от статуса id
+func dependents(ctx context.Context, q queryer, id uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.QueryContext(ctx, `SELECT blocked_id FROM task_dependencies WHERE blocker_id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dependent tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var blocked uuid.UUID
+		if err := rows.Scan(&blocked); err != nil {
+			return nil, fmt.Errorf("failed to scan dependent task: %w", err)
+		}
+		ids = append(ids, blocked)
+	}
+	return ids, rows.Err()
+}
+
+// openBlockers возвращает невыполненные неархивные задачи, блокирующие id.
+// FOR SHARE не дает им сменить статус до конца транзакции.
+func openBlockers(ctx context.Context, tx *sql.Tx, id uuid.UUID) ([]entity.TaskRef, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT t.id, t.title, t.status, t.due_date
+		FROM task_dependencies d
+		JOIN tasks t ON t.id = d.blocker_id
+		WHERE d.blocked_id = $1 AND t.status <> $2 AND t.archived_at IS NULL
+		ORDER BY t.due_date, t.id
+		FOR SHARE OF t`, id, entity.StatusDone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load open blockers: %w", err)
+	}
+	defer rows.Close()
+
+	var open []entity.TaskRef
+	for rows.Next() {
+		var blocker entity.TaskRef
+		if err := rows.Scan(&blocker.ID, &blocker.Title, &blocker.Status, &blocker.DueDate); err != nil {
+			return nil, fmt.Errorf("failed to scan open blocker: %w", err)
+		}
+		open = append(open, blocker)
+	}
+	return open, rows.Err()
+}
+
+// loadBlockers подгружает блокирующие задачи и вычисляет флаг blocked
+func loadBlockers(ctx context.Context, db *sql.DB, tasks []*entity.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*entity.Task, len(tasks))
+	ids := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		t.Blocked = false
+		t.Blockers = []entity.TaskRef{}
+		byID[t.ID] = t
+		ids = append(ids, t.ID.String())
+	}
+
+	query := `
+		SELECT d.blocked_id, t.id, t.title, t.status, t.due_date, t.archived_at IS NOT NULL
+		FROM task_dependencies d
+		JOIN tasks t ON t.id = d.blocker_id
+		WHERE d.blocked_id = ANY($1::uuid[])
+		ORDER BY t.due_date, t.id`
+
+	rows, err := db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to load task blockers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			blockedID uuid.UUID
+			blocker   entity.TaskRef
+			archived  bool
+		)
+		if err := rows.Scan(&blockedID, &blocker.ID, &blocker.Title, &blocker.Status, &blocker.DueDate, &archived); err != nil {
+			return fmt.Errorf("failed to scan task blocker: %w", err)
+		}
+		t, ok := byID[blockedID]
+		if !ok {
+			continue
+		}
+		t.Blockers = append(t.Blockers, blocker)
+		// Архивная задача больше не в работе и не должна блокировать навсегда
+		if blocker.Status != entity.StatusDone && !archived {
+			t.Blocked = true
+		}
+	}
+	return rows.Err()
+}
+
+func uuidStrings(ids []uuid.UUID) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = id.String()
+	}
+	return out
+}
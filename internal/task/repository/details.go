@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"task-manager/internal/task/entity"
+)
+
+// loadDetails подгружает связанные с задачами данные, которые не хранятся в строке tasks
+func loadDetails(ctx context.Context, db *sql.DB, tasks []*entity.Task) error {
+	if err := loadLabels(ctx, db, tasks); err != nil {
+		return err
+	}
+	if err := loadBlockers(ctx, db, tasks); err != nil {
+		return err
+	}
+	if err := loadRecurrence(ctx, db, tasks); err != nil {
+		return err
+	}
+	return loadProgress(ctx, db, tasks)
+}
+
+// loadProgress считает выполненные пункты чек-листа и прямые подзадачи одним запросом
+func loadProgress(ctx context.Context, db *sql.DB, tasks []*entity.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*entity.Task, len(tasks))
+	ids := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		t.Progress = entity.Progress{}
+		byID[t.ID] = t
+		ids = append(ids, t.ID.String())
+	}
+
+	query := `
+		SELECT task_id, COUNT(*) FILTER (WHERE done), COUNT(*)
+		FROM (
+			SELECT task_id, done FROM checklist_items WHERE task_id = ANY($1::uuid[])
+			UNION ALL
+			SELECT parent_id, status = $2 FROM tasks
+			WHERE parent_id = ANY($1::uuid[]) AND archived_at IS NULL
+		) steps
+		GROUP BY task_id`
+
+	rows, err := db.QueryContext(ctx, query, pq.Array(ids), entity.StatusDone)
+	if err != nil {
+		return fmt.Errorf("failed to load task progress: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			taskID   uuid.UUID
+			progress entity.Progress
+		)
+		if err := rows.Scan(&taskID, &progress.Done, &progress.Total); err != nil {
+			return fmt.Errorf("failed to scan task progress: %w", err)
+		}
+		if t, ok := byID[taskID]; ok {
+			t.Progress = progress
+		}
+	}
+	return rows.Err()
+}
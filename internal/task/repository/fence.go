@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"task-manager/internal/task"
+	"task-manager/internal/task/entity"
+)
+
+// checkFence пропускает запись в транзакции tx, только если fencing token лока не
+// меньше последнего виденного, и запоминает его. Строка job_fences блокируется до
+// конца транзакции, поэтому запись прежнего лидера не закоммитится вперемешку с
+// записями нового. nil - запись идет не из-под лока лидера и не проверяется.
+func checkFence(ctx context.Context, tx *sql.Tx, fence *entity.Fence) error {
+	if fence == nil {
+		return nil
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO job_fences (name, token) VALUES ($1, $2)
+		ON CONFLICT (name) DO UPDATE SET token = EXCLUDED.token
+		WHERE job_fences.token <= EXCLUDED.token`,
+		fence.Name, fence.Token,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to check fencing token: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return task.ErrStaleFence
+	}
+	return nil
+}
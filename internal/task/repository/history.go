@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"task-manager/internal/task"
+	"task-manager/internal/task/entity"
+	"task-manager/pkg/identity"
+	"time"
+)
+
+// insertStatusChange пишет смену статуса в историю в рамках транзакции изменения задачи
+func insertStatusChange(
+	ctx context.Context,
+	tx *sql.Tx,
+	taskID uuid.UUID,
+	actorID int64,
+	oldStatus *entity.Status,
+	newStatus entity.Status,
+	changedAt time.Time,
+) error {
+	query := `
+		INSERT INTO task_status_history (task_id, actor_id, old_status, new_status, changed_at)
+		VALUES ($1, $2, $3, $4, $5)`
+
+	if _, err := tx.ExecContext(ctx, query, taskID, actorID, oldStatus, newStatus, changedAt); err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) GetStatusHistory(ctx context.Context, id string) ([]*entity.StatusChange, error) {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	taskID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, task.ErrNotFound
+	}
+
+	var exists bool
+	err = r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1 AND user_id = $2)`,
+		taskID, userID,
+	).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check task: %w", err)
+	}
+	if !exists {
+		r.log.Warn("Task not found for history",
+			zap.String("task_id", id),
+			zap.Int64("user_id", userID),
+		)
+		return nil, task.ErrNotFound
+	}
+
+	query := `
+		SELECT id, task_id, actor_id, old_status, new_status, changed_at
+		FROM task_status_history
+		WHERE task_id = $1
+		ORDER BY changed_at ASC, id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, taskID)
+	if err != nil {
+		r.log.Error("Failed to fetch status history",
+			zap.Error(err),
+			zap.String("task_id", id),
+		)
+		return nil, fmt.Errorf("failed to get status history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []*entity.StatusChange
+	for rows.Next() {
+		var change entity.StatusChange
+		if err := rows.Scan(
+			&change.ID,
+			&change.TaskID,
+			&change.ActorID,
+			&change.OldStatus,
+			&change.NewStatus,
+			&change.ChangedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan status change: %w", err)
+		}
+		history = append(history, &change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return history, nil
+}
@@ -0,0 +1,348 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"task-manager/internal/task"
+	"task-manager/internal/task/entity"
+	"task-manager/pkg/database/redis"
+	"task-manager/pkg/identity"
+	"time"
+)
+
+// uniqueViolation - код ошибки Postgres при нарушении уникального индекса
+const uniqueViolation = "23505"
+
+const labelColumns = "id, user_id, name, color, created_at, updated_at"
+
+func scanLabel(row rowScanner, label *entity.Label) error {
+	return row.Scan(
+		&label.ID,
+		&label.UserID,
+		&label.Name,
+		&label.Color,
+		&label.CreatedAt,
+		&label.UpdatedAt,
+	)
+}
+
+type LabelRepository struct {
+	db    *sql.DB
+	redis *redis.Client
+	log   *zap.Logger
+}
+
+func NewLabelRepository(db *sql.DB, redis *redis.Client, log *zap.Logger) task.LabelRepository {
+	return &LabelRepository{
+		db:    db,
+		redis: redis,
+		log:   log.Named("label_repository"),
+	}
+}
+
+func (r *LabelRepository) CreateLabel(ctx context.Context, label *entity.Label) error {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	label.UserID = userID
+	label.CreatedAt = time.Now()
+	label.UpdatedAt = label.CreatedAt
+
+	query := `
+		INSERT INTO labels (user_id, name, color, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`
+
+	err = r.db.QueryRowContext(ctx, query, label.UserID, label.Name, label.Color, label.CreatedAt, label.UpdatedAt).
+		Scan(&label.ID)
+	if isUniqueViolation(err) {
+		return task.ErrLabelExists
+	}
+	if err != nil {
+		r.log.Error("Failed to create label",
+			zap.Error(err),
+			zap.String("name", label.Name),
+		)
+		return fmt.Errorf("failed to create label: %w", err)
+	}
+
+	r.log.Info("Label created",
+		zap.Int64("label_id", label.ID),
+		zap.Int64("user_id", userID),
+	)
+	return nil
+}
+
+func (r *LabelRepository) GetLabel(ctx context.Context, id int64) (*entity.Label, error) {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var label entity.Label
+	query := `SELECT ` + labelColumns + ` FROM labels WHERE id = $1 AND user_id = $2`
+	if err := scanLabel(r.db.QueryRowContext(ctx, query, id, userID), &label); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, task.ErrLabelNotFound
+		}
+		return nil, fmt.Errorf("failed to get label: %w", err)
+	}
+	return &label, nil
+}
+
+func (r *LabelRepository) ListLabels(ctx context.Context) ([]entity.Label, error) {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + labelColumns + ` FROM labels WHERE user_id = $1 ORDER BY name`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		r.log.Error("Failed to list labels",
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("failed to list labels: %w", err)
+	}
+	defer rows.Close()
+
+	labels := []entity.Label{}
+	for rows.Next() {
+		var label entity.Label
+		if err := scanLabel(rows, &label); err != nil {
+			return nil, fmt.Errorf("failed to scan label: %w", err)
+		}
+		labels = append(labels, label)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return labels, nil
+}
+
+func (r *LabelRepository) UpdateLabel(ctx context.Context, label *entity.Label) error {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	label.UpdatedAt = time.Now()
+	query := `
+		UPDATE labels
+		SET name = $1, color = $2, updated_at = $3
+		WHERE id = $4 AND user_id = $5`
+
+	result, err := r.db.ExecContext(ctx, query, label.Name, label.Color, label.UpdatedAt, label.ID, userID)
+	if isUniqueViolation(err) {
+		return task.ErrLabelExists
+	}
+	if err != nil {
+		r.log.Error("Failed to update label",
+			zap.Error(err),
+			zap.Int64("label_id", label.ID),
+		)
+		return fmt.Errorf("failed to update label: %w", err)
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return task.ErrLabelNotFound
+	}
+
+	// Метка закеширована вместе с задачами, к которым она привязана
+	r.invalidateLabelTasks(ctx, label.ID)
+	return nil
+}
+
+func (r *LabelRepository) DeleteLabel(ctx context.Context, id int64) error {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Список задач нужен до удаления: связи удалятся каскадно вместе с меткой
+	taskIDs, err := r.labelTaskIDs(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM labels WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		r.log.Error("Failed to delete label",
+			zap.Error(err),
+			zap.Int64("label_id", id),
+		)
+		return fmt.Errorf("failed to delete label: %w", err)
+	}
+
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return task.ErrLabelNotFound
+	}
+
+	invalidateTasks(ctx, r.redis, r.log, taskIDs...)
+	r.log.Info("Label deleted",
+		zap.Int64("label_id", id),
+	)
+	return nil
+}
+
+// AttachLabel привязывает метку к задаче. Повторная привязка ничего не меняет.
+// И задача, и метка должны принадлежать текущему пользователю.
+func (r *LabelRepository) AttachLabel(ctx context.Context, taskID string, labelID int64) error {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	id, err := ownedTask(ctx, r.db, taskID, userID)
+	if err != nil {
+		return err
+	}
+	if _, err := r.GetLabel(ctx, labelID); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO task_labels (task_id, label_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	if _, err := r.db.ExecContext(ctx, query, id, labelID); err != nil {
+		r.log.Error("Failed to attach label",
+			zap.Error(err),
+			zap.String("task_id", taskID),
+			zap.Int64("label_id", labelID),
+		)
+		return fmt.Errorf("failed to attach label: %w", err)
+	}
+
+	invalidateTasks(ctx, r.redis, r.log, id)
+	return nil
+}
+
+// DetachLabel отвязывает метку от задачи. Отвязка непривязанной метки ничего не меняет.
+func (r *LabelRepository) DetachLabel(ctx context.Context, taskID string, labelID int64) error {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	id, err := ownedTask(ctx, r.db, taskID, userID)
+	if err != nil {
+		return err
+	}
+	if _, err := r.GetLabel(ctx, labelID); err != nil {
+		return err
+	}
+
+	query := `DELETE FROM task_labels WHERE task_id = $1 AND label_id = $2`
+	if _, err := r.db.ExecContext(ctx, query, id, labelID); err != nil {
+		r.log.Error("Failed to detach label",
+			zap.Error(err),
+			zap.String("task_id", taskID),
+			zap.Int64("label_id", labelID),
+		)
+		return fmt.Errorf("failed to detach label: %w", err)
+	}
+
+	invalidateTasks(ctx, r.redis, r.log, id)
+	return nil
+}
+
+// ownedTask проверяет, что задача существует и принадлежит пользователю
+func ownedTask(ctx context.Context, db *sql.DB, taskID string, userID int64) (uuid.UUID, error) {
+	id, err := uuid.Parse(taskID)
+	if err != nil {
+		return uuid.Nil, task.ErrNotFound
+	}
+
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1 AND user_id = $2)`
+	if err := db.QueryRowContext(ctx, query, id, userID).Scan(&exists); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to check task: %w", err)
+	}
+	if !exists {
+		return uuid.Nil, task.ErrNotFound
+	}
+	return id, nil
+}
+
+func (r *LabelRepository) labelTaskIDs(ctx context.Context, labelID int64) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT task_id FROM task_labels WHERE label_id = $1`, labelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list labeled tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan task id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *LabelRepository) invalidateLabelTasks(ctx context.Context, labelID int64) {
+	taskIDs, err := r.labelTaskIDs(ctx, labelID)
+	if err != nil {
+		r.log.Warn("Failed to collect tasks for cache invalidation",
+			zap.Error(err),
+			zap.Int64("label_id", labelID),
+		)
+		return
+	}
+	invalidateTasks(ctx, r.redis, r.log, taskIDs...)
+}
+
+// loadLabels подгружает метки для набора задач одним запросом
+func loadLabels(ctx context.Context, db *sql.DB, tasks []*entity.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*entity.Task, len(tasks))
+	ids := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		t.Labels = []entity.Label{}
+		byID[t.ID] = t
+		ids = append(ids, t.ID.String())
+	}
+
+	query := `
+		SELECT tl.task_id, l.id, l.user_id, l.name, l.color, l.created_at, l.updated_at
+		FROM task_labels tl
+		JOIN labels l ON l.id = tl.label_id
+		WHERE tl.task_id = ANY($1::uuid[])
+		ORDER BY l.name`
+
+	rows, err := db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to load task labels: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			taskID uuid.UUID
+			label  entity.Label
+		)
+		if err := rows.Scan(&taskID, &label.ID, &label.UserID, &label.Name, &label.Color, &label.CreatedAt, &label.UpdatedAt); err != nil {
+			return fmt.Errorf("failed to scan task label: %w", err)
+		}
+		if t, ok := byID[taskID]; ok {
+			t.Labels = append(t.Labels, label)
+		}
+	}
+	return rows.Err()
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"slices"
+	"strings"
+	"task-manager/internal/task/dtos"
+	"task-manager/internal/task/entity"
+)
+
+// sortColumn - SQL-выражение поля сортировки и тип, к которому приводится значение ключа из курсора
+type sortColumn struct {
+	expr string
+	cast string
+}
+
+// sortColumns должен покрывать все поля, разрешенные dtos.ParseSort
+var sortColumns = map[string]sortColumn{
+	"due_date":   {expr: "due_date", cast: "timestamptz"},
+	"created_at": {expr: "created_at", cast: "timestamptz"},
+	"updated_at": {expr: "updated_at", cast: "timestamptz"},
+	"priority":   {expr: rankExpr("priority", entity.Priorities, entity.Priority.Rank), cast: "int"},
+	"status":     {expr: rankExpr("status", entity.Statuses, entity.Status.Rank), cast: "int"},
+	"title":      {expr: "title", cast: "text"},
+}
+
+// rankExpr переводит значение колонки в ранг из entity, чтобы порядок в SQL совпадал
+// с ключами курсора. Значения берутся из констант entity, а не из запроса.
+func rankExpr[T ~string](column string, values []T, rank func(T) int) string {
+	var b strings.Builder
+	b.WriteString("CASE " + column)
+	for _, v := range values {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", v, rank(v))
+	}
+	b.WriteString(" ELSE 0 END")
+	return b.String()
+}
+
+// queryBuilder собирает условия WHERE с позиционными параметрами
+type queryBuilder struct {
+	where []string
+	args  []interface{}
+}
+
+// arg добавляет параметр и возвращает его плейсхолдер
+func (b *queryBuilder) arg(value interface{}) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+func (b *queryBuilder) add(cond string) {
+	b.where = append(b.where, cond)
+}
+
+func (b *queryBuilder) whereClause() string {
+	if len(b.where) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.where, " AND ")
+}
+
+// applyFilter переводит фильтр в условия. Все значения передаются параметрами.
+func applyFilter(b *queryBuilder, userID *int64, filter dtos.Filter) {
+	b.add("archived_at IS NULL")
+
+	if userID != nil {
+		b.add("user_id = " + b.arg(*userID))
+	}
+	if filter.ParentID != nil {
+		b.add("parent_id = " + b.arg(*filter.ParentID))
+	}
+	if len(filter.Statuses) > 0 {
+		b.add("status = ANY(" + b.arg(pq.Array(toStrings(filter.Statuses))) + ")")
+	}
+	if len(filter.Priorities) > 0 {
+		b.add("priority = ANY(" + b.arg(pq.Array(toStrings(filter.Priorities))) + ")")
+	}
+	if filter.DueAfter != nil {
+		b.add("due_date >= " + b.arg(*filter.DueAfter))
+	}
+	if filter.DueBefore != nil {
+		b.add("due_date < " + b.arg(*filter.DueBefore))
+	}
+	if filter.CreatedFrom != nil {
+		b.add("created_at >= " + b.arg(*filter.CreatedFrom))
+	}
+	if filter.CreatedTo != nil {
+		b.add("created_at < " + b.arg(*filter.CreatedTo))
+	}
+	if filter.OverdueOnly {
+		b.add("due_date < NOW() AND status <> " + b.arg(entity.StatusDone))
+	}
+	if len(filter.Labels) > 0 {
+		labels := b.arg(pq.Array(filter.Labels))
+		if filter.LabelsMatchAll {
+			b.add(fmt.Sprintf(`id IN (
+				SELECT tl.task_id FROM task_labels tl JOIN labels l ON l.id = tl.label_id
+				WHERE lower(l.name) = ANY(%s)
+				GROUP BY tl.task_id
+				HAVING COUNT(DISTINCT lower(l.name)) = %s)`, labels, b.arg(len(filter.Labels))))
+		} else {
+			b.add(fmt.Sprintf(`id IN (
+				SELECT tl.task_id FROM task_labels tl JOIN labels l ON l.id = tl.label_id
+				WHERE lower(l.name) = ANY(%s))`, labels))
+		}
+	}
+	if filter.Search != "" {
+		if query, ok := tsQuery(b, filter.Search); ok {
+			b.add("search_vector @@ " + query)
+		}
+	}
+}
+
+// applyCursor добавляет keyset-условие "строго после (или до) курсора" в порядке sort.
+// Для смешанных направлений сортировки row comparison не подходит, поэтому условие
+// раскрывается в (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ... с id последним ключом.
+func applyCursor(b *queryBuilder, sort dtos.Sort, cursor *dtos.Cursor) {
+	type key struct {
+		expr  string
+		value string
+		desc  bool
+	}
+
+	keys := make([]key, 0, len(sort)+1)
+	for i, field := range sort {
+		column := sortColumns[field.Field]
+		keys = append(keys, key{
+			expr:  column.expr,
+			value: b.arg(cursor.Keys[i]) + "::" + column.cast,
+			desc:  field.Desc,
+		})
+	}
+	keys = append(keys, key{expr: "id", value: b.arg(cursor.ID)})
+
+	var alternatives []string
+	for i, k := range keys {
+		conds := make([]string, 0, i+1)
+		for _, prev := range keys[:i] {
+			conds = append(conds, prev.expr+" = "+prev.value)
+		}
+
+		op := ">"
+		if k.desc != cursor.Backward {
+			op = "<"
+		}
+		conds = append(conds, k.expr+" "+op+" "+k.value)
+		alternatives = append(alternatives, "("+strings.Join(conds, " AND ")+")")
+	}
+	b.add("(" + strings.Join(alternatives, " OR ") + ")")
+}
+
+// orderBy строит ORDER BY для sort с id последним ключом. reverse инвертирует все направления
+// для выборки страницы перед курсором.
+func orderBy(sort dtos.Sort, reverse bool) string {
+	direction := func(desc bool) string {
+		if desc != reverse {
+			return "DESC"
+		}
+		return "ASC"
+	}
+
+	parts := make([]string, 0, len(sort)+1)
+	for _, field := range sort {
+		parts = append(parts, sortColumns[field.Field].expr+" "+direction(field.Desc))
+	}
+	parts = append(parts, "id "+direction(false))
+	return " ORDER BY " + strings.Join(parts, ", ")
+}
+
+// list выбирает страницу задач. С курсором используется keyset-выборка, без него -
+// LIMIT/OFFSET для обратной совместимости. Лишняя строка в выборке показывает,
+// есть ли следующая страница.
+func (r *Repository) list(
+	ctx context.Context,
+	userID *int64,
+	filter dtos.Filter,
+	pagination dtos.Pagination,
+) (*dtos.TaskPage, error) {
+	sort := pagination.Sort
+	if len(sort) == 0 {
+		sort = dtos.DefaultSort
+	}
+
+	b := &queryBuilder{}
+	applyFilter(b, userID, filter)
+
+	r.log.Debug("Listing tasks",
+		zap.Int64p("user_id", userID),
+		zap.Any("filter", filter),
+		zap.Any("pagination", pagination),
+	)
+
+	page := &dtos.TaskPage{Items: []*entity.Task{}}
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks"+b.whereClause(), b.args...).Scan(&page.Total)
+	if err != nil {
+		r.log.Error("Failed to count tasks",
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("failed to count tasks: %w", err)
+	}
+
+	cursor := pagination.Cursor
+	backward := cursor != nil && cursor.Backward
+	if cursor != nil {
+		applyCursor(b, sort, cursor)
+	}
+
+	query := "SELECT " + taskColumns + " FROM tasks" + b.whereClause() + orderBy(sort, backward)
+	if cursor == nil {
+		query += " LIMIT " + b.arg(pagination.Limit+1) + " OFFSET " + b.arg(pagination.Offset)
+	} else {
+		query += " LIMIT " + b.arg(pagination.Limit+1)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, b.args...)
+	if err != nil {
+		r.log.Error("Failed to list tasks",
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var task entity.Task
+		if err := scanTask(rows, &task); err != nil {
+			r.log.Error("Failed to scan task row",
+				zap.Error(err),
+			)
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		page.Items = append(page.Items, &task)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.log.Error("Error during rows iteration",
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	hasMore := len(page.Items) > pagination.Limit
+	if hasMore {
+		page.Items = page.Items[:pagination.Limit]
+	}
+	if backward {
+		slices.Reverse(page.Items)
+	}
+
+	if err := loadDetails(ctx, r.db, page.Items); err != nil {
+		return nil, err
+	}
+
+	if len(page.Items) > 0 {
+		first, last := page.Items[0], page.Items[len(page.Items)-1]
+		// Назад есть куда идти, если пришли по курсору вперед, сдвинулись по offset
+		// или при обратной выборке нашлись еще строки
+		if (cursor != nil && !backward) || (cursor == nil && pagination.Offset > 0) || (backward && hasMore) {
+			page.PrevCursor = dtos.CursorBefore(first, sort)
+		}
+		if hasMore || backward {
+			page.NextCursor = dtos.CursorAfter(last, sort)
+		}
+	}
+
+	r.log.Debug("Tasks listed successfully",
+		zap.Int("count", len(page.Items)),
+		zap.Int64("total", page.Total),
+	)
+	return page, nil
+}
+
+func toStrings[T ~string](values []T) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = string(v)
+	}
+	return out
+}
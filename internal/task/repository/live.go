@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"go.uber.org/zap"
+	"strconv"
+	"strings"
+	"sync"
+	"task-manager/internal/task"
+	"task-manager/internal/task/entity"
+	"task-manager/pkg/database/redis"
+	"time"
+)
+
+const (
+	livePrefix        = "tasks:live:"
+	liveChannelSuffix = ":channel"
+	// liveChannelPattern - каналы всех пользователей, на них реплика подписана одним соединением
+	liveChannelPattern = livePrefix + "*" + liveChannelSuffix
+	// liveBuffer - сколько событий ждет отстающего подписчика, прежде чем его отключить
+	liveBuffer = 64
+)
+
+// liveStream - бэклог живых событий пользователя для дочитывания по Last-Event-ID
+func liveStream(userID int64) string {
+	return livePrefix + strconv.FormatInt(userID, 10)
+}
+
+// liveChannel - Pub/Sub канал, через который реплики API получают новые события пользователя
+func liveChannel(userID int64) string {
+	return liveStream(userID) + liveChannelSuffix
+}
+
+// liveChannelUser достает ID пользователя из имени канала
+func liveChannelUser(channel string) (int64, bool) {
+	id, ok := strings.CutPrefix(channel, livePrefix)
+	if !ok {
+		return 0, false
+	}
+	id, ok = strings.CutSuffix(id, liveChannelSuffix)
+	if !ok {
+		return 0, false
+	}
+	userID, err := strconv.ParseInt(id, 10, 64)
+	return userID, err == nil
+}
+
+type LiveEventRepository struct {
+	redis   *redis.Client
+	backlog int64
+	ttl     time.Duration
+	hub     *liveHub
+	log     *zap.Logger
+}
+
+// NewLiveEventRepository хранит не больше backlog последних событий пользователя;
+// бэклог удаляется целиком, если событий не было дольше ttl
+func NewLiveEventRepository(
+	redis *redis.Client,
+	backlog int64,
+	ttl time.Duration,
+	log *zap.Logger,
+) task.LiveEventRepository {
+	log = log.Named("live_event_repository")
+	return &LiveEventRepository{
+		redis:   redis,
+		backlog: backlog,
+		ttl:     ttl,
+		hub:     newLiveHub(redis.PSubscribe, log),
+		log:     log,
+	}
+}
+
+// Append сохраняет событие в бэклоге пользователя и рассылает его подписчикам
+func (r *LiveEventRepository) Append(ctx context.Context, userID int64, data []byte) (string, error) {
+	id, err := r.redis.Broadcast(ctx, liveStream(userID), liveChannel(userID), r.backlog, r.ttl, data)
+	if err != nil {
+		r.log.Error("Failed to broadcast live event",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+		)
+		return "", err
+	}
+	return id, nil
+}
+
+// Since возвращает события бэклога после after в порядке добавления
+func (r *LiveEventRepository) Since(ctx context.Context, userID int64, after string) ([]entity.LiveEvent, error) {
+	entries, err := r.redis.StreamSince(ctx, liveStream(userID), after)
+	if err != nil {
+		r.log.Error("Failed to read live event backlog",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+		)
+		return nil, err
+	}
+
+	events := make([]entity.LiveEvent, 0, len(entries))
+	for _, e := range entries {
+		events = append(events, liveEvent(e))
+	}
+	return events, nil
+}
+
+// OldestID возвращает ID самого старого события в бэклоге, для пустого бэклога - ""
+func (r *LiveEventRepository) OldestID(ctx context.Context, userID int64) (string, error) {
+	return r.redis.StreamFirstID(ctx, liveStream(userID))
+}
+
+// Subscribe подписывает на события пользователя через общую подписку реплики
+func (r *LiveEventRepository) Subscribe(ctx context.Context, userID int64) (task.LiveSubscription, error) {
+	sub, err := r.hub.subscribe(ctx, userID)
+	if err != nil {
+		r.log.Error("Failed to subscribe to live events",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+		)
+		return nil, err
+	}
+	return sub, nil
+}
+
+func liveEvent(e redis.StreamEntry) entity.LiveEvent {
+	return entity.LiveEvent{ID: e.ID, Data: json.RawMessage(e.Data)}
+}
+
+// liveHub держит одну подписку Redis на все каналы пользователей и раздает события
+// подписчикам реплики. Подписка открывается при первом Subscribe, поэтому реплика,
+// которая только рассылает события, соединение не держит.
+type liveHub struct {
+	psubscribe func(ctx context.Context, pattern string) (*redis.Subscription, error)
+	log        *zap.Logger
+
+	mu        sync.Mutex
+	connected bool
+	subs      map[int64]map[*liveSubscription]struct{}
+}
+
+func newLiveHub(
+	psubscribe func(ctx context.Context, pattern string) (*redis.Subscription, error),
+	log *zap.Logger,
+) *liveHub {
+	return &liveHub{
+		psubscribe: psubscribe,
+		log:        log,
+		subs:       make(map[int64]map[*liveSubscription]struct{}),
+	}
+}
+
+// subscribe регистрирует подписчика. После возврата он получит все события,
+// разосланные позже: общая подписка к этому моменту уже подтверждена Redis.
+func (h *liveHub) subscribe(ctx context.Context, userID int64) (*liveSubscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.connected {
+		sub, err := h.psubscribe(ctx, liveChannelPattern)
+		if err != nil {
+			return nil, err
+		}
+		h.connected = true
+		go h.dispatch(sub.Entries())
+	}
+
+	s := &liveSubscription{
+		hub:    h,
+		userID: userID,
+		events: make(chan entity.LiveEvent, liveBuffer),
+	}
+	if h.subs[userID] == nil {
+		h.subs[userID] = make(map[*liveSubscription]struct{})
+	}
+	h.subs[userID][s] = struct{}{}
+	return s, nil
+}
+
+// dispatch раздает события подписчикам их владельца. Общий цикл не ждет отдельного
+// клиента: подписчик с заполненным буфером отключается и дочитает пропущенное
+// по Last-Event-ID после переподключения.
+func (h *liveHub) dispatch(entries <-chan redis.ChannelEntry) {
+	for e := range entries {
+		userID, ok := liveChannelUser(e.Channel)
+		if !ok {
+			continue
+		}
+		event := liveEvent(e.StreamEntry)
+
+		h.mu.Lock()
+		for s := range h.subs[userID] {
+			select {
+			case s.events <- event:
+			default:
+				h.log.Warn("Slow live subscriber disconnected", zap.Int64("user_id", userID))
+				h.removeLocked(s)
+			}
+		}
+		h.mu.Unlock()
+	}
+
+	// Подписка Redis закрыта: отключаем всех, следующий Subscribe подпишется заново
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.connected = false
+	for _, subs := range h.subs {
+		for s := range subs {
+			h.removeLocked(s)
+		}
+	}
+}
+
+func (h *liveHub) remove(s *liveSubscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.removeLocked(s)
+}
+
+func (h *liveHub) removeLocked(s *liveSubscription) {
+	subs, ok := h.subs[s.userID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[s]; !ok {
+		return
+	}
+	delete(subs, s)
+	if len(subs) == 0 {
+		delete(h.subs, s.userID)
+	}
+	close(s.events)
+}
+
+// liveSubscription - подписчик liveHub на события одного пользователя
+type liveSubscription struct {
+	hub    *liveHub
+	userID int64
+	events chan entity.LiveEvent
+}
+
+func (s *liveSubscription) Events() <-chan entity.LiveEvent {
+	return s.events
+}
+
+func (s *liveSubscription) Close() error {
+	s.hub.remove(s)
+	return nil
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"task-manager/pkg/database/redis"
+	"testing"
+	"time"
+)
+
+func TestLiveChannelUser(t *testing.T) {
+	if id, ok := liveChannelUser(liveChannel(42)); !ok || id != 42 {
+		t.Errorf("liveChannelUser(liveChannel(42)) = %d, %v", id, ok)
+	}
+	for _, channel := range []string{"tasks:live:42", "tasks:live:x:channel", "other:42:channel", ""} {
+		if _, ok := liveChannelUser(channel); ok {
+			t.Errorf("liveChannelUser(%q) accepted a foreign channel", channel)
+		}
+	}
+}
+
+// connectedHub возвращает хаб, уже подписанный на канал entries
+func connectedHub(t *testing.T) (*liveHub, chan redis.ChannelEntry) {
+	t.Helper()
+	h := newLiveHub(nil, zap.NewNop())
+	h.connected = true
+	entries := make(chan redis.ChannelEntry)
+	go h.dispatch(entries)
+	return h, entries
+}
+
+func entry(userID int64, id string) redis.ChannelEntry {
+	return redis.ChannelEntry{
+		Channel:     liveChannel(userID),
+		StreamEntry: redis.StreamEntry{ID: id, Data: "{}"},
+	}
+}
+
+func TestLiveHubRoutesEventsToOwner(t *testing.T) {
+	h, entries := connectedHub(t)
+	ctx := context.Background()
+
+	first, _ := h.subscribe(ctx, 1)
+	second, _ := h.subscribe(ctx, 1)
+	other, _ := h.subscribe(ctx, 2)
+
+	entries <- entry(1, "1-0")
+	entries <- entry(2, "2-0")
+
+	for _, s := range []*liveSubscription{first, second} {
+		if e := <-s.Events(); e.ID != "1-0" {
+			t.Errorf("user 1 got %q, want 1-0", e.ID)
+		}
+	}
+	if e := <-other.Events(); e.ID != "2-0" {
+		t.Errorf("user 2 got %q, want 2-0", e.ID)
+	}
+}
+
+func TestLiveHubClose(t *testing.T) {
+	h, entries := connectedHub(t)
+	ctx := context.Background()
+
+	s, _ := h.subscribe(ctx, 1)
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	// Повторное закрытие не паникует
+	s.Close()
+
+	if _, ok := <-s.Events(); ok {
+		t.Fatal("events channel is open after Close")
+	}
+	entries <- entry(1, "1-0")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.subs) != 0 {
+		t.Errorf("subscribers left after Close: %v", h.subs)
+	}
+}
+
+func TestLiveHubDisconnectsSlowSubscriber(t *testing.T) {
+	h, entries := connectedHub(t)
+	ctx := context.Background()
+
+	slow, _ := h.subscribe(ctx, 1)
+	fast, _ := h.subscribe(ctx, 1)
+
+	for i := 0; i <= liveBuffer; i++ {
+		entries <- entry(1, "1-0")
+		<-fast.Events()
+	}
+
+	// Буфер отстающего переполнился: он отключен, остальные получают события дальше
+	n := 0
+	for range slow.Events() {
+		n++
+	}
+	if n != liveBuffer {
+		t.Errorf("slow subscriber got %d buffered events, want %d", n, liveBuffer)
+	}
+
+	entries <- entry(1, "2-0")
+	select {
+	case e := <-fast.Events():
+		if e.ID != "2-0" {
+			t.Errorf("fast subscriber got %q, want 2-0", e.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("fast subscriber stopped receiving events")
+	}
+}
+
+func TestLiveHubClosesSubscribersWhenRedisSubscriptionEnds(t *testing.T) {
+	h, entries := connectedHub(t)
+
+	s, _ := h.subscribe(context.Background(), 1)
+	close(entries)
+
+	select {
+	case _, ok := <-s.Events():
+		if ok {
+			t.Fatal("unexpected event")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("subscriber was not closed")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.connected {
+		t.Error("hub still marked as connected")
+	}
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"strconv"
+	"task-manager/internal/task"
+	"task-manager/internal/task/entity"
+	"task-manager/pkg/database/redis"
+	"time"
+)
+
+const (
+	// outboxStream - Redis Stream, в который релей публикует события задач
+	outboxStream = "tasks:events"
+	// outboxStreamMaxLen - сколько последних событий хранит стрим
+	outboxStreamMaxLen = 100000
+	// outboxLockKey - ключ advisory-блокировки, под которой транзакции пишут в outbox
+	outboxLockKey = 0x7461736b6f7574 // "taskout"
+)
+
+// insertOutbox сохраняет события в outbox в транзакции изменения задачи:
+// событие публикуется тогда и только тогда, когда изменение закоммичено.
+// Вызывается последним перед коммитом: advisory-блокировка держится до конца
+// транзакции и выстраивает записи в outbox друг за другом, поэтому порядок id
+// совпадает с порядком коммитов и релей не обгонит еще не закоммиченную строку.
+func insertOutbox(ctx context.Context, tx *sql.Tx, events ...task.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, outboxLockKey); err != nil {
+		return fmt.Errorf("failed to lock outbox: %w", err)
+	}
+
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s event: %w", event.Type, err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO task_outbox (event_id, event_type, user_id, task_id, payload, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			event.ID, event.Type, event.UserID, event.TaskID, payload, event.OccurredAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to write %s event to outbox: %w", event.Type, err)
+		}
+	}
+	return nil
+}
+
+func createdEvent(t *entity.Task) task.Event {
+	return task.NewEvent(task.EventTaskCreated, t.UserID, t.ID, t)
+}
+
+// updatedEvents - task.updated и, если статус сменился, task.status_changed
+func updatedEvents(t *entity.Task, oldStatus entity.Status) []task.Event {
+	events := []task.Event{task.NewEvent(task.EventTaskUpdated, t.UserID, t.ID, t)}
+	if oldStatus != t.Status {
+		changed := task.NewEvent(task.EventTaskStatusChanged, t.UserID, t.ID, t)
+		changed.OldStatus = &oldStatus
+		events = append(events, changed)
+	}
+	return events
+}
+
+func deletedEvents(userID int64, ids []uuid.UUID) []task.Event {
+	events := make([]task.Event, 0, len(ids))
+	for _, id := range ids {
+		events = append(events, task.NewEvent(task.EventTaskDeleted, userID, id, nil))
+	}
+	return events
+}
+
+type OutboxRepository struct {
+	db    *sql.DB
+	redis *redis.Client
+	log   *zap.Logger
+}
+
+func NewOutboxRepository(db *sql.DB, redis *redis.Client, log *zap.Logger) task.OutboxRepository {
+	return &OutboxRepository{
+		db:    db,
+		redis: redis,
+		log:   log.Named("outbox_repository"),
+	}
+}
+
+// Pending возвращает неопубликованные события в порядке id. Запись в outbox идет под
+// общей advisory-блокировкой до коммита (см. insertOutbox), поэтому порядок id
+// совпадает с порядком коммитов: строка с меньшим id не появится позже прочитанных.
+func (r *OutboxRepository) Pending(ctx context.Context, limit int) ([]entity.OutboxMessage, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, event_id, event_type, user_id, task_id, payload, created_at, published_at
+		FROM task_outbox
+		WHERE published_at IS NULL
+		ORDER BY id
+		LIMIT $1`, limit)
+	if err != nil {
+		r.log.Error("Failed to get pending outbox messages", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []entity.OutboxMessage
+	for rows.Next() {
+		var m entity.OutboxMessage
+		if err := rows.Scan(
+			&m.ID,
+			&m.EventID,
+			&m.Type,
+			&m.UserID,
+			&m.TaskID,
+			(*[]byte)(&m.Payload),
+			&m.CreatedAt,
+			&m.PublishedAt,
+		); err != nil {
+			return nil, err
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+// Publish добавляет событие в стрим и возвращает ID записи в нем. Если лок лидера
+// fence уже перехвачен, событие не публикуется: порядок держится на одном релее.
+func (r *OutboxRepository) Publish(ctx context.Context, fence entity.Fence, m *entity.OutboxMessage) (string, error) {
+	id, err := r.redis.StreamAddFenced(ctx, outboxStream, outboxStreamMaxLen, fence.Name, fence.Token, map[string]interface{}{
+		"outbox_id": strconv.FormatInt(m.ID, 10),
+		"event_id":  m.EventID.String(),
+		"type":      m.Type,
+		"user_id":   strconv.FormatInt(m.UserID, 10),
+		"task_id":   m.TaskID.String(),
+		"payload":   string(m.Payload),
+	})
+	if errors.Is(err, redis.ErrLockLost) {
+		return "", task.ErrStaleFence
+	}
+	if err != nil {
+		r.log.Error("Failed to publish outbox message",
+			zap.Error(err),
+			zap.Int64("outbox_id", m.ID),
+		)
+		return "", err
+	}
+	return id, nil
+}
+
+// MarkPublished подтверждает публикацию событий под локом лидера fence
+func (r *OutboxRepository) MarkPublished(ctx context.Context, fence entity.Fence, ids []int64, at time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkFence(ctx, tx, &fence); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE task_outbox SET published_at = $2 WHERE id = ANY($1)`,
+		pq.Array(ids), at,
+	)
+	if err != nil {
+		r.log.Error("Failed to mark outbox messages published",
+			zap.Error(err),
+			zap.Int("messages", len(ids)),
+		)
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeletePublished удаляет события, опубликованные раньше before
+func (r *OutboxRepository) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM task_outbox WHERE published_at IS NOT NULL AND published_at < $1`, before,
+	)
+	if err != nil {
+		r.log.Error("Failed to clean up outbox", zap.Error(err))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// eventConsumer - имя потребителя в группах стрима событий. Группа читается одним
+// воркером под локом лидера, поэтому имя общее: неподтвержденные записи прежнего
+// лидера достаются следующему и обрабатываются первыми.
+const eventConsumer = "leader"
+
+type EventStreamRepository struct {
+	redis *redis.Client
+	log   *zap.Logger
+}
+
+func NewEventStreamRepository(redis *redis.Client, log *zap.Logger) task.EventStreamRepository {
+	return &EventStreamRepository{
+		redis: redis,
+		log:   log.Named("event_stream_repository"),
+	}
+}
+
+// CreateGroup создает группу потребителей, если ее еще нет. Группа читает события,
+// опубликованные после создания, поэтому создается до запуска релея.
+func (r *EventStreamRepository) CreateGroup(ctx context.Context, group string) error {
+	if err := r.redis.StreamCreateGroup(ctx, outboxStream, group); err != nil {
+		r.log.Error("Failed to create event consumer group",
+			zap.Error(err),
+			zap.String("group", group),
+		)
+		return err
+	}
+	return nil
+}
+
+// Read возвращает неподтвержденные события группы, а если их нет - новые
+func (r *EventStreamRepository) Read(ctx context.Context, group string, limit int) ([]entity.StreamMessage, error) {
+	entries, err := r.redis.StreamReadGroup(ctx, outboxStream, group, eventConsumer, "payload", int64(limit))
+	if err != nil {
+		r.log.Error("Failed to read task events",
+			zap.Error(err),
+			zap.String("group", group),
+		)
+		return nil, err
+	}
+
+	messages := make([]entity.StreamMessage, 0, len(entries))
+	for _, e := range entries {
+		messages = append(messages, entity.StreamMessage{StreamID: e.ID, Payload: json.RawMessage(e.Data)})
+	}
+	return messages, nil
+}
+
+func (r *EventStreamRepository) Ack(ctx context.Context, group string, ids ...string) error {
+	if err := r.redis.StreamAck(ctx, outboxStream, group, ids...); err != nil {
+		r.log.Error("Failed to ack task events",
+			zap.Error(err),
+			zap.String("group", group),
+			zap.Int("events", len(ids)),
+		)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,411 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"task-manager/internal/task"
+	"task-manager/internal/task/entity"
+	"task-manager/pkg/database/redis"
+	"time"
+)
+
+const seriesColumns = "id, user_id, rule, timezone, title, description, priority, starts_at, last_due_date, generated, finished_at, created_at, updated_at"
+
+func scanSeries(row rowScanner, s *entity.Series) error {
+	return row.Scan(
+		&s.ID,
+		&s.UserID,
+		&s.Rule,
+		&s.Timezone,
+		&s.Title,
+		&s.Description,
+		&s.Priority,
+		&s.StartsAt,
+		&s.LastDueDate,
+		&s.Generated,
+		&s.FinishedAt,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	)
+}
+
+// RecurrenceRepository хранит серии повторяющихся задач. Методы не проверяют владельца:
+// они вызываются после GetByID задачи серии или из воркера, где пользователя нет.
+type RecurrenceRepository struct {
+	db    *sql.DB
+	redis *redis.Client
+	log   *zap.Logger
+}
+
+func NewRecurrenceRepository(db *sql.DB, redis *redis.Client, log *zap.Logger) task.RecurrenceRepository {
+	return &RecurrenceRepository{
+		db:    db,
+		redis: redis,
+		log:   log.Named("recurrence_repository"),
+	}
+}
+
+// insertSeries создает серию в транзакции создания ее первого вхождения
+func insertSeries(ctx context.Context, tx *sql.Tx, s *entity.Series) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+
+	query := `
+		INSERT INTO task_series (
+			id, user_id, rule, timezone, title, description, priority, starts_at, last_due_date, generated, finished_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING created_at, updated_at`
+
+	err := tx.QueryRowContext(ctx, query,
+		s.ID,
+		s.UserID,
+		s.Rule,
+		s.Timezone,
+		s.Title,
+		s.Description,
+		s.Priority,
+		s.StartsAt,
+		s.LastDueDate,
+		s.Generated,
+		s.FinishedAt,
+	).Scan(&s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create task series: %w", err)
+	}
+	return nil
+}
+
+func (r *RecurrenceRepository) GetSeries(ctx context.Context, id uuid.UUID) (*entity.Series, error) {
+	var s entity.Series
+	query := "SELECT " + seriesColumns + " FROM task_series WHERE id = $1"
+
+	err := scanSeries(r.db.QueryRowContext(ctx, query, id), &s)
+	if err == sql.ErrNoRows {
+		return nil, task.ErrSeriesNotFound
+	}
+	if err != nil {
+		r.log.Error("Failed to get task series",
+			zap.Error(err),
+			zap.String("series_id", id.String()),
+		)
+		return nil, fmt.Errorf("failed to get task series: %w", err)
+	}
+	return &s, nil
+}
+
+// DueSeries возвращает незавершенные серии, у которых срок последнего вхождения уже наступил
+func (r *RecurrenceRepository) DueSeries(ctx context.Context, now time.Time, limit int) ([]*entity.Series, error) {
+	query := `
+		SELECT ` + seriesColumns + `
+		FROM task_series
+		WHERE finished_at IS NULL AND last_due_date <= $1
+		ORDER BY last_due_date ASC
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		r.log.Error("Failed to fetch due task series", zap.Error(err))
+		return nil, fmt.Errorf("failed to get due task series: %w", err)
+	}
+	defer rows.Close()
+
+	var series []*entity.Series
+	for rows.Next() {
+		var s entity.Series
+		if err := scanSeries(rows, &s); err != nil {
+			return nil, fmt.Errorf("failed to scan task series: %w", err)
+		}
+		series = append(series, &s)
+	}
+	return series, rows.Err()
+}
+
+// CreateOccurrence создает вхождение серии с номером occurrence и сроком dueDate.
+// Номер может быть больше generated+1, если прошедшие вхождения пропущены. Счетчик
+// generated сверяется с прочитанным, поэтому при гонке (воркер и завершение задачи)
+// вхождение создаст только один из участников, второй получит nil. fence - лок
+// лидера, если вхождение создает воркер, иначе nil.
+func (r *RecurrenceRepository) CreateOccurrence(
+	ctx context.Context,
+	fence *entity.Fence,
+	s *entity.Series,
+	occurrence int,
+	dueDate time.Time,
+) (*entity.Task, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkFence(ctx, tx, fence); err != nil {
+		return nil, err
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE task_series
+		SET generated = $3, last_due_date = $4, updated_at = NOW()
+		WHERE id = $1 AND generated = $2 AND finished_at IS NULL`,
+		s.ID, s.Generated, occurrence, dueDate,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to advance task series: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		r.log.Debug("Occurrence already generated",
+			zap.String("series_id", s.ID.String()),
+			zap.Int("generated", s.Generated),
+		)
+		return nil, nil
+	}
+
+	now := time.Now()
+	t := &entity.Task{
+		ID:          uuid.New(),
+		UserID:      s.UserID,
+		Title:       s.Title,
+		Description: s.Description,
+		Status:      entity.StatusPending,
+		Priority:    s.Priority,
+		DueDate:     dueDate,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Labels:      []entity.Label{},
+		SeriesID:    &s.ID,
+		Occurrence:  &occurrence,
+		Recurrence:  &s.Rule,
+		Timezone:    &s.Timezone,
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO tasks (
+			id, user_id, title, description, status, priority, due_date, created_at, updated_at, series_id, occurrence
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		t.ID,
+		t.UserID,
+		t.Title,
+		t.Description,
+		t.Status,
+		t.Priority,
+		t.DueDate,
+		t.CreatedAt,
+		t.UpdatedAt,
+		t.SeriesID,
+		t.Occurrence,
+	)
+	if err != nil {
+		r.log.Error("Failed to create occurrence",
+			zap.Error(err),
+			zap.String("series_id", s.ID.String()),
+		)
+		return nil, fmt.Errorf("failed to create occurrence: %w", err)
+	}
+
+	if err := insertStatusChange(ctx, tx, t.ID, t.UserID, nil, t.Status, t.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	if err := insertOutbox(ctx, tx, createdEvent(t)); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit occurrence: %w", err)
+	}
+
+	s.Generated = occurrence
+	s.LastDueDate = dueDate
+
+	r.log.Info("Occurrence created",
+		zap.String("series_id", s.ID.String()),
+		zap.String("task_id", t.ID.String()),
+		zap.Int("occurrence", occurrence),
+		zap.Time("due_date", dueDate),
+	)
+	return t, nil
+}
+
+// FinishSeries прекращает генерацию вхождений. Уже созданные задачи не меняются.
+// fence - лок лидера, если серию завершает воркер, иначе nil.
+func (r *RecurrenceRepository) FinishSeries(ctx context.Context, fence *entity.Fence, id uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkFence(ctx, tx, fence); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE task_series SET finished_at = NOW(), updated_at = NOW() WHERE id = $1 AND finished_at IS NULL`, id)
+	if err != nil {
+		r.log.Error("Failed to finish task series",
+			zap.Error(err),
+			zap.String("series_id", id.String()),
+		)
+		return fmt.Errorf("failed to finish task series: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task series finish: %w", err)
+	}
+
+	r.log.Info("Task series finished", zap.String("series_id", id.String()))
+	return nil
+}
+
+// ReplaceSeries применяет шаблон s к задаче t и всем следующим вхождениям ее серии.
+// Если t - первое вхождение, серия обновляется на месте. Иначе серия делится:
+// старая заканчивается на предыдущем вхождении, а t и следующие за ней переходят
+// в новую серию s с нумерацией от 1. Задача без серии становится первым вхождением s.
+// Открытые следующие вхождения получают название, описание и приоритет из шаблона.
+func (r *RecurrenceRepository) ReplaceSeries(ctx context.Context, t *entity.Task, s *entity.Series) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var affected []uuid.UUID
+	switch {
+	case t.SeriesID != nil && s.ID == *t.SeriesID:
+		_, err = tx.ExecContext(ctx, `
+			UPDATE task_series
+			SET rule = $2, timezone = $3, title = $4, description = $5, priority = $6, starts_at = $7,
+				last_due_date = $8, generated = $9, finished_at = $10, updated_at = NOW()
+			WHERE id = $1`,
+			s.ID, s.Rule, s.Timezone, s.Title, s.Description, s.Priority, s.StartsAt,
+			s.LastDueDate, s.Generated, s.FinishedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update task series: %w", err)
+		}
+	case t.SeriesID != nil:
+		if err := insertSeries(ctx, tx, s); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `
+			UPDATE task_series
+			SET generated = $2, finished_at = COALESCE(finished_at, NOW()), updated_at = NOW()
+			WHERE id = $1`,
+			*t.SeriesID, *t.Occurrence-1,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to split task series: %w", err)
+		}
+		affected, err = queryIDs(ctx, tx, `
+			UPDATE tasks SET series_id = $3, occurrence = occurrence - $2 + 1
+			WHERE series_id = $1 AND occurrence >= $2
+			RETURNING id`,
+			*t.SeriesID, *t.Occurrence, s.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to move occurrences: %w", err)
+		}
+	default:
+		if err := insertSeries(ctx, tx, s); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx,
+			`UPDATE tasks SET series_id = $2, occurrence = 1 WHERE id = $1`, t.ID, s.ID)
+		if err != nil {
+			return fmt.Errorf("failed to attach task to series: %w", err)
+		}
+	}
+
+	following, err := queryTasks(ctx, tx, `
+		UPDATE tasks SET title = $2, description = $3, priority = $4, updated_at = NOW()
+		WHERE series_id = $1 AND occurrence > 1 AND status != $5
+		RETURNING `+taskColumns,
+		s.ID, s.Title, s.Description, s.Priority, entity.StatusDone,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update following occurrences: %w", err)
+	}
+
+	// Правило серии показывается в каждом вхождении
+	all, err := queryIDs(ctx, tx, `SELECT id FROM tasks WHERE series_id = $1`, s.ID)
+	if err != nil {
+		return fmt.Errorf("failed to collect occurrences: %w", err)
+	}
+
+	var events []task.Event
+	for _, o := range following {
+		o.Recurrence = &s.Rule
+		o.Timezone = &s.Timezone
+		events = append(events, updatedEvents(o, o.Status)...)
+	}
+	if err := insertOutbox(ctx, tx, events...); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task series: %w", err)
+	}
+
+	occurrence := 1
+	t.SeriesID = &s.ID
+	t.Occurrence = &occurrence
+	t.Recurrence = &s.Rule
+	t.Timezone = &s.Timezone
+
+	invalidated := append(append([]uuid.UUID{t.ID}, affected...), all...)
+	invalidateTasks(ctx, r.redis, r.log, invalidated...)
+
+	r.log.Info("Task series replaced",
+		zap.String("series_id", s.ID.String()),
+		zap.String("task_id", t.ID.String()),
+		zap.Int("following", len(following)),
+	)
+	return nil
+}
+
+// loadRecurrence подставляет правило и часовой пояс серии в ее вхождения
+func loadRecurrence(ctx context.Context, db *sql.DB, tasks []*entity.Task) error {
+	bySeries := map[uuid.UUID][]*entity.Task{}
+	ids := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		t.Recurrence = nil
+		t.Timezone = nil
+		if t.SeriesID == nil {
+			continue
+		}
+		if _, ok := bySeries[*t.SeriesID]; !ok {
+			ids = append(ids, t.SeriesID.String())
+		}
+		bySeries[*t.SeriesID] = append(bySeries[*t.SeriesID], t)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := db.QueryContext(ctx,
+		`SELECT id, rule, timezone FROM task_series WHERE id = ANY($1::uuid[])`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to load task recurrence: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id       uuid.UUID
+			rule     string
+			timezone string
+		)
+		if err := rows.Scan(&id, &rule, &timezone); err != nil {
+			return fmt.Errorf("failed to scan task recurrence: %w", err)
+		}
+		for _, t := range bySeries[id] {
+			t.Recurrence = &rule
+			t.Timezone = &timezone
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"strconv"
+	"task-manager/internal/task"
+	"task-manager/internal/task/entity"
+	"task-manager/pkg/database/redis"
+	"task-manager/pkg/identity"
+	"time"
+)
+
+// reminderScheduleKey - sorted set с расписанием: member - ID напоминания, score - время срабатывания
+const reminderScheduleKey = "reminders:schedule"
+
+// reminderColumns выбираются вместе со сроком задачи, чтобы сразу посчитать FireAt
+const reminderColumns = "r.id, r.task_id, r.offset_minutes, r.sent_at, r.sent_due_date, r.created_at, t.due_date"
+
+func scanReminder(row rowScanner, reminder *entity.Reminder) error {
+	var dueDate time.Time
+	if err := row.Scan(
+		&reminder.ID,
+		&reminder.TaskID,
+		&reminder.BeforeMinutes,
+		&reminder.SentAt,
+		&reminder.SentDueDate,
+		&reminder.CreatedAt,
+		&dueDate,
+	); err != nil {
+		return err
+	}
+	reminder.FireAt = reminder.FireTime(dueDate)
+	return nil
+}
+
+type ReminderRepository struct {
+	db    *sql.DB
+	redis *redis.Client
+	log   *zap.Logger
+}
+
+func NewReminderRepository(db *sql.DB, redis *redis.Client, log *zap.Logger) task.ReminderRepository {
+	return &ReminderRepository{
+		db:    db,
+		redis: redis,
+		log:   log.Named("reminder_repository"),
+	}
+}
+
+func (r *ReminderRepository) ListReminders(ctx context.Context, taskID string) ([]entity.Reminder, error) {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := ownedTask(ctx, r.db, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return r.TaskReminders(ctx, id)
+}
+
+func (r *ReminderRepository) CreateReminder(ctx context.Context, taskID string, reminder *entity.Reminder) error {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	id, err := ownedTask(ctx, r.db, taskID, userID)
+	if err != nil {
+		return err
+	}
+
+	reminder.TaskID = id
+	query := `
+		INSERT INTO task_reminders (task_id, offset_minutes)
+		VALUES ($1, $2)
+		RETURNING id, created_at`
+
+	err = r.db.QueryRowContext(ctx, query, id, reminder.BeforeMinutes).Scan(&reminder.ID, &reminder.CreatedAt)
+	if isUniqueViolation(err) {
+		return task.ErrReminderExists
+	}
+	if err != nil {
+		r.log.Error("Failed to create reminder",
+			zap.Error(err),
+			zap.String("task_id", taskID),
+		)
+		return fmt.Errorf("failed to create reminder: %w", err)
+	}
+	return nil
+}
+
+func (r *ReminderRepository) DeleteReminder(ctx context.Context, taskID string, id int64) error {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	taskUUID, err := ownedTask(ctx, r.db, taskID, userID)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM task_reminders WHERE id = $1 AND task_id = $2`, id, taskUUID)
+	if err != nil {
+		r.log.Error("Failed to delete reminder",
+			zap.Error(err),
+			zap.Int64("reminder_id", id),
+		)
+		return fmt.Errorf("failed to delete reminder: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return task.ErrReminderNotFound
+	}
+	return nil
+}
+
+// TaskReminders возвращает напоминания задачи без проверки владельца
+func (r *ReminderRepository) TaskReminders(ctx context.Context, taskID uuid.UUID) ([]entity.Reminder, error) {
+	query := `
+		SELECT ` + reminderColumns + `
+		FROM task_reminders r JOIN tasks t ON t.id = r.task_id
+		WHERE r.task_id = $1
+		ORDER BY r.offset_minutes DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, taskID)
+	if err != nil {
+		r.log.Error("Failed to list reminders",
+			zap.Error(err),
+			zap.String("task_id", taskID.String()),
+		)
+		return nil, fmt.Errorf("failed to list reminders: %w", err)
+	}
+	defer rows.Close()
+
+	reminders := []entity.Reminder{}
+	for rows.Next() {
+		var reminder entity.Reminder
+		if err := scanReminder(rows, &reminder); err != nil {
+			return nil, fmt.Errorf("failed to scan reminder: %w", err)
+		}
+		reminders = append(reminders, reminder)
+	}
+	return reminders, rows.Err()
+}
+
+func (r *ReminderRepository) Schedule(ctx context.Context, id int64, at time.Time) error {
+	if err := r.redis.Schedule(ctx, reminderScheduleKey, strconv.FormatInt(id, 10), at); err != nil {
+		return fmt.Errorf("failed to schedule reminder: %w", err)
+	}
+	return nil
+}
+
+func (r *ReminderRepository) Unschedule(ctx context.Context, ids ...int64) error {
+	if err := r.redis.Unschedule(ctx, reminderScheduleKey, reminderMembers(ids)...); err != nil {
+		return fmt.Errorf("failed to unschedule reminders: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue забирает сработавшие напоминания из расписания под аренду lease
+func (r *ReminderRepository) ClaimDue(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+	lease time.Duration,
+) ([]int64, time.Time, error) {
+	members, leaseUntil, err := r.redis.ClaimDue(ctx, reminderScheduleKey, now, limit, lease)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	ids := make([]int64, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			r.log.Warn("Dropping malformed reminder schedule entry", zap.String("member", member))
+			r.redis.Unschedule(ctx, reminderScheduleKey, member)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, leaseUntil, nil
+}
+
+// GetDue загружает напоминания вместе с задачей и email владельца. Удаленные
+// напоминания и задачи в результат не попадают.
+func (r *ReminderRepository) GetDue(ctx context.Context, ids []int64) ([]*entity.DueReminder, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT r.id, r.task_id, r.offset_minutes, r.sent_at, r.sent_due_date, r.created_at,
+			t.user_id, t.title, t.status, t.due_date, t.archived_at, u.email
+		FROM task_reminders r
+		JOIN tasks t ON t.id = r.task_id
+		JOIN users u ON u.id = t.user_id
+		WHERE r.id = ANY($1)`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		r.log.Error("Failed to load due reminders", zap.Error(err))
+		return nil, fmt.Errorf("failed to load due reminders: %w", err)
+	}
+	defer rows.Close()
+
+	var due []*entity.DueReminder
+	for rows.Next() {
+		var d entity.DueReminder
+		if err := rows.Scan(
+			&d.ID,
+			&d.TaskID,
+			&d.BeforeMinutes,
+			&d.SentAt,
+			&d.SentDueDate,
+			&d.CreatedAt,
+			&d.Task.UserID,
+			&d.Task.Title,
+			&d.Task.Status,
+			&d.Task.DueDate,
+			&d.Task.ArchivedAt,
+			&d.Email,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan due reminder: %w", err)
+		}
+		d.Task.ID = d.TaskID
+		d.FireAt = d.FireTime(d.Task.DueDate)
+		due = append(due, &d)
+	}
+	return due, rows.Err()
+}
+
+// MarkSent запоминает, что о сроке dueDate уже напомнили
+func (r *ReminderRepository) MarkSent(ctx context.Context, id int64, dueDate time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE task_reminders SET sent_at = NOW(), sent_due_date = $2 WHERE id = $1`, id, dueDate)
+	if err != nil {
+		return fmt.Errorf("failed to mark reminder as sent: %w", err)
+	}
+	return nil
+}
+
+// Ack убирает обработанные напоминания из расписания, если их не перепланировали
+func (r *ReminderRepository) Ack(ctx context.Context, leaseUntil time.Time, ids ...int64) error {
+	return r.redis.Ack(ctx, reminderScheduleKey, leaseUntil, reminderMembers(ids)...)
+}
+
+func reminderMembers(ids []int64) []string {
+	members := make([]string, 0, len(ids))
+	for _, id := range ids {
+		members = append(members, strconv.FormatInt(id, 10))
+	}
+	return members
+}
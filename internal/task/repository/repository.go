@@ -12,9 +12,42 @@ import (
 	"task-manager/internal/task/dtos"
 	"task-manager/internal/task/entity"
 	"task-manager/pkg/database/redis"
+	"task-manager/pkg/identity"
 	"time"
 )
 
+// taskColumns - явный список колонок, чтобы сканирование не зависело от порядка колонок в таблице
+const taskColumns = "id, user_id, parent_id, title, description, status, priority, due_date, overdue_at, archived_at, completed_at, created_at, updated_at, series_id, occurrence"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// queryer - общее у *sql.DB и *sql.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func scanTask(row rowScanner, task *entity.Task) error {
+	return row.Scan(
+		&task.ID,
+		&task.UserID,
+		&task.ParentID,
+		&task.Title,
+		&task.Description,
+		&task.Status,
+		&task.Priority,
+		&task.DueDate,
+		&task.OverdueAt,
+		&task.ArchivedAt,
+		&task.CompletedAt,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&task.SeriesID,
+		&task.Occurrence,
+	)
+}
+
 type Repository struct {
 	db    *sql.DB
 	redis *redis.Client
@@ -30,22 +63,65 @@ func NewRepository(db *sql.DB, redis *redis.Client, log *zap.Logger) task.TaskRe
 }
 
 func (r *Repository) Create(ctx context.Context, task *entity.Task) error {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return err
+	}
+
 	task.ID = uuid.New()
+	task.UserID = userID
 	task.CreatedAt = time.Now()
 	task.UpdatedAt = time.Now()
+	task.Labels = []entity.Label{}
 
 	query := `
 		INSERT INTO tasks (
-			id, title, description, status, priority, due_date, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+			id, user_id, parent_id, title, description, status, priority, due_date, created_at, updated_at,
+			series_id, occurrence
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
 
 	r.log.Debug("Creating new task",
 		zap.String("title", task.Title),
 		zap.String("status", string(task.Status)),
+		zap.Int64("user_id", task.UserID),
 	)
 
-	_, err := r.db.ExecContext(ctx, query,
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Задача с правилом повторения становится первым вхождением новой серии
+	if task.Recurrence != nil {
+		timezone := entity.DefaultTimezone
+		if task.Timezone != nil {
+			timezone = *task.Timezone
+		}
+		series := &entity.Series{
+			UserID:      userID,
+			Rule:        *task.Recurrence,
+			Timezone:    timezone,
+			Title:       task.Title,
+			Description: task.Description,
+			Priority:    task.Priority,
+			StartsAt:    task.DueDate,
+			LastDueDate: task.DueDate,
+			Generated:   1,
+		}
+		if err := insertSeries(ctx, tx, series); err != nil {
+			return err
+		}
+		occurrence := 1
+		task.SeriesID = &series.ID
+		task.Occurrence = &occurrence
+		task.Timezone = &series.Timezone
+	}
+
+	_, err = tx.ExecContext(ctx, query,
 		task.ID,
+		task.UserID,
+		task.ParentID,
 		task.Title,
 		task.Description,
 		task.Status,
@@ -53,6 +129,8 @@ func (r *Repository) Create(ctx context.Context, task *entity.Task) error {
 		task.DueDate,
 		task.CreatedAt,
 		task.UpdatedAt,
+		task.SeriesID,
+		task.Occurrence,
 	)
 
 	if err != nil {
@@ -63,6 +141,23 @@ func (r *Repository) Create(ctx context.Context, task *entity.Task) error {
 		return fmt.Errorf("failed to create task: %w", err)
 	}
 
+	if err := insertStatusChange(ctx, tx, task.ID, userID, nil, task.Status, task.CreatedAt); err != nil {
+		return err
+	}
+
+	if err := insertOutbox(ctx, tx, createdEvent(task)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task creation: %w", err)
+	}
+
+	// Прогресс родителя учитывает подзадачи
+	if task.ParentID != nil {
+		invalidateTasks(ctx, r.redis, r.log, *task.ParentID)
+	}
+
 	r.log.Info("Task created successfully",
 		zap.String("task_id", task.ID.String()),
 	)
@@ -70,36 +165,46 @@ func (r *Repository) Create(ctx context.Context, task *entity.Task) error {
 }
 
 func (r *Repository) GetByID(ctx context.Context, id string) (*entity.Task, error) {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	cacheKey := fmt.Sprintf("task:%s", id)
 	r.log.Debug("Fetching task from cache", zap.String("cache_key", cacheKey))
 
 	// Попытка получить из кеша
 	cachedTask, err := r.getFromCache(ctx, cacheKey)
 	if err == nil && cachedTask != nil {
+		if cachedTask.UserID != userID {
+			r.log.Warn("Task belongs to another user",
+				zap.String("task_id", id),
+				zap.Int64("user_id", userID),
+			)
+			return nil, task.ErrNotFound
+		}
 		r.log.Debug("Task found in cache", zap.String("task_id", id))
 		return cachedTask, nil
 	}
 
 	r.log.Debug("Fetching task from database", zap.String("task_id", id))
 
-	var task entity.Task
-	query := `SELECT * FROM tasks WHERE id = $1`
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, task.ErrNotFound
+	}
 
-	err = r.db.QueryRowContext(ctx, query, id).Scan(
-		&task.ID,
-		&task.Title,
-		&task.Description,
-		&task.Status,
-		&task.Priority,
-		&task.DueDate,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-	)
+	var t entity.Task
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1 AND user_id = $2`
 
+	err = scanTask(r.db.QueryRowContext(ctx, query, uuidID, userID), &t)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			r.log.Warn("Task not found", zap.String("task_id", id))
-			return nil, fmt.Errorf("task not found")
+			r.log.Warn("Task not found",
+				zap.String("task_id", id),
+				zap.Int64("user_id", userID),
+			)
+			return nil, task.ErrNotFound
 		}
 		r.log.Error("Failed to fetch task from database",
 			zap.Error(err),
@@ -108,15 +213,19 @@ func (r *Repository) GetByID(ctx context.Context, id string) (*entity.Task, erro
 		return nil, fmt.Errorf("failed to get task: %w", err)
 	}
 
+	if err := loadDetails(ctx, r.db, []*entity.Task{&t}); err != nil {
+		return nil, err
+	}
+
 	r.log.Debug("Caching task", zap.String("task_id", id))
-	if err := r.cacheTask(ctx, cacheKey, &task); err != nil {
+	if err := r.cacheTask(ctx, cacheKey, &t); err != nil {
 		r.log.Error("Failed to cache task",
 			zap.Error(err),
 			zap.String("task_id", id),
 		)
 	}
 
-	return &task, nil
+	return &t, nil
 }
 
 func (r *Repository) getFromCache(ctx context.Context, key string) (*entity.Task, error) {
@@ -158,8 +267,13 @@ func (r *Repository) cacheTask(ctx context.Context, key string, task *entity.Tas
 	return nil
 }
 
-func (r *Repository) Update(ctx context.Context, task *entity.Task) error {
-	task.UpdatedAt = time.Now()
+func (r *Repository) Update(ctx context.Context, t *entity.Task) error {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	t.UpdatedAt = time.Now()
 
 	query := `
 		UPDATE tasks 
@@ -169,192 +283,372 @@ func (r *Repository) Update(ctx context.Context, task *entity.Task) error {
 			status = $3,
 			priority = $4,
 			due_date = $5,
-			updated_at = $6
-		WHERE id = $7`
+			updated_at = $6,
+			completed_at = CASE WHEN $3 = 'done' THEN COALESCE(completed_at, $6) ELSE NULL END
+		WHERE id = $7 AND user_id = $8
+		RETURNING completed_at`
 
 	r.log.Debug("Updating task",
-		zap.String("task_id", task.ID.String()),
-		zap.String("status", string(task.Status)),
+		zap.String("task_id", t.ID.String()),
+		zap.String("status", string(t.Status)),
 	)
 
-	result, err := r.db.ExecContext(ctx, query,
-		task.Title,
-		task.Description,
-		task.Status,
-		task.Priority,
-		task.DueDate,
-		task.UpdatedAt,
-		task.ID,
-	)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Блокируем строку, чтобы прочитать прежний статус для истории
+	var oldStatus entity.Status
+	err = tx.QueryRowContext(ctx,
+		`SELECT status FROM tasks WHERE id = $1 AND user_id = $2 FOR UPDATE`,
+		t.ID, userID,
+	).Scan(&oldStatus)
+	if err == sql.ErrNoRows {
+		r.log.Warn("Task not found for update",
+			zap.String("task_id", t.ID.String()),
+			zap.Int64("user_id", userID),
+		)
+		return task.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock task: %w", err)
+	}
+
+	// Блокирующие задачи проверяются в транзакции, а не по кешу GetByID:
+	// их статус мог измениться после того, как задача попала в кеш
+	if t.Status != oldStatus && (t.Status == entity.StatusInProgress || t.Status == entity.StatusDone) {
+		open, err := openBlockers(ctx, tx, t.ID)
+		if err != nil {
+			return err
+		}
+		if len(open) > 0 {
+			return &task.BlockedError{Blockers: open}
+		}
+	}
 
+	err = tx.QueryRowContext(ctx, query,
+		t.Title,
+		t.Description,
+		t.Status,
+		t.Priority,
+		t.DueDate,
+		t.UpdatedAt,
+		t.ID,
+		userID,
+	).Scan(&t.CompletedAt)
+
+	if err == sql.ErrNoRows {
+		r.log.Warn("No rows affected during update",
+			zap.String("task_id", t.ID.String()),
+			zap.Int64("user_id", userID),
+		)
+		return task.ErrNotFound
+	}
 	if err != nil {
 		r.log.Error("Failed to update task",
 			zap.Error(err),
-			zap.String("task_id", task.ID.String()),
+			zap.String("task_id", t.ID.String()),
 		)
 		return fmt.Errorf("failed to update task: %w", err)
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		r.log.Warn("No rows affected during update",
-			zap.String("task_id", task.ID.String()),
-		)
-		return fmt.Errorf("task not found")
+	if oldStatus != t.Status {
+		if err := insertStatusChange(ctx, tx, t.ID, userID, &oldStatus, t.Status, t.UpdatedAt); err != nil {
+			return err
+		}
 	}
 
-	// Инвалидация кеша
-	cacheKey := fmt.Sprintf("task:%s", task.ID.String())
-	if err := r.redis.Delete(ctx, cacheKey); err != nil {
-		r.log.Warn("Failed to invalidate cache",
-			zap.Error(err),
-			zap.String("cache_key", cacheKey),
-		)
+	if err := insertOutbox(ctx, tx, updatedEvents(t, oldStatus)...); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task update: %w", err)
+	}
+
+	// Инвалидация кеша. Статус подзадачи влияет на прогресс родителя,
+	// а статус и название - на список блокирующих задач у зависимых.
+	invalidated := []uuid.UUID{t.ID}
+	if t.ParentID != nil {
+		invalidated = append(invalidated, *t.ParentID)
+	}
+	if blocked, err := dependents(ctx, r.db, t.ID); err != nil {
+		r.log.Warn("Failed to collect dependent tasks for cache invalidation", zap.Error(err))
+	} else {
+		invalidated = append(invalidated, blocked...)
 	}
+	invalidateTasks(ctx, r.redis, r.log, invalidated...)
 
 	r.log.Info("Task updated successfully",
-		zap.String("task_id", task.ID.String()),
+		zap.String("task_id", t.ID.String()),
 	)
 	return nil
 }
 
-func (r *Repository) Delete(ctx context.Context, id string) error {
-	cacheKey := fmt.Sprintf("task:%s", id)
-	uuidID, err := uuid.Parse(id)
+// Delete удаляет задачу. Подзадачи удаляются вместе с ней (cascade)
+// или переходят к ее родителю (reparent).
+func (r *Repository) Delete(ctx context.Context, id string, policy entity.ParentDeletePolicy) error {
+	userID, err := identity.RequireUserID(ctx)
 	if err != nil {
-		return fmt.Errorf("invalid task id: %w", err)
+		return err
 	}
 
-	query := `DELETE FROM tasks WHERE id = $1`
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return task.ErrNotFound
+	}
 
 	r.log.Debug("Deleting task",
 		zap.String("task_id", id),
+		zap.String("policy", string(policy)),
 	)
 
-	result, err := r.db.ExecContext(ctx, query, uuidID)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		r.log.Error("Failed to delete task",
-			zap.Error(err),
-			zap.String("task_id", id),
-		)
-		return fmt.Errorf("failed to delete task: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
+	events, invalidated, err := deleteTask(ctx, tx, userID, uuidID, policy)
+	if err == task.ErrNotFound {
 		r.log.Warn("Task not found for deletion",
 			zap.String("task_id", id),
+			zap.Int64("user_id", userID),
 		)
-		return fmt.Errorf("task not found")
+		return err
 	}
-
-	// Очистка кеша
-	if err := r.redis.Delete(ctx, cacheKey); err != nil {
-		r.log.Warn("Failed to delete cache entry",
+	if err != nil {
+		r.log.Error("Failed to delete task",
 			zap.Error(err),
-			zap.String("cache_key", cacheKey),
+			zap.String("task_id", id),
 		)
+		return err
+	}
+
+	if err := insertOutbox(ctx, tx, events...); err != nil {
+		return err
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task deletion: %w", err)
+	}
+
+	invalidateTasks(ctx, r.redis, r.log, invalidated...)
+
 	r.log.Info("Task deleted successfully",
 		zap.String("task_id", id),
+		zap.Int("invalidated", len(invalidated)),
 	)
 	return nil
 }
 
-func (r *Repository) List(
+// deleteTask удаляет задачу пользователя в транзакции tx, поступая с подзадачами
+// по policy. Возвращает события для outbox и задачи, чей кеш устарел: саму задачу,
+// ее родителя, затронутые подзадачи и задачи, которые она блокировала.
+func deleteTask(
 	ctx context.Context,
-	filter dtos.Filter,
-	pagination dtos.Pagination,
-) ([]*entity.Task, error) {
-	baseQuery := "SELECT * FROM tasks WHERE 1=1"
-	args := []interface{}{}
-	argCounter := 1
-
-	// Формирование условий фильтра
-	if filter.Status != "" {
-		baseQuery += fmt.Sprintf(" AND status = $%d", argCounter)
-		args = append(args, filter.Status)
-		argCounter++
-	}
-	if filter.Priority != "" {
-		baseQuery += fmt.Sprintf(" AND priority = $%d", argCounter)
-		args = append(args, filter.Priority)
-		argCounter++
-	}
-	if filter.Search != "" {
-		baseQuery += fmt.Sprintf(" AND title ILIKE $%d", argCounter)
-		args = append(args, "%"+filter.Search+"%")
-		argCounter++
-	}
-
-	// Добавляем сортировку и пагинацию
-	baseQuery += " ORDER BY due_date ASC"
-	baseQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argCounter, argCounter+1)
-	args = append(args, pagination.Limit, pagination.Offset)
-
-	r.log.Debug("Listing tasks",
-		zap.Any("filter", filter),
-		zap.Any("pagination", pagination),
-	)
+	tx *sql.Tx,
+	userID int64,
+	id uuid.UUID,
+	policy entity.ParentDeletePolicy,
+) ([]task.Event, []uuid.UUID, error) {
+	var parentID *uuid.UUID
+	err := tx.QueryRowContext(ctx,
+		`SELECT parent_id FROM tasks WHERE id = $1 AND user_id = $2 FOR UPDATE`,
+		id, userID,
+	).Scan(&parentID)
+	if err == sql.ErrNoRows {
+		return nil, nil, task.ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to lock task: %w", err)
+	}
 
-	rows, err := r.db.QueryContext(ctx, baseQuery, args...)
+	invalidated := []uuid.UUID{id}
+	if parentID != nil {
+		invalidated = append(invalidated, *parentID)
+	}
+	blocked, err := dependents(ctx, tx, id)
 	if err != nil {
-		r.log.Error("Failed to list tasks",
-			zap.Error(err),
-		)
-		return nil, fmt.Errorf("failed to list tasks: %w", err)
+		return nil, nil, err
+	}
+	invalidated = append(invalidated, blocked...)
+
+	var affected []uuid.UUID
+	var reparented []*entity.Task
+	switch policy {
+	case entity.ParentDeleteCascade:
+		affected, err = queryIDs(ctx, tx, `
+			WITH RECURSIVE subtree AS (
+				SELECT id FROM tasks WHERE id = $1
+				UNION ALL
+				SELECT t.id FROM tasks t JOIN subtree s ON t.parent_id = s.id
+			)
+			DELETE FROM tasks WHERE id IN (SELECT id FROM subtree) AND user_id = $2
+			RETURNING id`, id, userID)
+	default:
+		reparented, err = queryTasks(ctx, tx, `
+			UPDATE tasks SET parent_id = $3, updated_at = NOW()
+			WHERE parent_id = $1 AND user_id = $2
+			RETURNING `+taskColumns, id, userID, parentID)
+		for _, child := range reparented {
+			affected = append(affected, child.ID)
+		}
+		if err == nil {
+			_, err = tx.ExecContext(ctx, `DELETE FROM tasks WHERE id = $1 AND user_id = $2`, id, userID)
+		}
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to delete task: %w", err)
+	}
+	invalidated = append(invalidated, affected...)
+
+	// При cascade удаляется все поддерево, и о каждой задаче выходит свое событие.
+	// При reparent подзадачи меняют родителя - это их обновление.
+	deleted := []uuid.UUID{id}
+	if policy == entity.ParentDeleteCascade {
+		deleted = affected
+	}
+	events := deletedEvents(userID, deleted)
+	for _, child := range reparented {
+		events = append(events, updatedEvents(child, child.Status)...)
+	}
+	return events, invalidated, nil
+}
+
+// queryIDs выполняет запрос, возвращающий колонку id задач
+func queryIDs(ctx context.Context, q queryer, query string, args ...interface{}) ([]uuid.UUID, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// queryTasks выполняет запрос, возвращающий taskColumns
+func queryTasks(ctx context.Context, q queryer, query string, args ...interface{}) ([]*entity.Task, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
 	}
 	defer rows.Close()
 
 	var tasks []*entity.Task
 	for rows.Next() {
-		var task entity.Task
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Status,
-			&task.Priority,
-			&task.DueDate,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-		)
-		if err != nil {
-			r.log.Error("Failed to scan task row",
-				zap.Error(err),
-			)
-			return nil, fmt.Errorf("failed to scan task: %w", err)
+		var t entity.Task
+		if err := scanTask(rows, &t); err != nil {
+			return nil, err
 		}
-		tasks = append(tasks, &task)
+		tasks = append(tasks, &t)
+	}
+	return tasks, rows.Err()
+}
+
+// invalidateTasks сбрасывает кеш GetByID для перечисленных задач
+func invalidateTasks(ctx context.Context, client *redis.Client, log *zap.Logger, ids ...uuid.UUID) {
+	if len(ids) == 0 {
+		return
 	}
 
-	if err = rows.Err(); err != nil {
-		r.log.Error("Error during rows iteration",
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, fmt.Sprintf("task:%s", id.String()))
+	}
+	if err := client.Delete(ctx, keys...); err != nil {
+		log.Warn("Failed to invalidate cache",
 			zap.Error(err),
+			zap.Int("keys", len(keys)),
 		)
-		return nil, fmt.Errorf("rows iteration error: %w", err)
 	}
+}
 
-	r.log.Debug("Tasks listed successfully",
-		zap.Int("count", len(tasks)),
-	)
-	return tasks, nil
+// Depth возвращает глубину задачи в иерархии: 0 для корневой задачи
+func (r *Repository) Depth(ctx context.Context, id string) (int, error) {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	uuidID, err := uuid.Parse(id)
+	if err != nil {
+		return 0, task.ErrNotFound
+	}
+
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, 0 AS depth FROM tasks WHERE id = $1 AND user_id = $2
+			UNION ALL
+			SELECT t.id, t.parent_id, a.depth + 1 FROM tasks t JOIN ancestors a ON t.id = a.parent_id
+		)
+		SELECT MAX(depth) FROM ancestors`
+
+	var depth sql.NullInt64
+	if err := r.db.QueryRowContext(ctx, query, uuidID, userID).Scan(&depth); err != nil {
+		return 0, fmt.Errorf("failed to get task depth: %w", err)
+	}
+	if !depth.Valid {
+		return 0, task.ErrNotFound
+	}
+	return int(depth.Int64), nil
+}
+
+func (r *Repository) List(
+	ctx context.Context,
+	filter dtos.Filter,
+	pagination dtos.Pagination,
+) (*dtos.TaskPage, error) {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.list(ctx, &userID, filter, pagination)
+}
+
+// ListAll возвращает задачи всех пользователей. Доступ проверяется на уровне роутера (tasks:read_all).
+func (r *Repository) ListAll(
+	ctx context.Context,
+	filter dtos.Filter,
+	pagination dtos.Pagination,
+) (*dtos.TaskPage, error) {
+	return r.list(ctx, nil, filter, pagination)
 }
 
+// GetOverdue возвращает просроченные задачи текущего пользователя. Фоновые джобы
+// работают по всем пользователям только через ApplyOverduePolicy.
 func (r *Repository) GetOverdue(ctx context.Context, threshold time.Time) ([]*entity.Task, error) {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
-		SELECT * 
+		SELECT ` + taskColumns + ` 
 		FROM tasks 
 		WHERE due_date < $1 
-		AND status != $2 
+		AND status != $2
+		AND archived_at IS NULL
+		AND user_id = $3
 		ORDER BY due_date ASC`
+	args := []interface{}{threshold, entity.StatusDone, userID}
 
 	r.log.Debug("Fetching overdue tasks",
 		zap.Time("threshold", threshold),
 	)
 
-	rows, err := r.db.QueryContext(ctx, query, threshold, entity.StatusDone)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		r.log.Error("Failed to fetch overdue tasks",
 			zap.Error(err),
@@ -366,17 +660,7 @@ func (r *Repository) GetOverdue(ctx context.Context, threshold time.Time) ([]*en
 	var tasks []*entity.Task
 	for rows.Next() {
 		var task entity.Task
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Status,
-			&task.Priority,
-			&task.DueDate,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-		)
-		if err != nil {
+		if err := scanTask(rows, &task); err != nil {
 			r.log.Error("Failed to scan overdue task",
 				zap.Error(err),
 			)
@@ -392,8 +676,159 @@ func (r *Repository) GetOverdue(ctx context.Context, threshold time.Time) ([]*en
 		return nil, fmt.Errorf("rows iteration error: %w", err)
 	}
 
+	if err := loadDetails(ctx, r.db, tasks); err != nil {
+		return nil, err
+	}
+
 	r.log.Debug("Overdue tasks fetched",
 		zap.Int("count", len(tasks)),
 	)
 	return tasks, nil
 }
+
+// overduePolicyQueries - SQL для каждой политики обработки просрочек.
+// $1 - порог due_date, $2 - статус выполненной задачи.
+var overduePolicyQueries = map[entity.OverduePolicy]string{
+	entity.OverduePolicyMark: `
+		UPDATE tasks
+		SET overdue_at = NOW(), updated_at = NOW()
+		WHERE due_date < $1 AND status != $2
+		AND overdue_at IS NULL AND archived_at IS NULL
+		RETURNING ` + taskColumns,
+	entity.OverduePolicyArchive: `
+		UPDATE tasks
+		SET overdue_at = COALESCE(overdue_at, NOW()), archived_at = NOW(), updated_at = NOW()
+		WHERE due_date < $1 AND status != $2
+		AND archived_at IS NULL
+		RETURNING ` + taskColumns,
+	entity.OverduePolicyEscalate: `
+		UPDATE tasks
+		SET priority = CASE priority WHEN 'low' THEN 'medium' ELSE 'high' END,
+			overdue_at = NOW(), updated_at = NOW()
+		WHERE due_date < $1 AND status != $2
+		AND overdue_at IS NULL AND archived_at IS NULL
+		RETURNING ` + taskColumns,
+	// Удаляемые задачи только выбираются и блокируются: удаление идет через deleteTask,
+	// чтобы подзадачи обработались по политике удаления родителя
+	entity.OverduePolicyDelete: `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE due_date < $1 AND status != $2
+		AND archived_at IS NULL
+		ORDER BY id
+		FOR UPDATE`,
+}
+
+// ApplyOverduePolicy применяет политику к просроченным задачам всех пользователей
+// и возвращает затронутые задачи. Используется фоновыми джобами. Подзадачи удаляемых
+// задач удаляются или переходят к их родителю по onParentDelete, как при Delete.
+// Изменения пишутся под локом лидера fence и отклоняются, если лок уже перехвачен.
+func (r *Repository) ApplyOverduePolicy(
+	ctx context.Context,
+	fence entity.Fence,
+	policy entity.OverduePolicy,
+	onParentDelete entity.ParentDeletePolicy,
+	threshold time.Time,
+) ([]*entity.Task, error) {
+	query, ok := overduePolicyQueries[policy]
+	if !ok {
+		return nil, fmt.Errorf("unknown overdue policy %q", policy)
+	}
+
+	r.log.Debug("Applying overdue policy",
+		zap.String("policy", string(policy)),
+		zap.Time("threshold", threshold),
+	)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkFence(ctx, tx, &fence); err != nil {
+		return nil, err
+	}
+
+	// Архивная или удаленная задача перестает блокировать зависимые,
+	// поэтому их кеш тоже сбрасывается. Собираем до удаления ребер.
+	var blocked []uuid.UUID
+	if policy.Destructive() {
+		blocked, err = queryIDs(ctx, tx, `
+			SELECT DISTINCT d.blocked_id
+			FROM task_dependencies d
+			JOIN tasks t ON t.id = d.blocker_id
+			WHERE t.due_date < $1 AND t.status != $2 AND t.archived_at IS NULL`,
+			threshold, entity.StatusDone)
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect dependent tasks: %w", err)
+		}
+	}
+
+	tasks, err := queryTasks(ctx, tx, query, threshold, entity.StatusDone)
+	if err != nil {
+		r.log.Error("Failed to apply overdue policy",
+			zap.Error(err),
+			zap.String("policy", string(policy)),
+		)
+		return nil, fmt.Errorf("failed to apply overdue policy: %w", err)
+	}
+
+	var events []task.Event
+	if policy == entity.OverduePolicyDelete {
+		var deleted []*entity.Task
+		for _, t := range tasks {
+			taskEvents, invalidated, err := deleteTask(ctx, tx, t.UserID, t.ID, onParentDelete)
+			if err == task.ErrNotFound {
+				// Уже удалена вместе с просроченным родителем
+				continue
+			}
+			if err != nil {
+				r.log.Error("Failed to delete overdue task",
+					zap.Error(err),
+					zap.String("task_id", t.ID.String()),
+				)
+				return nil, err
+			}
+			events = append(events, taskEvents...)
+			blocked = append(blocked, invalidated...)
+			deleted = append(deleted, t)
+		}
+		tasks = deleted
+	} else {
+		for _, t := range tasks {
+			events = append(events, updatedEvents(t, t.Status)...)
+		}
+	}
+	if err := insertOutbox(ctx, tx, events...); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit overdue policy: %w", err)
+	}
+
+	// Инвалидация кеша затронутых задач. Для удаления в blocked уже собраны
+	// подзадачи и родители удаленных задач.
+	if len(tasks) > 0 {
+		keys := make([]string, 0, len(tasks)+len(blocked))
+		for _, task := range tasks {
+			keys = append(keys, fmt.Sprintf("task:%s", task.ID.String()))
+		}
+		for _, id := range blocked {
+			keys = append(keys, fmt.Sprintf("task:%s", id.String()))
+		}
+		if err := r.redis.Delete(ctx, keys...); err != nil {
+			r.log.Warn("Failed to invalidate cache",
+				zap.Error(err),
+				zap.Int("keys", len(keys)),
+			)
+		}
+	}
+
+	r.log.Info("Overdue policy applied",
+		zap.String("policy", string(policy)),
+		zap.Int("count", len(tasks)),
+	)
+	return tasks, nil
+}
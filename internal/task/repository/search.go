@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"strings"
+	"task-manager/internal/task/dtos"
+	"task-manager/internal/task/entity"
+	"task-manager/pkg/identity"
+	"unicode"
+)
+
+// searchConfig - конфигурация полнотекстового поиска, созданная миграцией 000009.
+// Ей же строится колонка search_vector, поэтому запросы должны использовать ту же конфигурацию.
+const searchConfig = "'task_search'::regconfig"
+
+// headlineOptions - параметры подсветки совпадений в ts_headline. Подсветка отдается
+// клиенту как HTML, поэтому текст задачи экранируется до ts_headline (escapeHTML),
+// и сырыми в нем остаются только теги <mark>.
+const (
+	titleHeadlineOptions   = "StartSel=<mark>, StopSel=</mark>, HighlightAll=true"
+	snippetHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5"
+)
+
+// escapeHTML экранирует SQL-выражение expr для вставки в HTML. & заменяется первым,
+// чтобы не экранировать повторно уже замененные символы.
+func escapeHTML(expr string) string {
+	return fmt.Sprintf(
+		`replace(replace(replace(replace(replace(%s, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`,
+		expr,
+	)
+}
+
+// tsQuery переводит поисковую строку в выражение tsquery: "фраза в кавычках" ищется как фраза,
+// слово* - по префиксу, остальные слова - по всем словоформам. Части объединяются по И.
+// Возвращает false, если в строке нет ни одного терма.
+func tsQuery(b *queryBuilder, q string) (string, bool) {
+	var (
+		parts  []string
+		plain  []string
+		phrase strings.Builder
+		quoted bool
+	)
+
+	flushWord := func(word string) {
+		if word == "" {
+			return
+		}
+		if strings.HasSuffix(word, "*") {
+			// В to_tsquery попадают только буквы и цифры, чтобы пользователь не мог передать операторы
+			prefix := strings.Map(func(r rune) rune {
+				if unicode.IsLetter(r) || unicode.IsDigit(r) {
+					return r
+				}
+				return -1
+			}, word)
+			if prefix != "" {
+				parts = append(parts, fmt.Sprintf("to_tsquery(%s, %s)", searchConfig, b.arg(prefix+":*")))
+			}
+			return
+		}
+		plain = append(plain, word)
+	}
+
+	for _, field := range strings.SplitAfter(q, `"`) {
+		text := strings.TrimSuffix(field, `"`)
+		closed := len(text) != len(field)
+
+		if quoted {
+			phrase.WriteString(text)
+			if closed {
+				if p := strings.TrimSpace(phrase.String()); p != "" {
+					parts = append(parts, fmt.Sprintf("phraseto_tsquery(%s, %s)", searchConfig, b.arg(p)))
+				}
+				phrase.Reset()
+				quoted = false
+			}
+			continue
+		}
+
+		for _, word := range strings.Fields(text) {
+			flushWord(word)
+		}
+		quoted = closed
+	}
+	// Незакрытая кавычка - ищем остаток как обычные слова
+	for _, word := range strings.Fields(phrase.String()) {
+		flushWord(word)
+	}
+
+	if len(plain) > 0 {
+		parts = append(parts, fmt.Sprintf("plainto_tsquery(%s, %s)", searchConfig, b.arg(strings.Join(plain, " "))))
+	}
+	if len(parts) == 0 {
+		return "", false
+	}
+	return "(" + strings.Join(parts, " && ") + ")", true
+}
+
+// extraScanner дочитывает дополнительные колонки после колонок задачи
+type extraScanner struct {
+	row   rowScanner
+	extra []interface{}
+}
+
+func (s extraScanner) Scan(dest ...interface{}) error {
+	return s.row.Scan(append(dest, s.extra...)...)
+}
+
+func (r *Repository) Search(ctx context.Context, req dtos.SearchRequest) (*dtos.SearchPage, error) {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dtos.SearchPage{Items: []*dtos.SearchResult{}}
+
+	b := &queryBuilder{}
+	applyFilter(b, &userID, dtos.Filter{})
+	query, ok := tsQuery(b, req.Query)
+	if !ok {
+		return page, nil
+	}
+	b.add("search_vector @@ " + query)
+
+	r.log.Debug("Searching tasks",
+		zap.Int64("user_id", userID),
+		zap.String("query", req.Query),
+	)
+
+	err = r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks"+b.whereClause(), b.args...).Scan(&page.Total)
+	if err != nil {
+		r.log.Error("Failed to count search results",
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	// Подсветка дорогая, поэтому ts_headline считается только для строк текущей страницы
+	rankedQuery := fmt.Sprintf(`
+		SELECT %[1]s, rank,
+			ts_headline(%[2]s, %[9]s, query, '%[3]s'),
+			ts_headline(%[2]s, %[10]s, query, '%[4]s')
+		FROM (
+			SELECT %[1]s, %[5]s AS query, ts_rank_cd(search_vector, %[5]s) AS rank
+			FROM tasks%[6]s
+			ORDER BY rank DESC, id ASC
+			LIMIT %[7]s OFFSET %[8]s
+		) ranked
+		ORDER BY rank DESC, id ASC`,
+		taskColumns, searchConfig, titleHeadlineOptions, snippetHeadlineOptions,
+		query, b.whereClause(), b.arg(req.Limit), b.arg(req.Offset),
+		escapeHTML("title"), escapeHTML("COALESCE(description, '')"),
+	)
+
+	rows, err := r.db.QueryContext(ctx, rankedQuery, b.args...)
+	if err != nil {
+		r.log.Error("Failed to search tasks",
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("failed to search tasks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			t      entity.Task
+			result = &dtos.SearchResult{Task: &t}
+		)
+		scanner := extraScanner{row: rows, extra: []interface{}{&result.Rank, &result.TitleHighlight, &result.Snippet}}
+		if err := scanTask(scanner, &t); err != nil {
+			r.log.Error("Failed to scan search result",
+				zap.Error(err),
+			)
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		page.Items = append(page.Items, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.log.Error("Error during rows iteration",
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	tasks := make([]*entity.Task, 0, len(page.Items))
+	for _, result := range page.Items {
+		tasks = append(tasks, result.Task)
+	}
+	if err := loadDetails(ctx, r.db, tasks); err != nil {
+		return nil, err
+	}
+
+	r.log.Debug("Tasks found",
+		zap.Int("count", len(page.Items)),
+		zap.Int64("total", page.Total),
+	)
+	return page, nil
+}
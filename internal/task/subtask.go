@@ -0,0 +1,10 @@
+package task
+
+import "task-manager/internal/task/entity"
+
+// SubtaskRules - ограничения иерархии задач.
+// MaxDepth - сколько уровней подзадач допускается под корневой задачей.
+type SubtaskRules struct {
+	MaxDepth       int
+	OnParentDelete entity.ParentDeletePolicy
+}
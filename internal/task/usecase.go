@@ -4,14 +4,93 @@ import (
 	"context"
 	"task-manager/internal/task/dtos"
 	"task-manager/internal/task/entity"
+	"time"
 )
 
 type TaskUseCase interface {
 	CreateTask(ctx context.Context, req *dtos.CreateTaskRequest) (*entity.Task, error)
+	CreateSubtask(ctx context.Context, parentID string, req *dtos.CreateTaskRequest) (*entity.Task, error)
 	GetTask(ctx context.Context, id string) (*entity.Task, error)
 	UpdateTask(ctx context.Context, id string, req *dtos.UpdateTaskRequest) (*entity.Task, error)
+	ReopenTask(ctx context.Context, id string) (*entity.Task, error)
 	DeleteTask(ctx context.Context, id string) error
-	ListTasks(ctx context.Context, filter dtos.Filter, pagination dtos.Pagination) ([]*entity.Task, error)
+	GetTaskHistory(ctx context.Context, id string) ([]*entity.StatusChange, error)
+	ListTasks(ctx context.Context, filter dtos.Filter, pagination dtos.Pagination) (*dtos.TaskPage, error)
+	ListAllTasks(ctx context.Context, filter dtos.Filter, pagination dtos.Pagination) (*dtos.TaskPage, error)
+	ListSubtasks(
+		ctx context.Context,
+		parentID string,
+		filter dtos.Filter,
+		pagination dtos.Pagination,
+	) (*dtos.TaskPage, error)
+	SearchTasks(ctx context.Context, req dtos.SearchRequest) (*dtos.SearchPage, error)
 	GetUpcomingTasks(ctx context.Context, limit int) ([]*entity.Task, error)
 	GetOverdueTasks(ctx context.Context) ([]*entity.Task, error)
+	ProcessOverdueTasks(
+		ctx context.Context,
+		fence entity.Fence,
+		policy entity.OverduePolicy,
+		gracePeriod time.Duration,
+	) ([]*entity.Task, error)
+	GenerateOccurrences(ctx context.Context, fence entity.Fence, now time.Time) ([]*entity.Task, error)
+}
+
+type LabelUseCase interface {
+	CreateLabel(ctx context.Context, req *dtos.CreateLabelRequest) (*entity.Label, error)
+	ListLabels(ctx context.Context) ([]entity.Label, error)
+	UpdateLabel(ctx context.Context, id int64, req *dtos.UpdateLabelRequest) (*entity.Label, error)
+	DeleteLabel(ctx context.Context, id int64) error
+	AttachLabel(ctx context.Context, taskID string, labelID int64) (*entity.Task, error)
+	DetachLabel(ctx context.Context, taskID string, labelID int64) (*entity.Task, error)
+}
+
+type ChecklistUseCase interface {
+	ListItems(ctx context.Context, taskID string) ([]entity.ChecklistItem, error)
+	CreateItem(ctx context.Context, taskID string, req *dtos.CreateChecklistItemRequest) (*entity.ChecklistItem, error)
+	UpdateItem(
+		ctx context.Context,
+		taskID string,
+		itemID int64,
+		req *dtos.UpdateChecklistItemRequest,
+	) (*entity.ChecklistItem, error)
+	ToggleItem(ctx context.Context, taskID string, itemID int64) (*entity.ChecklistItem, error)
+	DeleteItem(ctx context.Context, taskID string, itemID int64) error
+	ReorderItems(ctx context.Context, taskID string, req *dtos.ReorderChecklistRequest) ([]entity.ChecklistItem, error)
+}
+
+type DependencyUseCase interface {
+	AddDependency(ctx context.Context, taskID, blockerID string) (*entity.Task, error)
+	RemoveDependency(ctx context.Context, taskID, blockerID string) (*entity.Task, error)
+	Plan(ctx context.Context, ids []string) (*dtos.DependencyPlan, error)
+}
+
+// ReminderScheduler пересчитывает расписание напоминаний после изменения задачи
+type ReminderScheduler interface {
+	Reschedule(ctx context.Context, task *entity.Task) error
+}
+
+type ReminderUseCase interface {
+	ReminderScheduler
+	ListReminders(ctx context.Context, taskID string) ([]entity.Reminder, error)
+	CreateReminder(ctx context.Context, taskID string, req *dtos.CreateReminderRequest) (*entity.Reminder, error)
+	DeleteReminder(ctx context.Context, taskID string, id int64) error
+	DeliverDue(ctx context.Context, now time.Time) (int, error)
+}
+
+// OutboxRelay переносит события задач из outbox в Redis Stream
+type OutboxRelay interface {
+	Relay(ctx context.Context, fence entity.Fence) (int, error)
+	Cleanup(ctx context.Context, before time.Time) (int64, error)
+}
+
+// EventConsumer передает события из стрима outbox обработчику одной группы потребителей
+type EventConsumer interface {
+	Consume(ctx context.Context) (int, error)
+}
+
+// LiveEventUseCase рассылает события задач в живые стримы пользователей и
+// подписывает клиентов на стрим с дочитыванием пропущенного
+type LiveEventUseCase interface {
+	EventHandler
+	Subscribe(ctx context.Context, lastEventID string) (LiveSubscription, error)
 }
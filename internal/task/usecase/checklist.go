@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"strings"
+	"task-manager/internal/task"
+	"task-manager/internal/task/dtos"
+	"task-manager/internal/task/entity"
+)
+
+type checklistUseCase struct {
+	repo task.ChecklistRepository
+	log  *zap.Logger
+}
+
+func NewChecklistUseCase(repo task.ChecklistRepository, log *zap.Logger) task.ChecklistUseCase {
+	return &checklistUseCase{
+		repo: repo,
+		log:  log.Named("checklist_usecase"),
+	}
+}
+
+func (uc *checklistUseCase) ListItems(ctx context.Context, taskID string) ([]entity.ChecklistItem, error) {
+	items, err := uc.repo.ListItems(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checklist items: %w", err)
+	}
+	return items, nil
+}
+
+func (uc *checklistUseCase) CreateItem(
+	ctx context.Context,
+	taskID string,
+	req *dtos.CreateChecklistItemRequest,
+) (*entity.ChecklistItem, error) {
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		return nil, &task.ValidationError{Field: "text", Reason: "text is required"}
+	}
+
+	item := &entity.ChecklistItem{Text: text}
+	if err := uc.repo.CreateItem(ctx, taskID, item); err != nil {
+		uc.log.Error("Failed to create checklist item",
+			zap.Error(err),
+			zap.String("task_id", taskID),
+		)
+		return nil, fmt.Errorf("failed to create checklist item: %w", err)
+	}
+	return item, nil
+}
+
+func (uc *checklistUseCase) UpdateItem(
+	ctx context.Context,
+	taskID string,
+	itemID int64,
+	req *dtos.UpdateChecklistItemRequest,
+) (*entity.ChecklistItem, error) {
+	item, err := uc.repo.GetItem(ctx, taskID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Text != nil {
+		text := strings.TrimSpace(*req.Text)
+		if text == "" {
+			return nil, &task.ValidationError{Field: "text", Reason: "text cannot be empty"}
+		}
+		item.Text = text
+	}
+	if req.Done != nil {
+		item.Done = *req.Done
+	}
+
+	if err := uc.repo.UpdateItem(ctx, item); err != nil {
+		uc.log.Error("Failed to update checklist item",
+			zap.Error(err),
+			zap.Int64("item_id", itemID),
+		)
+		return nil, fmt.Errorf("failed to update checklist item: %w", err)
+	}
+	return item, nil
+}
+
+// ToggleItem переключает отметку выполнения пункта
+func (uc *checklistUseCase) ToggleItem(ctx context.Context, taskID string, itemID int64) (*entity.ChecklistItem, error) {
+	item, err := uc.repo.GetItem(ctx, taskID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	done := !item.Done
+	return uc.UpdateItem(ctx, taskID, itemID, &dtos.UpdateChecklistItemRequest{Done: &done})
+}
+
+func (uc *checklistUseCase) DeleteItem(ctx context.Context, taskID string, itemID int64) error {
+	if err := uc.repo.DeleteItem(ctx, taskID, itemID); err != nil {
+		uc.log.Error("Failed to delete checklist item",
+			zap.Error(err),
+			zap.Int64("item_id", itemID),
+		)
+		return fmt.Errorf("failed to delete checklist item: %w", err)
+	}
+	return nil
+}
+
+// ReorderItems задает новый порядок пунктов и возвращает чек-лист в этом порядке
+func (uc *checklistUseCase) ReorderItems(
+	ctx context.Context,
+	taskID string,
+	req *dtos.ReorderChecklistRequest,
+) ([]entity.ChecklistItem, error) {
+	if err := uc.repo.ReorderItems(ctx, taskID, req.ItemIDs); err != nil {
+		uc.log.Warn("Failed to reorder checklist items",
+			zap.Error(err),
+			zap.String("task_id", taskID),
+		)
+		return nil, fmt.Errorf("failed to reorder checklist items: %w", err)
+	}
+	return uc.ListItems(ctx, taskID)
+}
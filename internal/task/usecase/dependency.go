@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"task-manager/internal/task"
+	"task-manager/internal/task/dtos"
+	"task-manager/internal/task/entity"
+)
+
+type dependencyUseCase struct {
+	deps  task.DependencyRepository
+	tasks task.TaskRepository
+	log   *zap.Logger
+}
+
+func NewDependencyUseCase(deps task.DependencyRepository, tasks task.TaskRepository, log *zap.Logger) task.DependencyUseCase {
+	return &dependencyUseCase{
+		deps:  deps,
+		tasks: tasks,
+		log:   log.Named("dependency_usecase"),
+	}
+}
+
+// AddDependency помечает задачу taskID заблокированной задачей blockerID
+func (uc *dependencyUseCase) AddDependency(ctx context.Context, taskID, blockerID string) (*entity.Task, error) {
+	// Сравниваем разобранные UUID: строки могут отличаться регистром или формой записи.
+	// Некорректные id отсекает репозиторий.
+	blocked, errBlocked := uuid.Parse(taskID)
+	blocker, errBlocker := uuid.Parse(blockerID)
+	if errBlocked == nil && errBlocker == nil && blocked == blocker {
+		return nil, &task.ValidationError{Field: "blocker_id", Reason: "task cannot block itself"}
+	}
+
+	if err := uc.deps.AddDependency(ctx, taskID, blockerID); err != nil {
+		uc.log.Warn("Failed to add dependency",
+			zap.Error(err),
+			zap.String("task_id", taskID),
+			zap.String("blocker_id", blockerID),
+		)
+		return nil, fmt.Errorf("failed to add dependency: %w", err)
+	}
+	return uc.tasks.GetByID(ctx, taskID)
+}
+
+func (uc *dependencyUseCase) RemoveDependency(ctx context.Context, taskID, blockerID string) (*entity.Task, error) {
+	if err := uc.deps.RemoveDependency(ctx, taskID, blockerID); err != nil {
+		uc.log.Warn("Failed to remove dependency",
+			zap.Error(err),
+			zap.String("task_id", taskID),
+			zap.String("blocker_id", blockerID),
+		)
+		return nil, fmt.Errorf("failed to remove dependency: %w", err)
+	}
+	return uc.tasks.GetByID(ctx, taskID)
+}
+
+// Plan строит топологический порядок и критический путь для задач ids.
+// Без ids план строится по всем невыполненным задачам пользователя.
+func (uc *dependencyUseCase) Plan(ctx context.Context, ids []string) (*dtos.DependencyPlan, error) {
+	parsed := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		u, err := uuid.Parse(id)
+		if err != nil {
+			return nil, &task.ValidationError{Field: "ids", Reason: fmt.Sprintf("invalid task id %q", id)}
+		}
+		parsed = append(parsed, u)
+	}
+
+	tasks, edges, err := uc.deps.Graph(ctx, parsed)
+	if err != nil {
+		uc.log.Error("Failed to load dependency graph", zap.Error(err))
+		return nil, fmt.Errorf("failed to load dependency graph: %w", err)
+	}
+
+	order, err := task.TopologicalOrder(tasks, edges)
+	if err != nil {
+		// Вставка ребер проверяет циклы, поэтому сюда попадаем только при порче данных
+		uc.log.Error("Dependency graph contains a cycle", zap.Int("tasks", len(tasks)))
+		return nil, err
+	}
+
+	plan := &dtos.DependencyPlan{
+		Order:        order,
+		CriticalPath: task.CriticalPath(order, edges),
+		Dependencies: edges,
+	}
+	if plan.CriticalPath == nil {
+		plan.CriticalPath = []entity.TaskRef{}
+	}
+	if plan.Dependencies == nil {
+		plan.Dependencies = []entity.Dependency{}
+	}
+	return plan, nil
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"go.uber.org/zap"
+	"task-manager/internal/task"
+)
+
+// eventBatch - сколько событий стрима читается за один запрос
+const eventBatch = 100
+
+type eventConsumer struct {
+	repo    task.EventStreamRepository
+	group   string
+	handler task.EventHandler
+	log     *zap.Logger
+}
+
+func NewEventConsumer(
+	repo task.EventStreamRepository,
+	group string,
+	handler task.EventHandler,
+	log *zap.Logger,
+) task.EventConsumer {
+	return &eventConsumer{
+		repo:    repo,
+		group:   group,
+		handler: handler,
+		log:     log.Named("event_consumer").With(zap.String("group", group)),
+	}
+}
+
+// Consume передает события группы обработчику по порядку и подтверждает обработанные.
+// На первой ошибке чтение останавливается: необработанное событие придет первым
+// в следующем запуске, и порядок событий не нарушится. Падение между обработкой
+// и подтверждением приводит к повторной обработке - доставка at-least-once.
+func (c *eventConsumer) Consume(ctx context.Context) (int, error) {
+	handled := 0
+	for {
+		batch, err := c.repo.Read(ctx, c.group, eventBatch)
+		if err != nil {
+			return handled, err
+		}
+
+		processed := make([]string, 0, len(batch))
+		var handleErr error
+		for _, m := range batch {
+			var event task.Event
+			if err := json.Unmarshal(m.Payload, &event); err != nil {
+				// Запись вытеснена из стрима или повреждена - повтор не поможет
+				c.log.Error("Skipping malformed task event",
+					zap.Error(err),
+					zap.String("stream_id", m.StreamID),
+				)
+				processed = append(processed, m.StreamID)
+				continue
+			}
+
+			if handleErr = c.handler.Handle(ctx, event); handleErr != nil {
+				c.log.Warn("Task event handling failed, will retry",
+					zap.Error(handleErr),
+					zap.String("event_id", event.ID.String()),
+				)
+				break
+			}
+			processed = append(processed, m.StreamID)
+		}
+
+		if len(processed) > 0 {
+			// Подтверждение не должно теряться из-за остановки воркера:
+			// иначе события обработаются повторно
+			if err := c.repo.Ack(context.WithoutCancel(ctx), c.group, processed...); err != nil {
+				return handled, err
+			}
+			handled += len(processed)
+		}
+
+		if handleErr != nil || len(batch) < eventBatch {
+			return handled, handleErr
+		}
+	}
+}
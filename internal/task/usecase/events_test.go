@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"slices"
+	"task-manager/internal/task"
+	"task-manager/internal/task/entity"
+	"testing"
+)
+
+// fakeEventStream отдает записи по limit штук, пока они не подтверждены
+type fakeEventStream struct {
+	messages []entity.StreamMessage
+	acked    []string
+	readErr  error
+}
+
+func (s *fakeEventStream) CreateGroup(context.Context, string) error {
+	return nil
+}
+
+func (s *fakeEventStream) Read(_ context.Context, _ string, limit int) ([]entity.StreamMessage, error) {
+	if s.readErr != nil {
+		return nil, s.readErr
+	}
+	var out []entity.StreamMessage
+	for _, m := range s.messages {
+		if !slices.Contains(s.acked, m.StreamID) && len(out) < limit {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+func (s *fakeEventStream) Ack(_ context.Context, _ string, ids ...string) error {
+	s.acked = append(s.acked, ids...)
+	return nil
+}
+
+// fakeEventHandler падает на событиях из failOn
+type fakeEventHandler struct {
+	failOn  map[uuid.UUID]bool
+	handled []uuid.UUID
+}
+
+func (h *fakeEventHandler) Handle(_ context.Context, event task.Event) error {
+	if h.failOn[event.ID] {
+		return errors.New("handler failed")
+	}
+	h.handled = append(h.handled, event.ID)
+	return nil
+}
+
+func streamMessage(t *testing.T, streamID string, event task.Event) entity.StreamMessage {
+	t.Helper()
+	payload, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("marshal event: %v", err)
+	}
+	return entity.StreamMessage{StreamID: streamID, Payload: payload}
+}
+
+func TestConsume(t *testing.T) {
+	first := task.NewEvent(task.EventTaskCreated, 1, uuid.New(), nil)
+	second := task.NewEvent(task.EventTaskUpdated, 1, uuid.New(), nil)
+	third := task.NewEvent(task.EventTaskDeleted, 1, uuid.New(), nil)
+
+	t.Run("handles and acks events in order, skipping malformed", func(t *testing.T) {
+		stream := &fakeEventStream{messages: []entity.StreamMessage{
+			streamMessage(t, "1-0", first),
+			{StreamID: "2-0"},
+			streamMessage(t, "3-0", second),
+		}}
+		handler := &fakeEventHandler{}
+
+		handled, err := NewEventConsumer(stream, "test", handler, zap.NewNop()).Consume(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if handled != 3 {
+			t.Errorf("handled = %d, want 3", handled)
+		}
+		if !slices.Equal(handler.handled, []uuid.UUID{first.ID, second.ID}) {
+			t.Errorf("handler got %v, want first and second events", handler.handled)
+		}
+		if !slices.Equal(stream.acked, []string{"1-0", "2-0", "3-0"}) {
+			t.Errorf("acked = %v, want all entries", stream.acked)
+		}
+	})
+
+	t.Run("stops at the first failure and leaves the rest unacked", func(t *testing.T) {
+		stream := &fakeEventStream{messages: []entity.StreamMessage{
+			streamMessage(t, "1-0", first),
+			streamMessage(t, "2-0", second),
+			streamMessage(t, "3-0", third),
+		}}
+		handler := &fakeEventHandler{failOn: map[uuid.UUID]bool{second.ID: true}}
+
+		handled, err := NewEventConsumer(stream, "test", handler, zap.NewNop()).Consume(context.Background())
+		if err == nil {
+			t.Fatal("expected handler error")
+		}
+		if handled != 1 {
+			t.Errorf("handled = %d, want 1", handled)
+		}
+		if !slices.Equal(stream.acked, []string{"1-0"}) {
+			t.Errorf("acked = %v, want [1-0]", stream.acked)
+		}
+		if slices.Contains(handler.handled, third.ID) {
+			t.Error("event after the failed one was handled out of order")
+		}
+	})
+
+	t.Run("reads until the stream is drained", func(t *testing.T) {
+		stream := &fakeEventStream{}
+		for i := 0; i < eventBatch+1; i++ {
+			stream.messages = append(stream.messages,
+				streamMessage(t, uuid.NewString(), task.NewEvent(task.EventTaskUpdated, 1, uuid.New(), nil)))
+		}
+
+		handled, err := NewEventConsumer(stream, "test", &fakeEventHandler{}, zap.NewNop()).Consume(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if handled != eventBatch+1 {
+			t.Errorf("handled = %d, want %d", handled, eventBatch+1)
+		}
+	})
+
+	t.Run("read error is returned", func(t *testing.T) {
+		stream := &fakeEventStream{readErr: errors.New("redis down")}
+		if _, err := NewEventConsumer(stream, "test", &fakeEventHandler{}, zap.NewNop()).Consume(context.Background()); err == nil {
+			t.Fatal("expected read error")
+		}
+	})
+}
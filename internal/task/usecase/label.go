@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"strings"
+	"task-manager/internal/task"
+	"task-manager/internal/task/dtos"
+	"task-manager/internal/task/entity"
+)
+
+type labelUseCase struct {
+	labels task.LabelRepository
+	tasks  task.TaskRepository
+	log    *zap.Logger
+}
+
+func NewLabelUseCase(labels task.LabelRepository, tasks task.TaskRepository, log *zap.Logger) task.LabelUseCase {
+	return &labelUseCase{
+		labels: labels,
+		tasks:  tasks,
+		log:    log.Named("label_usecase"),
+	}
+}
+
+func (uc *labelUseCase) CreateLabel(ctx context.Context, req *dtos.CreateLabelRequest) (*entity.Label, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, &task.ValidationError{Field: "name", Reason: "name is required"}
+	}
+
+	label := &entity.Label{
+		Name:  name,
+		Color: strings.ToLower(req.Color),
+	}
+	if label.Color == "" {
+		label.Color = dtos.DefaultLabelColor
+	}
+
+	if err := uc.labels.CreateLabel(ctx, label); err != nil {
+		uc.log.Error("Failed to create label",
+			zap.Error(err),
+			zap.String("name", name),
+		)
+		return nil, fmt.Errorf("failed to create label: %w", err)
+	}
+	return label, nil
+}
+
+func (uc *labelUseCase) ListLabels(ctx context.Context) ([]entity.Label, error) {
+	labels, err := uc.labels.ListLabels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list labels: %w", err)
+	}
+	return labels, nil
+}
+
+func (uc *labelUseCase) UpdateLabel(ctx context.Context, id int64, req *dtos.UpdateLabelRequest) (*entity.Label, error) {
+	label, err := uc.labels.GetLabel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, &task.ValidationError{Field: "name", Reason: "name cannot be empty"}
+		}
+		label.Name = name
+	}
+	if req.Color != nil {
+		label.Color = strings.ToLower(*req.Color)
+	}
+
+	if err := uc.labels.UpdateLabel(ctx, label); err != nil {
+		uc.log.Error("Failed to update label",
+			zap.Error(err),
+			zap.Int64("label_id", id),
+		)
+		return nil, fmt.Errorf("failed to update label: %w", err)
+	}
+	return label, nil
+}
+
+func (uc *labelUseCase) DeleteLabel(ctx context.Context, id int64) error {
+	if err := uc.labels.DeleteLabel(ctx, id); err != nil {
+		uc.log.Error("Failed to delete label",
+			zap.Error(err),
+			zap.Int64("label_id", id),
+		)
+		return fmt.Errorf("failed to delete label: %w", err)
+	}
+	return nil
+}
+
+// AttachLabel привязывает метку к задаче и возвращает задачу с актуальным списком меток
+func (uc *labelUseCase) AttachLabel(ctx context.Context, taskID string, labelID int64) (*entity.Task, error) {
+	if err := uc.labels.AttachLabel(ctx, taskID, labelID); err != nil {
+		uc.log.Warn("Failed to attach label",
+			zap.Error(err),
+			zap.String("task_id", taskID),
+			zap.Int64("label_id", labelID),
+		)
+		return nil, fmt.Errorf("failed to attach label: %w", err)
+	}
+	return uc.tasks.GetByID(ctx, taskID)
+}
+
+// DetachLabel отвязывает метку от задачи и возвращает задачу с актуальным списком меток
+func (uc *labelUseCase) DetachLabel(ctx context.Context, taskID string, labelID int64) (*entity.Task, error) {
+	if err := uc.labels.DetachLabel(ctx, taskID, labelID); err != nil {
+		uc.log.Warn("Failed to detach label",
+			zap.Error(err),
+			zap.String("task_id", taskID),
+			zap.Int64("label_id", labelID),
+		)
+		return nil, fmt.Errorf("failed to detach label: %w", err)
+	}
+	return uc.tasks.GetByID(ctx, taskID)
+}
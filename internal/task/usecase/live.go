@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"go.uber.org/zap"
+	"sync"
+	"task-manager/internal/task"
+	"task-manager/internal/task/entity"
+	"task-manager/pkg/database/redis"
+	"task-manager/pkg/identity"
+)
+
+type liveEventUseCase struct {
+	repo task.LiveEventRepository
+	log  *zap.Logger
+}
+
+func NewLiveEventUseCase(repo task.LiveEventRepository, log *zap.Logger) task.LiveEventUseCase {
+	return &liveEventUseCase{
+		repo: repo,
+		log:  log.Named("live_event_usecase"),
+	}
+}
+
+// Handle рассылает событие владельцу задачи. Ошибка Redis возвращается, и событие
+// придет из стрима outbox повторно. После сбоя между рассылкой и подтверждением клиент
+// может получить событие дважды: дубль узнается по id события задачи в данных.
+func (uc *liveEventUseCase) Handle(ctx context.Context, event task.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		uc.log.Error("Failed to marshal task event",
+			zap.Error(err),
+			zap.String("event_id", event.ID.String()),
+		)
+		return nil
+	}
+
+	_, err = uc.repo.Append(ctx, event.UserID, data)
+	return err
+}
+
+// Subscribe подписывает текущего пользователя на его события. С непустым lastEventID
+// сначала отдаются события бэклога после него; если часть из них уже вытеснена,
+// первым приходит событие entity.LiveEventReset.
+func (uc *liveEventUseCase) Subscribe(ctx context.Context, lastEventID string) (task.LiveSubscription, error) {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if lastEventID != "" && !redis.ValidStreamID(lastEventID) {
+		return nil, &task.ValidationError{Field: "Last-Event-ID", Reason: "malformed event id"}
+	}
+
+	// Подписка оформляется до чтения бэклога: событие, добавленное между ними,
+	// придет дважды и будет отброшено, но не потеряется
+	live, err := uc.repo.Subscribe(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var backlog []entity.LiveEvent
+	if lastEventID != "" {
+		backlog, err = uc.backlog(ctx, userID, lastEventID)
+		if err != nil {
+			live.Close()
+			return nil, err
+		}
+	}
+
+	s := &resumedSubscription{
+		live:    live,
+		backlog: backlog,
+		last:    lastEventID,
+		events:  make(chan entity.LiveEvent),
+		done:    make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// backlog возвращает события после lastEventID. Если самой lastEventID в бэклоге уже
+// нет, часть следующих за ней событий могла быть вытеснена, и клиенту уходит reset.
+func (uc *liveEventUseCase) backlog(ctx context.Context, userID int64, lastEventID string) ([]entity.LiveEvent, error) {
+	oldest, err := uc.repo.OldestID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []entity.LiveEvent
+	if backlogLost(oldest, lastEventID) {
+		uc.log.Debug("Live event backlog lost",
+			zap.Int64("user_id", userID),
+			zap.String("last_event_id", lastEventID),
+		)
+		events = append(events, entity.LiveEvent{Type: entity.LiveEventReset, Data: json.RawMessage("{}")})
+	}
+
+	since, err := uc.repo.Since(ctx, userID, lastEventID)
+	if err != nil {
+		return nil, err
+	}
+	return append(events, since...), nil
+}
+
+// backlogLost сообщает, что события после lastEventID могли быть вытеснены: бэклог
+// пуст (истек его срок жизни) или самая старая запись в нем новее lastEventID
+func backlogLost(oldest, lastEventID string) bool {
+	return oldest == "" || redis.StreamIDAfter(oldest, lastEventID)
+}
+
+// resumedSubscription отдает бэклог, затем живые события, пропуская уже отданные
+type resumedSubscription struct {
+	live    task.LiveSubscription
+	backlog []entity.LiveEvent
+	last    string
+	events  chan entity.LiveEvent
+	done    chan struct{}
+	once    sync.Once
+}
+
+func (s *resumedSubscription) run() {
+	defer close(s.events)
+
+	for _, event := range s.backlog {
+		if !s.send(event) {
+			return
+		}
+	}
+	for event := range s.live.Events() {
+		if s.last != "" && !redis.StreamIDAfter(event.ID, s.last) {
+			continue
+		}
+		if !s.send(event) {
+			return
+		}
+	}
+}
+
+func (s *resumedSubscription) send(event entity.LiveEvent) bool {
+	if event.Type == "" {
+		event.Type = eventType(event.Data)
+	}
+	if event.ID != "" {
+		s.last = event.ID
+	}
+
+	select {
+	case s.events <- event:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+func (s *resumedSubscription) Events() <-chan entity.LiveEvent {
+	return s.events
+}
+
+func (s *resumedSubscription) Close() error {
+	s.once.Do(func() { close(s.done) })
+	return s.live.Close()
+}
+
+// eventType достает тип из сохраненного task.Event
+func eventType(data json.RawMessage) string {
+	var event struct {
+		Type string `json:"type"`
+	}
+	_ = json.Unmarshal(data, &event)
+	return event.Type
+}
@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go.uber.org/zap"
+	"task-manager/internal/task"
+	"task-manager/internal/task/entity"
+	"task-manager/pkg/database/redis"
+	"task-manager/pkg/identity"
+	"testing"
+)
+
+// fakeLiveRepository хранит бэклог в памяти, живые события приходят через канал live
+type fakeLiveRepository struct {
+	backlog []entity.LiveEvent
+	live    chan entity.LiveEvent
+}
+
+func (r *fakeLiveRepository) Append(context.Context, int64, []byte) (string, error) {
+	return "", nil
+}
+
+func (r *fakeLiveRepository) Since(_ context.Context, _ int64, after string) ([]entity.LiveEvent, error) {
+	var events []entity.LiveEvent
+	for _, e := range r.backlog {
+		if redis.StreamIDAfter(e.ID, after) {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func (r *fakeLiveRepository) OldestID(context.Context, int64) (string, error) {
+	if len(r.backlog) == 0 {
+		return "", nil
+	}
+	return r.backlog[0].ID, nil
+}
+
+func (r *fakeLiveRepository) Subscribe(context.Context, int64) (task.LiveSubscription, error) {
+	return &fakeLiveSubscription{events: r.live}, nil
+}
+
+type fakeLiveSubscription struct {
+	events chan entity.LiveEvent
+}
+
+func (s *fakeLiveSubscription) Events() <-chan entity.LiveEvent {
+	return s.events
+}
+
+func (s *fakeLiveSubscription) Close() error {
+	return nil
+}
+
+func liveTaskEvent(id string) entity.LiveEvent {
+	return entity.LiveEvent{ID: id, Data: json.RawMessage(`{"type":"task.updated"}`)}
+}
+
+func TestBacklogLost(t *testing.T) {
+	tests := []struct {
+		name        string
+		oldest      string
+		lastEventID string
+		want        bool
+	}{
+		{name: "last event is still in the backlog", oldest: "5-0", lastEventID: "7-0", want: false},
+		{name: "last event is the oldest one", oldest: "5-0", lastEventID: "5-0", want: false},
+		{name: "last event was trimmed", oldest: "5-1", lastEventID: "5-0", want: true},
+		{name: "expired backlog", oldest: "", lastEventID: "5-0", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := backlogLost(tt.oldest, tt.lastEventID); got != tt.want {
+				t.Errorf("backlogLost(%q, %q) = %v, want %v", tt.oldest, tt.lastEventID, got, tt.want)
+			}
+		})
+	}
+}
+
+// collect читает из подписки n событий
+func collect(t *testing.T, sub task.LiveSubscription, n int) []entity.LiveEvent {
+	t.Helper()
+	var events []entity.LiveEvent
+	for event := range sub.Events() {
+		events = append(events, event)
+		if len(events) == n {
+			break
+		}
+	}
+	return events
+}
+
+func eventIDs(events []entity.LiveEvent) []string {
+	ids := make([]string, 0, len(events))
+	for _, e := range events {
+		if e.Type == entity.LiveEventReset {
+			ids = append(ids, entity.LiveEventReset)
+			continue
+		}
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func TestSubscribeResume(t *testing.T) {
+	ctx := identity.WithUserID(context.Background(), 1)
+
+	tests := []struct {
+		name        string
+		backlog     []string
+		live        []string
+		lastEventID string
+		want        []string
+	}{
+		{
+			name:    "without last event id only live events are sent",
+			backlog: []string{"1-0", "2-0"},
+			live:    []string{"3-0"},
+			want:    []string{"3-0"},
+		},
+		{
+			name:        "backlog after last event id comes first, duplicates are dropped",
+			backlog:     []string{"1-0", "2-0", "3-0"},
+			live:        []string{"2-0", "3-0", "4-0"},
+			lastEventID: "1-0",
+			want:        []string{"2-0", "3-0", "4-0"},
+		},
+		{
+			name:        "trimmed backlog starts with reset",
+			backlog:     []string{"5-0", "6-0"},
+			live:        []string{"7-0"},
+			lastEventID: "2-0",
+			want:        []string{entity.LiveEventReset, "5-0", "6-0", "7-0"},
+		},
+		{
+			name:        "expired backlog sends reset before live events",
+			live:        []string{"9-0"},
+			lastEventID: "2-0",
+			want:        []string{entity.LiveEventReset, "9-0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeLiveRepository{live: make(chan entity.LiveEvent, len(tt.live))}
+			for _, id := range tt.backlog {
+				repo.backlog = append(repo.backlog, liveTaskEvent(id))
+			}
+			for _, id := range tt.live {
+				repo.live <- liveTaskEvent(id)
+			}
+			close(repo.live)
+
+			sub, err := NewLiveEventUseCase(repo, zap.NewNop()).Subscribe(ctx, tt.lastEventID)
+			if err != nil {
+				t.Fatalf("Subscribe: %v", err)
+			}
+			defer sub.Close()
+
+			got := eventIDs(collect(t, sub, len(tt.want)+1))
+			if len(got) != len(tt.want) {
+				t.Fatalf("events = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("events = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSubscribeRejectsMalformedLastEventID(t *testing.T) {
+	ctx := identity.WithUserID(context.Background(), 1)
+	repo := &fakeLiveRepository{live: make(chan entity.LiveEvent)}
+
+	_, err := NewLiveEventUseCase(repo, zap.NewNop()).Subscribe(ctx, "not-an-id")
+	var validation *task.ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("err = %v, want ValidationError", err)
+	}
+}
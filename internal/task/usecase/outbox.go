@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"task-manager/internal/task"
+	"task-manager/internal/task/entity"
+	"time"
+)
+
+// outboxBatch - сколько событий outbox читается за один запрос
+const outboxBatch = 500
+
+type outboxRelay struct {
+	repo task.OutboxRepository
+	log  *zap.Logger
+}
+
+func NewOutboxRelay(repo task.OutboxRepository, log *zap.Logger) task.OutboxRelay {
+	return &outboxRelay{
+		repo: repo,
+		log:  log.Named("outbox_relay"),
+	}
+}
+
+// Relay публикует неопубликованные события по одному в порядке записи и
+// подтверждает их пачкой. При ошибке публикации подтверждаются уже отправленные
+// события, остальные уйдут в следующий запуск. Падение между публикацией и
+// подтверждением приводит к повторной отправке: доставка at-least-once,
+// получатели отбрасывают дубли по event_id. Публикация и подтверждение идут под
+// локом лидера fence: релей, чей лок перехвачен, останавливается на первой записи.
+func (r *outboxRelay) Relay(ctx context.Context, fence entity.Fence) (int, error) {
+	relayed := 0
+	for {
+		batch, err := r.repo.Pending(ctx, outboxBatch)
+		if err != nil {
+			return relayed, err
+		}
+
+		published := make([]int64, 0, len(batch))
+		var publishErr error
+		for i := range batch {
+			if _, publishErr = r.repo.Publish(ctx, fence, &batch[i]); publishErr != nil {
+				break
+			}
+			published = append(published, batch[i].ID)
+		}
+
+		if len(published) > 0 {
+			// Подтверждение не должно теряться из-за остановки воркера:
+			// иначе вся пачка уйдет повторно
+			if err := r.repo.MarkPublished(context.WithoutCancel(ctx), fence, published, time.Now()); err != nil {
+				return relayed, err
+			}
+			relayed += len(published)
+		}
+
+		if publishErr != nil || len(batch) < outboxBatch {
+			return relayed, publishErr
+		}
+	}
+}
+
+func (r *outboxRelay) Cleanup(ctx context.Context, before time.Time) (int64, error) {
+	return r.repo.DeletePublished(ctx, before)
+}
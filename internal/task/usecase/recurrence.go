@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"strings"
+	"task-manager/internal/task"
+	"task-manager/internal/task/entity"
+	"task-manager/pkg/rrule"
+	"time"
+	// Часовые пояса серий не должны зависеть от наличия tzdata в образе
+	_ "time/tzdata"
+)
+
+// dueSeriesBatch ограничивает число серий, обрабатываемых воркером за один запуск
+const dueSeriesBatch = 100
+
+// parseRecurrence проверяет правило повторения и приводит его к каноническому виду
+func parseRecurrence(value string) (*rrule.Rule, error) {
+	rule, err := rrule.Parse(value)
+	if err != nil {
+		return nil, &task.ValidationError{
+			Field:  "recurrence",
+			Reason: strings.TrimPrefix(err.Error(), rrule.ErrInvalidRule.Error()+": "),
+		}
+	}
+	return rule, nil
+}
+
+// nextOccurrence возвращает номер и срок первого вхождения серии позже now.
+// Прошедшие вхождения пропускаются, но учитываются в COUNT: серия, которую долго
+// не продвигали, сразу получает актуальное вхождение, а не по одному устаревшему
+// за запуск воркера. Правило вычисляется в часовом поясе серии. false - серия закончилась.
+func nextOccurrence(rule *rrule.Rule, s *entity.Series, now time.Time) (int, time.Time, bool, error) {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return 0, time.Time{}, false, fmt.Errorf("series %s has invalid timezone: %w", s.ID, err)
+	}
+
+	start, prev := s.StartsAt.In(loc), s.LastDueDate.In(loc)
+	for generated := s.Generated; ; generated++ {
+		next, ok := rule.Next(start, prev, generated)
+		if !ok {
+			return 0, time.Time{}, false, nil
+		}
+		if next.After(now) {
+			return generated + 1, next, true, nil
+		}
+		prev = next
+	}
+}
+
+// advanceSeries создает вхождение, следующее за occurrence. Серия продвигается только
+// от последнего созданного вхождения: завершение старого вхождения, когда следующее
+// уже создано воркером, ничего не генерирует. fence - лок лидера, если серию
+// продвигает воркер, и nil, если пользователь.
+func (uc *taskUseCase) advanceSeries(
+	ctx context.Context,
+	fence *entity.Fence,
+	seriesID uuid.UUID,
+	occurrence int,
+	now time.Time,
+) (*entity.Task, error) {
+	s, err := uc.recurrences.GetSeries(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	if s.FinishedAt != nil || s.Generated != occurrence {
+		return nil, nil
+	}
+
+	rule, err := rrule.Parse(s.Rule)
+	if err != nil {
+		return nil, fmt.Errorf("series %s has invalid rule: %w", s.ID, err)
+	}
+
+	number, next, ok, err := nextOccurrence(rule, s, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		uc.log.Info("Task series completed",
+			zap.String("series_id", s.ID.String()),
+			zap.Int("generated", s.Generated),
+		)
+		return nil, uc.recurrences.FinishSeries(ctx, fence, s.ID)
+	}
+	if skipped := number - s.Generated - 1; skipped > 0 {
+		uc.log.Info("Skipped past occurrences",
+			zap.String("series_id", s.ID.String()),
+			zap.Int("skipped", skipped),
+		)
+	}
+
+	return uc.recurrences.CreateOccurrence(ctx, fence, s, number, next)
+}
+
+// futureSeries строит серию для задачи t и следующих за ней вхождений. current - текущая
+// серия t или nil. recurrence и timezone - изменения из запроса, nil оставляет прежнее значение.
+// Если t - не первое вхождение, вхождения до нее остаются в current, а нумерация
+// и COUNT новой серии отсчитываются от t.
+func futureSeries(t *entity.Task, current *entity.Series, recurrence, timezone *string) (*entity.Series, error) {
+	series := &entity.Series{
+		UserID:      t.UserID,
+		Timezone:    entity.DefaultTimezone,
+		Title:       t.Title,
+		Description: t.Description,
+		Priority:    t.Priority,
+		StartsAt:    t.DueDate,
+		LastDueDate: t.DueDate,
+		Generated:   1,
+	}
+
+	if recurrence != nil {
+		rule, err := parseRecurrence(*recurrence)
+		if err != nil {
+			return nil, err
+		}
+		series.Rule = rule.String()
+	}
+
+	if current != nil {
+		series.Timezone = current.Timezone
+		passed := *t.Occurrence - 1
+		if recurrence == nil {
+			// COUNT считается от начала серии: новой серии остаются только непройденные вхождения
+			rule, err := rrule.Parse(current.Rule)
+			if err != nil {
+				return nil, fmt.Errorf("series %s has invalid rule: %w", current.ID, err)
+			}
+			if rule.Count > 0 {
+				rule.Count = max(rule.Count-passed, 1)
+			}
+			series.Rule = rule.String()
+		}
+		if passed == 0 {
+			series.ID = current.ID
+		}
+		series.Generated = current.Generated - passed
+		if series.Generated > 1 {
+			series.LastDueDate = current.LastDueDate
+		}
+	}
+
+	if timezone != nil {
+		series.Timezone = *timezone
+	}
+	return series, nil
+}
+
+// applyToFuture переносит изменения задачи t на все следующие вхождения ее серии.
+// recurrence - новое правило, nil оставляет прежнее, пустая строка прекращает повторение.
+func (uc *taskUseCase) applyToFuture(ctx context.Context, t *entity.Task, recurrence, timezone *string) error {
+	var current *entity.Series
+	if t.SeriesID != nil {
+		s, err := uc.recurrences.GetSeries(ctx, *t.SeriesID)
+		if err != nil {
+			return err
+		}
+		current = s
+	}
+
+	if recurrence != nil && *recurrence == "" {
+		if current == nil {
+			return nil
+		}
+		return uc.recurrences.FinishSeries(ctx, nil, current.ID)
+	}
+
+	series, err := futureSeries(t, current, recurrence, timezone)
+	if err != nil {
+		return err
+	}
+	return uc.recurrences.ReplaceSeries(ctx, t, series)
+}
+
+// GenerateOccurrences создает следующие вхождения серий, срок последнего вхождения
+// которых наступил. Вызывается воркером под локом лидера fence.
+func (uc *taskUseCase) GenerateOccurrences(ctx context.Context, fence entity.Fence, now time.Time) ([]*entity.Task, error) {
+	series, err := uc.recurrences.DueSeries(ctx, now, dueSeriesBatch)
+	if err != nil {
+		uc.log.Error("Failed to get due task series", zap.Error(err))
+		return nil, fmt.Errorf("failed to get due task series: %w", err)
+	}
+
+	var (
+		created []*entity.Task
+		errs    []error
+	)
+	for _, s := range series {
+		t, err := uc.advanceSeries(ctx, &fence, s.ID, s.Generated, now)
+		if err != nil {
+			uc.log.Error("Failed to generate occurrence",
+				zap.Error(err),
+				zap.String("series_id", s.ID.String()),
+			)
+			errs = append(errs, err)
+			continue
+		}
+		if t != nil {
+			created = append(created, t)
+		}
+	}
+	return created, errors.Join(errs...)
+}
@@ -0,0 +1,258 @@
+package usecase
+
+import (
+	"github.com/google/uuid"
+	"task-manager/internal/task/entity"
+	"task-manager/pkg/rrule"
+	"testing"
+	"time"
+)
+
+func TestNextOccurrence(t *testing.T) {
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		rule      string
+		timezone  string
+		last      time.Time
+		generated int
+		now       time.Time
+		wantOK    bool
+		wantNum   int
+		wantDue   time.Time
+	}{
+		{
+			name:      "next occurrence is already in the future",
+			rule:      "FREQ=DAILY",
+			last:      start,
+			generated: 1,
+			now:       start.Add(time.Hour),
+			wantOK:    true,
+			wantNum:   2,
+			wantDue:   start.AddDate(0, 0, 1),
+		},
+		{
+			name:      "past occurrences are skipped",
+			rule:      "FREQ=DAILY",
+			last:      start,
+			generated: 1,
+			now:       start.AddDate(0, 0, 10).Add(time.Hour),
+			wantOK:    true,
+			wantNum:   12,
+			wantDue:   start.AddDate(0, 0, 11),
+		},
+		{
+			name:      "occurrence exactly at now is skipped",
+			rule:      "FREQ=DAILY",
+			last:      start,
+			generated: 1,
+			now:       start.AddDate(0, 0, 1),
+			wantOK:    true,
+			wantNum:   3,
+			wantDue:   start.AddDate(0, 0, 2),
+		},
+		{
+			name:      "skipped occurrences count towards COUNT",
+			rule:      "FREQ=DAILY;COUNT=5",
+			last:      start,
+			generated: 1,
+			now:       start.AddDate(0, 0, 10),
+			wantOK:    false,
+		},
+		{
+			name:      "until ends the series while skipping",
+			rule:      "FREQ=WEEKLY;UNTIL=20260120",
+			last:      start,
+			generated: 1,
+			now:       start.AddDate(0, 1, 0),
+			wantOK:    false,
+		},
+		{
+			name:      "series timezone keeps local time across DST",
+			rule:      "FREQ=DAILY",
+			timezone:  "Europe/Berlin",
+			last:      time.Date(2026, 3, 28, 8, 0, 0, 0, time.UTC), // 09:00 CET
+			generated: 1,
+			now:       time.Date(2026, 3, 28, 12, 0, 0, 0, time.UTC),
+			wantOK:    true,
+			wantNum:   2,
+			wantDue:   time.Date(2026, 3, 29, 7, 0, 0, 0, time.UTC), // 09:00 CEST
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := rrule.Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.rule, err)
+			}
+			s := &entity.Series{
+				ID:          uuid.New(),
+				Rule:        tt.rule,
+				Timezone:    entity.DefaultTimezone,
+				StartsAt:    tt.last,
+				LastDueDate: tt.last,
+				Generated:   tt.generated,
+			}
+			if tt.timezone != "" {
+				s.Timezone = tt.timezone
+			}
+
+			num, due, ok, err := nextOccurrence(rule, s, tt.now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if num != tt.wantNum {
+				t.Errorf("occurrence = %d, want %d", num, tt.wantNum)
+			}
+			if !due.Equal(tt.wantDue) {
+				t.Errorf("due = %v, want %v", due, tt.wantDue)
+			}
+		})
+	}
+}
+
+func TestNextOccurrenceInvalidTimezone(t *testing.T) {
+	rule, _ := rrule.Parse("FREQ=DAILY")
+	s := &entity.Series{Timezone: "Mars/Olympus", Generated: 1}
+	if _, _, _, err := nextOccurrence(rule, s, time.Now()); err == nil {
+		t.Fatal("expected error for unknown timezone")
+	}
+}
+
+func TestFutureSeries(t *testing.T) {
+	due := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+	lastDue := time.Date(2026, 2, 5, 9, 0, 0, 0, time.UTC)
+	currentID := uuid.New()
+	str := func(s string) *string { return &s }
+	num := func(n int) *int { return &n }
+
+	current := func(rule string, generated int) *entity.Series {
+		return &entity.Series{
+			ID:          currentID,
+			Rule:        rule,
+			Timezone:    "Europe/Moscow",
+			LastDueDate: lastDue,
+			Generated:   generated,
+		}
+	}
+
+	tests := []struct {
+		name          string
+		occurrence    *int
+		current       *entity.Series
+		recurrence    *string
+		timezone      *string
+		wantRule      string
+		wantTimezone  string
+		wantGenerated int
+		wantLastDue   time.Time
+		wantSameID    bool
+	}{
+		{
+			name:          "first occurrence keeps the series id",
+			occurrence:    num(1),
+			current:       current("FREQ=DAILY;COUNT=10", 3),
+			wantRule:      "FREQ=DAILY;COUNT=10",
+			wantTimezone:  "Europe/Moscow",
+			wantGenerated: 3,
+			wantLastDue:   lastDue,
+			wantSameID:    true,
+		},
+		{
+			name:          "split shortens COUNT and renumbers generated",
+			occurrence:    num(3),
+			current:       current("FREQ=DAILY;COUNT=10", 5),
+			wantRule:      "FREQ=DAILY;COUNT=8",
+			wantTimezone:  "Europe/Moscow",
+			wantGenerated: 3,
+			wantLastDue:   lastDue,
+		},
+		{
+			name:          "split on the last generated occurrence starts from its due date",
+			occurrence:    num(5),
+			current:       current("FREQ=DAILY", 5),
+			wantRule:      "FREQ=DAILY",
+			wantTimezone:  "Europe/Moscow",
+			wantGenerated: 1,
+			wantLastDue:   due,
+		},
+		{
+			name:          "COUNT never drops below one",
+			occurrence:    num(4),
+			current:       current("FREQ=DAILY;COUNT=2", 4),
+			wantRule:      "FREQ=DAILY;COUNT=1",
+			wantTimezone:  "Europe/Moscow",
+			wantGenerated: 1,
+			wantLastDue:   due,
+		},
+		{
+			name:          "new rule replaces the old one as is",
+			occurrence:    num(2),
+			current:       current("FREQ=DAILY;COUNT=10", 3),
+			recurrence:    str("freq=weekly"),
+			wantRule:      "FREQ=WEEKLY",
+			wantTimezone:  "Europe/Moscow",
+			wantGenerated: 2,
+			wantLastDue:   lastDue,
+		},
+		{
+			name:          "timezone change",
+			occurrence:    num(1),
+			current:       current("FREQ=DAILY", 1),
+			timezone:      str("Asia/Tokyo"),
+			wantRule:      "FREQ=DAILY",
+			wantTimezone:  "Asia/Tokyo",
+			wantGenerated: 1,
+			wantLastDue:   due,
+			wantSameID:    true,
+		},
+		{
+			name:          "task without series starts a new one",
+			recurrence:    str("FREQ=MONTHLY"),
+			wantRule:      "FREQ=MONTHLY",
+			wantTimezone:  entity.DefaultTimezone,
+			wantGenerated: 1,
+			wantLastDue:   due,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &entity.Task{UserID: 1, Title: "t", DueDate: due, Occurrence: tt.occurrence}
+			if tt.current != nil {
+				task.SeriesID = &tt.current.ID
+			}
+
+			s, err := futureSeries(task, tt.current, tt.recurrence, tt.timezone)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if s.Rule != tt.wantRule {
+				t.Errorf("rule = %q, want %q", s.Rule, tt.wantRule)
+			}
+			if s.Timezone != tt.wantTimezone {
+				t.Errorf("timezone = %q, want %q", s.Timezone, tt.wantTimezone)
+			}
+			if s.Generated != tt.wantGenerated {
+				t.Errorf("generated = %d, want %d", s.Generated, tt.wantGenerated)
+			}
+			if !s.LastDueDate.Equal(tt.wantLastDue) {
+				t.Errorf("last due date = %v, want %v", s.LastDueDate, tt.wantLastDue)
+			}
+			if !s.StartsAt.Equal(due) {
+				t.Errorf("starts at = %v, want %v", s.StartsAt, due)
+			}
+			if sameID := s.ID == currentID; sameID != tt.wantSameID {
+				t.Errorf("keeps series id = %v, want %v", sameID, tt.wantSameID)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"strconv"
+	"task-manager/internal/task"
+	"task-manager/internal/task/dtos"
+	"task-manager/internal/task/entity"
+	"task-manager/pkg/notifier"
+	"time"
+)
+
+const (
+	// reminderBatch - сколько напоминаний воркер забирает за один запуск
+	reminderBatch = 100
+	// reminderLease - через сколько недоставленное напоминание вернется в выдачу
+	reminderLease = 10 * time.Minute
+	// reminderExpiry - сколько после срока задачи еще пытаться доставить напоминание.
+	// Позже оно теряет смысл, и повторы при недоступном канале прекращаются.
+	reminderExpiry = time.Hour
+)
+
+type reminderUseCase struct {
+	repo     task.ReminderRepository
+	tasks    task.TaskRepository
+	notifier notifier.Notifier
+	log      *zap.Logger
+}
+
+func NewReminderUseCase(
+	repo task.ReminderRepository,
+	tasks task.TaskRepository,
+	notifier notifier.Notifier,
+	log *zap.Logger,
+) task.ReminderUseCase {
+	return &reminderUseCase{
+		repo:     repo,
+		tasks:    tasks,
+		notifier: notifier,
+		log:      log.Named("reminder_usecase"),
+	}
+}
+
+func (uc *reminderUseCase) ListReminders(ctx context.Context, taskID string) ([]entity.Reminder, error) {
+	reminders, err := uc.repo.ListReminders(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reminders: %w", err)
+	}
+	return reminders, nil
+}
+
+func (uc *reminderUseCase) CreateReminder(
+	ctx context.Context,
+	taskID string,
+	req *dtos.CreateReminderRequest,
+) (*entity.Reminder, error) {
+	t, err := uc.tasks.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	reminder := &entity.Reminder{BeforeMinutes: *req.BeforeMinutes}
+	if err := uc.repo.CreateReminder(ctx, taskID, reminder); err != nil {
+		return nil, err
+	}
+	reminder.FireAt = reminder.FireTime(t.DueDate)
+
+	if err := uc.schedule(ctx, t, *reminder); err != nil {
+		return nil, err
+	}
+
+	uc.log.Info("Reminder created",
+		zap.String("task_id", taskID),
+		zap.Int64("reminder_id", reminder.ID),
+		zap.Time("fire_at", reminder.FireAt),
+	)
+	return reminder, nil
+}
+
+// DeleteReminder удаляет напоминание. Если убрать его из расписания не удалось,
+// воркер сам отбросит запись, не найдя напоминание в базе.
+func (uc *reminderUseCase) DeleteReminder(ctx context.Context, taskID string, id int64) error {
+	if err := uc.repo.DeleteReminder(ctx, taskID, id); err != nil {
+		return err
+	}
+
+	if err := uc.repo.Unschedule(ctx, id); err != nil {
+		uc.log.Warn("Failed to unschedule deleted reminder",
+			zap.Error(err),
+			zap.Int64("reminder_id", id),
+		)
+	}
+	return nil
+}
+
+// Reschedule приводит расписание напоминаний в соответствие с задачей: после переноса
+// срока напоминания планируются заново, выполненная или архивная задача снимает их.
+func (uc *reminderUseCase) Reschedule(ctx context.Context, t *entity.Task) error {
+	reminders, err := uc.repo.TaskReminders(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+
+	var cancelled []int64
+	for _, reminder := range reminders {
+		if !active(t, reminder) {
+			cancelled = append(cancelled, reminder.ID)
+			continue
+		}
+		reminder.FireAt = reminder.FireTime(t.DueDate)
+		if err := uc.repo.Schedule(ctx, reminder.ID, reminder.FireAt); err != nil {
+			return err
+		}
+	}
+	return uc.repo.Unschedule(ctx, cancelled...)
+}
+
+func (uc *reminderUseCase) schedule(ctx context.Context, t *entity.Task, reminder entity.Reminder) error {
+	if !active(t, reminder) {
+		return nil
+	}
+	return uc.repo.Schedule(ctx, reminder.ID, reminder.FireAt)
+}
+
+// active сообщает, нужно ли еще напоминать о задаче. Напоминание, чье время уже прошло,
+// но срок задачи еще впереди, отправляется сразу.
+func active(t *entity.Task, reminder entity.Reminder) bool {
+	return t.Status != entity.StatusDone &&
+		t.ArchivedAt == nil &&
+		t.DueDate.After(time.Now()) &&
+		!reminder.SentFor(t.DueDate)
+}
+
+// DeliverDue отправляет сработавшие напоминания. Недоставленные остаются арендованными
+// и вернутся в выдачу через reminderLease, поэтому доставка - at-least-once.
+// Повторы ограничены сроком задачи: через reminderExpiry после него напоминание отбрасывается.
+func (uc *reminderUseCase) DeliverDue(ctx context.Context, now time.Time) (int, error) {
+	ids, leaseUntil, err := uc.repo.ClaimDue(ctx, now, reminderBatch, reminderLease)
+	if err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	due, err := uc.repo.GetDue(ctx, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	found := make(map[int64]bool, len(due))
+	for _, d := range due {
+		found[d.ID] = true
+	}
+
+	// Напоминания и задачи, которых уже нет в базе, просто убираются из расписания
+	var acked []int64
+	for _, id := range ids {
+		if !found[id] {
+			acked = append(acked, id)
+		}
+	}
+
+	sent := 0
+	for _, d := range due {
+		switch {
+		case d.Task.Status == entity.StatusDone || d.Task.ArchivedAt != nil || d.SentFor(d.Task.DueDate):
+			acked = append(acked, d.ID)
+		case now.After(d.Task.DueDate.Add(reminderExpiry)):
+			uc.log.Warn("Dropping expired reminder",
+				zap.Int64("reminder_id", d.ID),
+				zap.Time("due_date", d.Task.DueDate),
+			)
+			acked = append(acked, d.ID)
+		case d.FireAt.After(now):
+			// Срок перенесли, а перепланирование не дошло до Redis
+			if err := uc.repo.Schedule(ctx, d.ID, d.FireAt); err != nil {
+				uc.log.Warn("Failed to reschedule reminder",
+					zap.Error(err),
+					zap.Int64("reminder_id", d.ID),
+				)
+			}
+		default:
+			if err := uc.notifier.Notify(ctx, reminderMessage(d)); err != nil {
+				uc.log.Warn("Failed to deliver reminder, will retry after lease",
+					zap.Error(err),
+					zap.Int64("reminder_id", d.ID),
+					zap.Time("retry_at", leaseUntil),
+				)
+				continue
+			}
+			if err := uc.repo.MarkSent(ctx, d.ID, d.Task.DueDate); err != nil {
+				uc.log.Error("Failed to mark reminder as sent",
+					zap.Error(err),
+					zap.Int64("reminder_id", d.ID),
+				)
+				continue
+			}
+			acked = append(acked, d.ID)
+			sent++
+		}
+	}
+
+	if err := uc.repo.Ack(ctx, leaseUntil, acked...); err != nil {
+		return sent, err
+	}
+	return sent, nil
+}
+
+func reminderMessage(d *entity.DueReminder) notifier.Message {
+	return notifier.Message{
+		UserID:  d.Task.UserID,
+		Email:   d.Email,
+		Subject: "Reminder: " + d.Task.Title,
+		Body: fmt.Sprintf("Task %q is due %s.",
+			d.Task.Title, d.Task.DueDate.UTC().Format("2006-01-02 15:04 MST")),
+		Meta: map[string]string{
+			"task_id":     d.TaskID.String(),
+			"reminder_id": strconv.FormatInt(d.ID, 10),
+			"due_date":    d.Task.DueDate.Format(time.RFC3339),
+		},
+	}
+}
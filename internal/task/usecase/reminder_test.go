@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"slices"
+	"task-manager/internal/task/entity"
+	"task-manager/pkg/notifier"
+	"testing"
+	"time"
+)
+
+// fakeReminderRepository держит расписание в памяти и запоминает вызовы
+type fakeReminderRepository struct {
+	claimed   []int64
+	due       []*entity.DueReminder
+	scheduled map[int64]time.Time
+	sent      []int64
+	acked     []int64
+	leaseAck  time.Time
+}
+
+func (r *fakeReminderRepository) ListReminders(context.Context, string) ([]entity.Reminder, error) {
+	return nil, nil
+}
+
+func (r *fakeReminderRepository) CreateReminder(context.Context, string, *entity.Reminder) error {
+	return nil
+}
+
+func (r *fakeReminderRepository) DeleteReminder(context.Context, string, int64) error {
+	return nil
+}
+
+func (r *fakeReminderRepository) TaskReminders(context.Context, uuid.UUID) ([]entity.Reminder, error) {
+	return nil, nil
+}
+
+func (r *fakeReminderRepository) Schedule(_ context.Context, id int64, at time.Time) error {
+	if r.scheduled == nil {
+		r.scheduled = map[int64]time.Time{}
+	}
+	r.scheduled[id] = at
+	return nil
+}
+
+func (r *fakeReminderRepository) Unschedule(context.Context, ...int64) error {
+	return nil
+}
+
+func (r *fakeReminderRepository) ClaimDue(
+	_ context.Context,
+	now time.Time,
+	_ int,
+	lease time.Duration,
+) ([]int64, time.Time, error) {
+	return r.claimed, now.Add(lease), nil
+}
+
+func (r *fakeReminderRepository) GetDue(context.Context, []int64) ([]*entity.DueReminder, error) {
+	return r.due, nil
+}
+
+func (r *fakeReminderRepository) MarkSent(_ context.Context, id int64, _ time.Time) error {
+	r.sent = append(r.sent, id)
+	return nil
+}
+
+func (r *fakeReminderRepository) Ack(_ context.Context, leaseUntil time.Time, ids ...int64) error {
+	r.leaseAck = leaseUntil
+	r.acked = append(r.acked, ids...)
+	return nil
+}
+
+// fakeNotifier отвечает ошибкой err на каждое уведомление
+type fakeNotifier struct {
+	err      error
+	messages []notifier.Message
+}
+
+func (n *fakeNotifier) Notify(_ context.Context, msg notifier.Message) error {
+	n.messages = append(n.messages, msg)
+	return n.err
+}
+
+func dueReminder(id int64, dueDate time.Time, status entity.Status) *entity.DueReminder {
+	return &entity.DueReminder{
+		Reminder: entity.Reminder{ID: id, TaskID: uuid.New(), FireAt: dueDate.Add(-time.Hour), BeforeMinutes: 60},
+		Task:     entity.Task{UserID: 1, Title: "task", Status: status, DueDate: dueDate},
+		Email:    "user@example.com",
+	}
+}
+
+func TestDeliverDue(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	sentDue := now.Add(time.Hour)
+
+	alreadySent := dueReminder(4, sentDue, entity.StatusPending)
+	alreadySent.SentDueDate = &sentDue
+
+	moved := dueReminder(5, now.Add(3*time.Hour), entity.StatusPending)
+
+	tests := []struct {
+		name          string
+		claimed       []int64
+		due           []*entity.DueReminder
+		notifyErr     error
+		wantSent      int
+		wantNotified  int
+		wantAcked     []int64
+		wantScheduled []int64
+	}{
+		{
+			name:         "delivered reminder is marked and acked",
+			claimed:      []int64{1},
+			due:          []*entity.DueReminder{dueReminder(1, now.Add(30*time.Minute), entity.StatusPending)},
+			wantSent:     1,
+			wantNotified: 1,
+			wantAcked:    []int64{1},
+		},
+		{
+			name:    "missing, done and already sent reminders are acked without delivery",
+			claimed: []int64{2, 3, 4, 9},
+			due: []*entity.DueReminder{
+				dueReminder(2, now.Add(time.Hour), entity.StatusDone),
+				func() *entity.DueReminder {
+					d := dueReminder(3, now.Add(time.Hour), entity.StatusPending)
+					d.Task.ArchivedAt = &now
+					return d
+				}(),
+				alreadySent,
+			},
+			wantAcked: []int64{9, 2, 3, 4},
+		},
+		{
+			name:          "reminder of a moved due date is rescheduled",
+			claimed:       []int64{5},
+			due:           []*entity.DueReminder{moved},
+			wantScheduled: []int64{5},
+		},
+		{
+			name:         "failed delivery stays leased for a retry",
+			claimed:      []int64{6},
+			due:          []*entity.DueReminder{dueReminder(6, now.Add(30*time.Minute), entity.StatusPending)},
+			notifyErr:    errors.New("smtp down"),
+			wantNotified: 1,
+		},
+		{
+			name:         "failed delivery within expiry after the due date is retried",
+			claimed:      []int64{7},
+			due:          []*entity.DueReminder{dueReminder(7, now.Add(-reminderExpiry/2), entity.StatusPending)},
+			notifyErr:    errors.New("smtp down"),
+			wantNotified: 1,
+		},
+		{
+			name:      "reminder past expiry is dropped without delivery",
+			claimed:   []int64{8},
+			due:       []*entity.DueReminder{dueReminder(8, now.Add(-reminderExpiry-time.Minute), entity.StatusPending)},
+			notifyErr: errors.New("smtp down"),
+			wantAcked: []int64{8},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeReminderRepository{claimed: tt.claimed, due: tt.due}
+			notify := &fakeNotifier{err: tt.notifyErr}
+			uc := NewReminderUseCase(repo, nil, notify, zap.NewNop())
+
+			sent, err := uc.DeliverDue(context.Background(), now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sent != tt.wantSent {
+				t.Errorf("sent = %d, want %d", sent, tt.wantSent)
+			}
+			if len(notify.messages) != tt.wantNotified {
+				t.Errorf("notified %d times, want %d", len(notify.messages), tt.wantNotified)
+			}
+			if !slices.Equal(repo.acked, tt.wantAcked) {
+				t.Errorf("acked = %v, want %v", repo.acked, tt.wantAcked)
+			}
+			if len(repo.acked) > 0 && !repo.leaseAck.Equal(now.Add(reminderLease)) {
+				t.Errorf("ack lease = %v, want %v", repo.leaseAck, now.Add(reminderLease))
+			}
+			for _, id := range tt.wantScheduled {
+				if _, ok := repo.scheduled[id]; !ok {
+					t.Errorf("reminder %d was not rescheduled", id)
+				}
+			}
+			if len(repo.sent) != tt.wantSent {
+				t.Errorf("marked sent = %v, want %d reminders", repo.sent, tt.wantSent)
+			}
+		})
+	}
+}
+
+func TestDeliverDueNothingClaimed(t *testing.T) {
+	repo := &fakeReminderRepository{}
+	notify := &fakeNotifier{}
+	uc := NewReminderUseCase(repo, nil, notify, zap.NewNop())
+
+	sent, err := uc.DeliverDue(context.Background(), time.Now())
+	if err != nil || sent != 0 {
+		t.Fatalf("DeliverDue = %d, %v; want 0, nil", sent, err)
+	}
+	if repo.acked != nil {
+		t.Errorf("unexpected ack of %v", repo.acked)
+	}
+}
@@ -4,22 +4,42 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"strings"
 	"task-manager/internal/task"
 	"task-manager/internal/task/dtos"
 	"task-manager/internal/task/entity"
 	"time"
 )
 
+// maxSearchQueryLength ограничивает длину поисковой строки
+const maxSearchQueryLength = 200
+
 type taskUseCase struct {
-	repo task.TaskRepository
-	log  *zap.Logger
+	repo        task.TaskRepository
+	recurrences task.RecurrenceRepository
+	reminders   task.ReminderScheduler
+	workflow    *task.Workflow
+	subtasks    task.SubtaskRules
+	log         *zap.Logger
 }
 
-func NewTaskUseCase(repo task.TaskRepository, log *zap.Logger) task.TaskUseCase {
+func NewTaskUseCase(
+	repo task.TaskRepository,
+	recurrences task.RecurrenceRepository,
+	reminders task.ReminderScheduler,
+	workflow *task.Workflow,
+	subtasks task.SubtaskRules,
+	log *zap.Logger,
+) task.TaskUseCase {
 	return &taskUseCase{
-		repo: repo,
-		log:  log.Named("task_usecase"),
+		repo:        repo,
+		recurrences: recurrences,
+		reminders:   reminders,
+		workflow:    workflow,
+		subtasks:    subtasks,
+		log:         log.Named("task_usecase"),
 	}
 }
 
@@ -31,25 +51,61 @@ func (uc *taskUseCase) CreateTask(ctx context.Context, req *dtos.CreateTaskReque
 
 	if req.Title == "" {
 		uc.log.Warn("Validation failed: empty title")
-		return nil, errors.New("title is required")
+		return nil, &task.ValidationError{Field: "title", Reason: "title is required"}
 	}
 
 	if req.DueDate.Before(time.Now().Add(-1 * time.Minute)) {
 		uc.log.Warn("Validation failed: due date in past",
 			zap.Time("due_date", req.DueDate),
 		)
-		return nil, errors.New("due date cannot be in the past")
+		return nil, &task.ValidationError{Field: "due_date", Reason: "due date cannot be in the past"}
+	}
+
+	if req.Priority == "" {
+		req.Priority = string(entity.PriorityMedium)
+	}
+	if !entity.Priority(req.Priority).Valid() {
+		uc.log.Warn("Validation failed: unknown priority",
+			zap.String("priority", req.Priority),
+		)
+		return nil, &task.ValidationError{Field: "priority", Reason: "unknown priority " + req.Priority}
 	}
 
-	task := &entity.Task{
+	if req.Timezone != nil && req.Recurrence == nil {
+		return nil, &task.ValidationError{Field: "timezone", Reason: "timezone is only used with recurrence"}
+	}
+
+	var recurrence *string
+	if req.Recurrence != nil {
+		rule, err := parseRecurrence(*req.Recurrence)
+		if err != nil {
+			uc.log.Warn("Validation failed: invalid recurrence rule",
+				zap.String("recurrence", *req.Recurrence),
+			)
+			return nil, err
+		}
+		canonical := rule.String()
+		recurrence = &canonical
+	}
+
+	parentID, err := uc.checkParent(ctx, req.ParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &entity.Task{
+		UserID:      req.UserID,
+		ParentID:    parentID,
 		Title:       req.Title,
 		Description: req.Description,
 		Status:      entity.StatusPending,
 		Priority:    entity.Priority(req.Priority),
 		DueDate:     req.DueDate,
+		Recurrence:  recurrence,
+		Timezone:    req.Timezone,
 	}
 
-	if err := uc.repo.Create(ctx, task); err != nil {
+	if err := uc.repo.Create(ctx, t); err != nil {
 		uc.log.Error("Failed to create task",
 			zap.Error(err),
 			zap.String("title", req.Title),
@@ -58,9 +114,68 @@ func (uc *taskUseCase) CreateTask(ctx context.Context, req *dtos.CreateTaskReque
 	}
 
 	uc.log.Info("Task created successfully",
-		zap.String("task_id", task.ID.String()),
+		zap.String("task_id", t.ID.String()),
 	)
-	return task, nil
+	return t, nil
+}
+
+// checkParent проверяет, что родитель принадлежит пользователю и новая подзадача
+// не превысит допустимую глубину иерархии
+func (uc *taskUseCase) checkParent(ctx context.Context, parentID *string) (*uuid.UUID, error) {
+	if parentID == nil {
+		return nil, nil
+	}
+
+	parent, err := uc.repo.GetByID(ctx, *parentID)
+	if errors.Is(err, task.ErrNotFound) {
+		return nil, &task.ValidationError{Field: "parent_id", Reason: "parent task not found"}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parent task: %w", err)
+	}
+
+	depth, err := uc.repo.Depth(ctx, *parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parent depth: %w", err)
+	}
+	if depth+1 > uc.subtasks.MaxDepth {
+		uc.log.Warn("Validation failed: subtask depth limit",
+			zap.String("parent_id", *parentID),
+			zap.Int("depth", depth+1),
+		)
+		return nil, &task.ValidationError{
+			Field:  "parent_id",
+			Reason: fmt.Sprintf("subtasks cannot be nested deeper than %d levels", uc.subtasks.MaxDepth),
+		}
+	}
+	return &parent.ID, nil
+}
+
+// CreateSubtask создает подзадачу для parentID.
+// Родитель здесь - ресурс из пути, поэтому его отсутствие - 404, а не ошибка валидации.
+func (uc *taskUseCase) CreateSubtask(ctx context.Context, parentID string, req *dtos.CreateTaskRequest) (*entity.Task, error) {
+	if _, err := uc.repo.GetByID(ctx, parentID); err != nil {
+		return nil, fmt.Errorf("failed to get parent task: %w", err)
+	}
+
+	req.ParentID = &parentID
+	return uc.CreateTask(ctx, req)
+}
+
+// ListSubtasks возвращает прямые подзадачи задачи parentID
+func (uc *taskUseCase) ListSubtasks(
+	ctx context.Context,
+	parentID string,
+	filter dtos.Filter,
+	pagination dtos.Pagination,
+) (*dtos.TaskPage, error) {
+	parent, err := uc.repo.GetByID(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter.ParentID = &parent.ID
+	return uc.ListTasks(ctx, filter, pagination)
 }
 
 func (uc *taskUseCase) GetTask(ctx context.Context, id string) (*entity.Task, error) {
@@ -88,7 +203,7 @@ func (uc *taskUseCase) UpdateTask(ctx context.Context, id string, req *dtos.Upda
 		zap.Any("request", req),
 	)
 
-	task, err := uc.repo.GetByID(ctx, id)
+	t, err := uc.repo.GetByID(ctx, id)
 	if err != nil {
 		uc.log.Warn("Task not found for update",
 			zap.String("task_id", id),
@@ -97,29 +212,72 @@ func (uc *taskUseCase) UpdateTask(ctx context.Context, id string, req *dtos.Upda
 		return nil, fmt.Errorf("task not found: %w", err)
 	}
 
+	scope := entity.EditScopeThis
+	if req.Scope != "" {
+		scope = entity.EditScope(req.Scope)
+	}
+	if !scope.Valid() {
+		return nil, &task.ValidationError{Field: "scope", Reason: "unknown scope " + req.Scope}
+	}
+	if req.Recurrence != nil && scope != entity.EditScopeFuture {
+		return nil, &task.ValidationError{Field: "recurrence", Reason: "recurrence can only be changed with scope=future"}
+	}
+	if req.Timezone != nil && scope != entity.EditScopeFuture {
+		return nil, &task.ValidationError{Field: "timezone", Reason: "timezone can only be changed with scope=future"}
+	}
+	if scope == entity.EditScopeFuture && t.SeriesID == nil && req.Recurrence == nil {
+		return nil, &task.ValidationError{Field: "scope", Reason: "task is not recurring"}
+	}
+	if req.Recurrence != nil && *req.Recurrence != "" {
+		if _, err := parseRecurrence(*req.Recurrence); err != nil {
+			return nil, err
+		}
+	}
+	oldStatus := t.Status
+
 	if req.Title != nil {
-		task.Title = *req.Title
+		t.Title = *req.Title
 	}
 	if req.Description != nil {
-		task.Description = req.Description
+		t.Description = req.Description
 	}
 	if req.Status != nil {
-		task.Status = entity.Status(*req.Status)
+		newStatus := entity.Status(*req.Status)
+		if err := uc.workflow.Check(t.Status, newStatus, task.ActionUpdate); err != nil {
+			uc.log.Warn("Status transition rejected",
+				zap.String("task_id", id),
+				zap.String("from", string(t.Status)),
+				zap.String("to", string(newStatus)),
+			)
+			return nil, err
+		}
+		t.Status = newStatus
 	}
 	if req.Priority != nil {
-		task.Priority = entity.Priority(*req.Priority)
+		if !entity.Priority(*req.Priority).Valid() {
+			return nil, &task.ValidationError{Field: "priority", Reason: "unknown priority " + *req.Priority}
+		}
+		t.Priority = entity.Priority(*req.Priority)
 	}
 	if req.DueDate != nil {
 		if req.DueDate.Before(time.Now()) {
 			uc.log.Warn("Invalid due date update",
 				zap.Time("new_due_date", *req.DueDate),
 			)
-			return nil, errors.New("new due date cannot be in the past")
+			return nil, &task.ValidationError{Field: "due_date", Reason: "new due date cannot be in the past"}
 		}
-		task.DueDate = *req.DueDate
+		t.DueDate = *req.DueDate
 	}
 
-	if err := uc.repo.Update(ctx, task); err != nil {
+	if err := uc.repo.Update(ctx, t); err != nil {
+		var blocked *task.BlockedError
+		if errors.As(err, &blocked) {
+			uc.log.Warn("Status change rejected: task is blocked",
+				zap.String("task_id", id),
+				zap.String("to", string(t.Status)),
+			)
+			return nil, err
+		}
 		uc.log.Error("Failed to update task",
 			zap.Error(err),
 			zap.String("task_id", id),
@@ -127,16 +285,95 @@ func (uc *taskUseCase) UpdateTask(ctx context.Context, id string, req *dtos.Upda
 		return nil, fmt.Errorf("failed to update task: %w", err)
 	}
 
+	if scope == entity.EditScopeFuture {
+		if err := uc.applyToFuture(ctx, t, req.Recurrence, req.Timezone); err != nil {
+			uc.log.Error("Failed to apply changes to future occurrences",
+				zap.Error(err),
+				zap.String("task_id", id),
+			)
+			return nil, fmt.Errorf("failed to update future occurrences: %w", err)
+		}
+	}
+
+	if req.Status != nil || req.DueDate != nil {
+		uc.rescheduleReminders(ctx, t)
+	}
+
+	// Завершенное вхождение порождает следующее. Ошибка не отменяет обновление:
+	// вхождение позже создаст воркер, когда наступит срок.
+	if t.Status == entity.StatusDone && oldStatus != entity.StatusDone && t.SeriesID != nil {
+		next, err := uc.advanceSeries(ctx, nil, *t.SeriesID, *t.Occurrence, time.Now())
+		if err != nil {
+			uc.log.Error("Failed to generate next occurrence",
+				zap.Error(err),
+				zap.String("task_id", id),
+			)
+		} else if next != nil {
+			uc.log.Info("Next occurrence created",
+				zap.String("task_id", id),
+				zap.String("next_task_id", next.ID.String()),
+				zap.Time("due_date", next.DueDate),
+			)
+		}
+	}
+
 	uc.log.Info("Task updated successfully",
 		zap.String("task_id", id),
 	)
-	return task, nil
+	return t, nil
+}
+
+// rescheduleReminders пересчитывает напоминания задачи. Ошибка не отменяет изменение:
+// перед отправкой воркер сверяет напоминание с актуальным сроком и статусом задачи.
+func (uc *taskUseCase) rescheduleReminders(ctx context.Context, t *entity.Task) {
+	if err := uc.reminders.Reschedule(ctx, t); err != nil {
+		uc.log.Warn("Failed to reschedule reminders",
+			zap.Error(err),
+			zap.String("task_id", t.ID.String()),
+		)
+	}
+}
+
+// ReopenTask переоткрывает выполненную задачу - единственный способ вывести ее из done
+func (uc *taskUseCase) ReopenTask(ctx context.Context, id string) (*entity.Task, error) {
+	uc.log.Debug("Reopening task", zap.String("task_id", id))
+
+	t, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		uc.log.Warn("Task not found for reopen",
+			zap.String("task_id", id),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	if err := uc.workflow.Check(t.Status, entity.StatusPending, task.ActionReopen); err != nil {
+		uc.log.Warn("Reopen rejected",
+			zap.String("task_id", id),
+			zap.String("status", string(t.Status)),
+		)
+		return nil, err
+	}
+
+	t.Status = entity.StatusPending
+	if err := uc.repo.Update(ctx, t); err != nil {
+		uc.log.Error("Failed to reopen task",
+			zap.Error(err),
+			zap.String("task_id", id),
+		)
+		return nil, fmt.Errorf("failed to reopen task: %w", err)
+	}
+
+	uc.rescheduleReminders(ctx, t)
+
+	uc.log.Info("Task reopened", zap.String("task_id", id))
+	return t, nil
 }
 
 func (uc *taskUseCase) DeleteTask(ctx context.Context, id string) error {
 	uc.log.Debug("Deleting task", zap.String("task_id", id))
 
-	if err := uc.repo.Delete(ctx, id); err != nil {
+	if err := uc.repo.Delete(ctx, id, uc.subtasks.OnParentDelete); err != nil {
 		uc.log.Error("Failed to delete task",
 			zap.Error(err),
 			zap.String("task_id", id),
@@ -150,11 +387,26 @@ func (uc *taskUseCase) DeleteTask(ctx context.Context, id string) error {
 	return nil
 }
 
+func (uc *taskUseCase) GetTaskHistory(ctx context.Context, id string) ([]*entity.StatusChange, error) {
+	uc.log.Debug("Getting task status history", zap.String("task_id", id))
+
+	history, err := uc.repo.GetStatusHistory(ctx, id)
+	if err != nil {
+		uc.log.Error("Failed to get task status history",
+			zap.Error(err),
+			zap.String("task_id", id),
+		)
+		return nil, fmt.Errorf("failed to get task history: %w", err)
+	}
+
+	return history, nil
+}
+
 func (uc *taskUseCase) ListTasks(
 	ctx context.Context,
 	filter dtos.Filter,
 	pagination dtos.Pagination,
-) ([]*entity.Task, error) {
+) (*dtos.TaskPage, error) {
 	uc.log.Debug("Listing tasks",
 		zap.Any("filter", filter),
 		zap.Any("pagination", pagination),
@@ -166,8 +418,11 @@ func (uc *taskUseCase) ListTasks(
 			zap.Int("new_limit", pagination.Limit),
 		)
 	}
+	if pagination.Offset < 0 {
+		pagination.Offset = 0
+	}
 
-	tasks, err := uc.repo.List(ctx, filter, pagination)
+	page, err := uc.repo.List(ctx, filter, pagination)
 	if err != nil {
 		uc.log.Error("Failed to list tasks",
 			zap.Error(err),
@@ -176,9 +431,60 @@ func (uc *taskUseCase) ListTasks(
 	}
 
 	uc.log.Debug("Tasks listed",
-		zap.Int("count", len(tasks)),
+		zap.Int("count", len(page.Items)),
+		zap.Int64("total", page.Total),
 	)
-	return tasks, nil
+	return page, nil
+}
+
+// ListAllTasks возвращает задачи всех пользователей (для администраторов)
+func (uc *taskUseCase) ListAllTasks(
+	ctx context.Context,
+	filter dtos.Filter,
+	pagination dtos.Pagination,
+) (*dtos.TaskPage, error) {
+	if pagination.Limit <= 0 || pagination.Limit > 100 {
+		pagination.Limit = 50
+	}
+	if pagination.Offset < 0 {
+		pagination.Offset = 0
+	}
+
+	page, err := uc.repo.ListAll(ctx, filter, pagination)
+	if err != nil {
+		uc.log.Error("Failed to list all tasks",
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	return page, nil
+}
+
+// SearchTasks выполняет полнотекстовый поиск по задачам пользователя
+func (uc *taskUseCase) SearchTasks(ctx context.Context, req dtos.SearchRequest) (*dtos.SearchPage, error) {
+	req.Query = strings.TrimSpace(req.Query)
+	if req.Query == "" {
+		return nil, &task.ValidationError{Field: "q", Reason: "search query is required"}
+	}
+	if len(req.Query) > maxSearchQueryLength {
+		return nil, &task.ValidationError{Field: "q", Reason: fmt.Sprintf("search query must be at most %d characters", maxSearchQueryLength)}
+	}
+
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	page, err := uc.repo.Search(ctx, req)
+	if err != nil {
+		uc.log.Error("Failed to search tasks",
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("failed to search tasks: %w", err)
+	}
+	return page, nil
 }
 
 func (uc *taskUseCase) GetUpcomingTasks(ctx context.Context, limit int) ([]*entity.Task, error) {
@@ -193,12 +499,16 @@ func (uc *taskUseCase) GetUpcomingTasks(ctx context.Context, limit int) ([]*enti
 		)
 	}
 
-	return uc.repo.List(ctx, dtos.Filter{
-		Status: entity.StatusPending,
+	page, err := uc.repo.List(ctx, dtos.Filter{
+		Statuses: []entity.Status{entity.StatusPending},
 	}, dtos.Pagination{
 		Limit:  limit,
 		Offset: 0,
 	})
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
 }
 
 func (uc *taskUseCase) GetOverdueTasks(ctx context.Context) ([]*entity.Task, error) {
@@ -218,3 +528,37 @@ func (uc *taskUseCase) GetOverdueTasks(ctx context.Context) ([]*entity.Task, err
 	)
 	return tasks, nil
 }
+
+// ProcessOverdueTasks применяет политику к просроченным задачам. Архивация и удаление
+// ждут еще gracePeriod после срока, пометка и эскалация срабатывают сразу.
+// Вызывается воркером под локом лидера fence.
+func (uc *taskUseCase) ProcessOverdueTasks(
+	ctx context.Context,
+	fence entity.Fence,
+	policy entity.OverduePolicy,
+	gracePeriod time.Duration,
+) ([]*entity.Task, error) {
+	if !policy.Valid() {
+		return nil, fmt.Errorf("unknown overdue policy %q", policy)
+	}
+
+	threshold := time.Now()
+	if policy.Destructive() {
+		threshold = threshold.Add(-gracePeriod)
+	}
+	uc.log.Debug("Processing overdue tasks",
+		zap.String("policy", string(policy)),
+		zap.Time("threshold", threshold),
+	)
+
+	tasks, err := uc.repo.ApplyOverduePolicy(ctx, fence, policy, uc.subtasks.OnParentDelete, threshold)
+	if err != nil {
+		uc.log.Error("Failed to process overdue tasks",
+			zap.Error(err),
+			zap.String("policy", string(policy)),
+		)
+		return nil, fmt.Errorf("failed to process overdue tasks: %w", err)
+	}
+
+	return tasks, nil
+}
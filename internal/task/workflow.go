@@ -0,0 +1,98 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+	"task-manager/internal/task/entity"
+)
+
+// Action - способ, которым выполняется переход между статусами
+type Action string
+
+const (
+	// ActionUpdate - обычное обновление задачи через UpdateTask
+	ActionUpdate Action = "update"
+	// ActionReopen - явное переоткрытие выполненной задачи
+	ActionReopen Action = "reopen"
+)
+
+// Transition - разрешенный переход From -> To для действия Action
+type Transition struct {
+	From   entity.Status
+	To     entity.Status
+	Action Action
+}
+
+// ParseTransitions разбирает таблицу переходов из конфигурации: список через запятую
+// вида from->to, действие по умолчанию update, явное задается суффиксом :reopen,
+// например "pending->done,done->pending:reopen"
+func ParseTransitions(spec string) ([]Transition, error) {
+	var transitions []Transition
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		pair, action, found := strings.Cut(item, ":")
+		if !found {
+			action = string(ActionUpdate)
+		}
+		from, to, ok := strings.Cut(pair, "->")
+		if !ok {
+			return nil, fmt.Errorf("invalid transition %q, expected from->to[:action]", item)
+		}
+
+		t := Transition{
+			From:   entity.Status(strings.TrimSpace(from)),
+			To:     entity.Status(strings.TrimSpace(to)),
+			Action: Action(strings.TrimSpace(action)),
+		}
+		if !t.From.Valid() || !t.To.Valid() {
+			return nil, fmt.Errorf("invalid transition %q: unknown status", item)
+		}
+		if t.Action != ActionUpdate && t.Action != ActionReopen {
+			return nil, fmt.Errorf("invalid transition %q: unknown action %q", item, t.Action)
+		}
+		transitions = append(transitions, t)
+	}
+
+	if len(transitions) == 0 {
+		return nil, fmt.Errorf("workflow has no transitions")
+	}
+	return transitions, nil
+}
+
+// Workflow - конечный автомат статусов задачи
+type Workflow struct {
+	statuses    map[entity.Status]struct{}
+	transitions map[Transition]struct{}
+}
+
+func NewWorkflow(transitions []Transition) *Workflow {
+	w := &Workflow{
+		statuses:    map[entity.Status]struct{}{},
+		transitions: map[Transition]struct{}{},
+	}
+	for _, t := range transitions {
+		w.statuses[t.From] = struct{}{}
+		w.statuses[t.To] = struct{}{}
+		w.transitions[t] = struct{}{}
+	}
+	return w
+}
+
+// Check проверяет переход from -> to для действия action. Обновление в тот же статус
+// допустимо и ничего не меняет, переоткрыть можно только по явному переходу.
+func (w *Workflow) Check(from, to entity.Status, action Action) error {
+	if _, ok := w.statuses[to]; !ok {
+		return &ValidationError{Field: "status", Reason: "unknown status " + string(to)}
+	}
+	if from == to && action == ActionUpdate {
+		return nil
+	}
+	if _, ok := w.transitions[Transition{From: from, To: to, Action: action}]; !ok {
+		return &TransitionError{From: from, To: to, Action: action}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package task
+
+import (
+	"errors"
+	"task-manager/internal/task/entity"
+	"testing"
+)
+
+func TestParseTransitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    []Transition
+		wantErr bool
+	}{
+		{
+			name: "default action is update",
+			spec: "pending->done",
+			want: []Transition{{From: entity.StatusPending, To: entity.StatusDone, Action: ActionUpdate}},
+		},
+		{
+			name: "explicit reopen and spaces",
+			spec: " done -> pending : reopen ,",
+			want: []Transition{{From: entity.StatusDone, To: entity.StatusPending, Action: ActionReopen}},
+		},
+		{
+			name: "blocked and cancelled statuses",
+			spec: "in_progress->blocked,blocked->in_progress,pending->cancelled",
+			want: []Transition{
+				{From: entity.StatusInProgress, To: entity.StatusBlocked, Action: ActionUpdate},
+				{From: entity.StatusBlocked, To: entity.StatusInProgress, Action: ActionUpdate},
+				{From: entity.StatusPending, To: entity.StatusCancelled, Action: ActionUpdate},
+			},
+		},
+		{name: "unknown status", spec: "pending->archived", wantErr: true},
+		{name: "unknown action", spec: "pending->done:skip", wantErr: true},
+		{name: "missing arrow", spec: "pending", wantErr: true},
+		{name: "empty", spec: " , ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTransitions(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseTransitions(%q) = %v, want error", tt.spec, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTransitions(%q): %v", tt.spec, err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseTransitions(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("transition %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWorkflowCheck(t *testing.T) {
+	transitions, err := ParseTransitions("pending->in_progress,in_progress->blocked,blocked->in_progress")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewWorkflow(transitions)
+
+	if err := w.Check(entity.StatusInProgress, entity.StatusBlocked, ActionUpdate); err != nil {
+		t.Errorf("in_progress->blocked: %v", err)
+	}
+
+	var transitionErr *TransitionError
+	if err := w.Check(entity.StatusPending, entity.StatusBlocked, ActionUpdate); !errors.As(err, &transitionErr) {
+		t.Errorf("pending->blocked = %v, want TransitionError", err)
+	}
+
+	// Статус, которого нет в таблице переходов, недоступен, даже если он известен
+	var validationErr *ValidationError
+	if err := w.Check(entity.StatusPending, entity.StatusCancelled, ActionUpdate); !errors.As(err, &validationErr) {
+		t.Errorf("pending->cancelled = %v, want ValidationError", err)
+	}
+}
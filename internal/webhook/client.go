@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress - адрес получателя ведет во внутреннюю сеть
+var ErrForbiddenAddress = errors.New("destination address is not allowed")
+
+// nonPublicPrefixes - специальные диапазоны, которые не покрываются методами netip.Addr,
+// но тоже ведут не в интернет
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "эта" сеть
+	netip.MustParsePrefix("100.64.0.0/10"),  // CGNAT, часто внутренняя сеть облака
+	netip.MustParsePrefix("192.0.0.0/24"),   // назначения протоколов IETF
+	netip.MustParsePrefix("198.18.0.0/15"),  // тестирование производительности сетей
+	netip.MustParsePrefix("240.0.0.0/4"),    // зарезервировано, включая broadcast
+	netip.MustParsePrefix("64:ff9b:1::/48"), // локальный NAT64
+}
+
+// nat64Prefix - известный префикс NAT64: в последних 32 битах адреса лежит IPv4,
+// к которому NAT64-шлюз откроет соединение
+var nat64Prefix = netip.MustParsePrefix("64:ff9b::/96")
+
+// PublicAddr сообщает, можно ли отправлять вебхук на адрес addr. Запрещены loopback,
+// частные, link-local, multicast, неуказанные адреса и специальные диапазоны из
+// nonPublicPrefixes, а адрес NAT64 проверяется по вложенному IPv4: иначе вебхук
+// превращается в способ достучаться до внутренних сервисов и метаданных облака (SSRF).
+func PublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if nat64Prefix.Contains(addr) {
+		ip := addr.As16()
+		return PublicAddr(netip.AddrFrom4([4]byte(ip[12:])))
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified()
+}
+
+// NewClient создает HTTP-клиент для доставки вебхуков. Адрес проверяется при каждом
+// соединении уже после разрешения имени, поэтому DNS, сменивший ответ после регистрации
+// вебхука, не откроет доступ во внутреннюю сеть. Редиректы не выполняются: ответ 3xx
+// считается неудачной попыткой. Прокси из окружения не используется - через него
+// проверка адреса не работает.
+func NewClient(timeout time.Duration) *http.Client {
+	return newClient(timeout, PublicAddr)
+}
+
+func newClient(timeout time.Duration, allowed func(netip.Addr) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, address)
+			}
+			if !allowed(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, addrPort.Addr())
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
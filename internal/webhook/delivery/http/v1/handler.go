@@ -0,0 +1,157 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"task-manager/internal/webhook"
+	"task-manager/internal/webhook/dtos"
+	"task-manager/pkg/response"
+)
+
+type WebhookHandler struct {
+	uc  webhook.WebhookUseCase
+	log *zap.Logger
+}
+
+func NewWebhookHandler(uc webhook.WebhookUseCase, log *zap.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		uc:  uc,
+		log: log.Named("webhook_handler"),
+	}
+}
+
+// CreateWebhook регистрирует вебхук. Секрет подписи возвращается только в этом ответе.
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req dtos.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid webhook request", zap.Error(err))
+		c.Error(response.InvalidBody(err))
+		return
+	}
+
+	created, err := h.uc.CreateWebhook(c.Request.Context(), &req)
+	if err != nil {
+		h.log.Error("Failed to create webhook", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// ListWebhooks возвращает вебхуки текущего пользователя
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	webhooks, err := h.uc.ListWebhooks(c.Request.Context())
+	if err != nil {
+		h.log.Error("Failed to list webhooks", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+// GetWebhook возвращает вебхук по ID
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	id, ok := pathID(c, "id", webhook.ErrNotFound)
+	if !ok {
+		return
+	}
+
+	w, err := h.uc.GetWebhook(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, w)
+}
+
+// UpdateWebhook меняет адрес, набор событий или включает/выключает вебхук
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, ok := pathID(c, "id", webhook.ErrNotFound)
+	if !ok {
+		return
+	}
+
+	var req dtos.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn("Invalid webhook request", zap.Error(err))
+		c.Error(response.InvalidBody(err))
+		return
+	}
+
+	w, err := h.uc.UpdateWebhook(c.Request.Context(), id, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, w)
+}
+
+// DeleteWebhook удаляет вебхук вместе с журналом доставок
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, ok := pathID(c, "id", webhook.ErrNotFound)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.DeleteWebhook(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries возвращает журнал доставок вебхука, новые первыми: limit, offset
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	id, ok := pathID(c, "id", webhook.ErrNotFound)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	deliveries, err := h.uc.ListDeliveries(c.Request.Context(), id, limit, offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// ReplayDelivery повторно отправляет доставку
+func (h *WebhookHandler) ReplayDelivery(c *gin.Context) {
+	id, ok := pathID(c, "id", webhook.ErrNotFound)
+	if !ok {
+		return
+	}
+	deliveryID, ok := pathID(c, "deliveryId", webhook.ErrDeliveryNotFound)
+	if !ok {
+		return
+	}
+
+	replay, err := h.uc.ReplayDelivery(c.Request.Context(), id, deliveryID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, replay)
+}
+
+// pathID разбирает числовой ID из пути. Некорректный ID неотличим от несуществующего.
+func pathID(c *gin.Context, name string, notFound error) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(name), 10, 64)
+	if err != nil {
+		c.Error(notFound)
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,18 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func (h *WebhookHandler) WebhookRoutes(router *gin.RouterGroup, auth gin.HandlerFunc) {
+	webhookGroup := router.Group("/webhooks").Use(auth)
+	{
+		webhookGroup.POST("", h.CreateWebhook)
+		webhookGroup.GET("", h.ListWebhooks)
+		webhookGroup.GET("/:id", h.GetWebhook)
+		webhookGroup.PUT("/:id", h.UpdateWebhook)
+		webhookGroup.DELETE("/:id", h.DeleteWebhook)
+		webhookGroup.GET("/:id/deliveries", h.ListDeliveries)
+		webhookGroup.POST("/:id/deliveries/:deliveryId/replay", h.ReplayDelivery)
+	}
+}
@@ -0,0 +1,21 @@
+package dtos
+
+import "task-manager/internal/webhook/entity"
+
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" validate:"required,url,max=500"`
+	Secret string   `json:"secret,omitempty" validate:"omitempty,min=16,max=200"`
+	Events []string `json:"events" validate:"required,min=1,unique"`
+}
+
+type UpdateWebhookRequest struct {
+	URL    *string  `json:"url,omitempty" validate:"omitempty,url,max=500"`
+	Events []string `json:"events,omitempty" validate:"omitempty,min=1,unique"`
+	Active *bool    `json:"active,omitempty"`
+}
+
+// CreatedWebhook - ответ на создание: секрет показывается только один раз
+type CreatedWebhook struct {
+	*entity.Webhook
+	Secret string `json:"secret"`
+}
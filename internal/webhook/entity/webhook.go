@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+// Webhook - адрес, на который отправляются события задач пользователя
+type Webhook struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"-"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Subscribed сообщает, подписан ли вебхук на событие
+func (w *Webhook) Subscribed(eventType string) bool {
+	for _, e := range w.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type DeliveryStatus string
+
+const (
+	// DeliveryPending - ждет первой или повторной попытки
+	DeliveryPending DeliveryStatus = "pending"
+	// DeliverySucceeded - получатель ответил 2xx
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	// DeliveryFailed - попытки исчерпаны
+	DeliveryFailed DeliveryStatus = "failed"
+)
+
+// Delivery - отправка одного события на один вебхук
+type Delivery struct {
+	ID            int64           `json:"id"`
+	WebhookID     int64           `json:"webhook_id"`
+	EventID       uuid.UUID       `json:"event_id"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        DeliveryStatus  `json:"status"`
+	Attempts      int             `json:"attempts"`
+	ResponseCode  *int            `json:"response_code,omitempty"`
+	LastError     *string         `json:"last_error,omitempty"`
+	NextAttemptAt *time.Time      `json:"next_attempt_at,omitempty"`
+	DeliveredAt   *time.Time      `json:"delivered_at,omitempty"`
+	ReplayOf      *int64          `json:"replay_of,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// DueDelivery - доставка вместе с адресом и ключом подписи вебхука
+type DueDelivery struct {
+	Delivery
+	URL    string
+	Secret string
+}
@@ -0,0 +1,16 @@
+package webhook
+
+import "task-manager/pkg/apperror"
+
+var (
+	// ErrNotFound - вебхук не найден или принадлежит другому пользователю
+	ErrNotFound = apperror.New(apperror.ErrNotFound, "webhook not found")
+	// ErrDeliveryNotFound - доставка не найдена у вебхука
+	ErrDeliveryNotFound = apperror.New(apperror.ErrNotFound, "webhook delivery not found")
+)
+
+// InvalidField - ошибка валидации поля запроса, которую не выразить тегами validate
+func InvalidField(field, message string) error {
+	return apperror.New(apperror.ErrValidation, "validation failed").
+		WithFields(apperror.FieldError{Field: field, Message: message})
+}
@@ -0,0 +1,21 @@
+package webhook
+
+import (
+	"context"
+	"task-manager/internal/webhook/entity"
+	"time"
+)
+
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook *entity.Webhook) error
+	GetWebhook(ctx context.Context, id int64) (*entity.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]entity.Webhook, error)
+	UpdateWebhook(ctx context.Context, webhook *entity.Webhook) error
+	DeleteWebhook(ctx context.Context, id int64) error
+	Subscribers(ctx context.Context, userID int64, eventType string) ([]entity.Webhook, error)
+	CreateDeliveries(ctx context.Context, deliveries []*entity.Delivery) error
+	ListDeliveries(ctx context.Context, webhookID int64, limit, offset int) ([]entity.Delivery, error)
+	GetDelivery(ctx context.Context, webhookID, id int64) (*entity.Delivery, error)
+	ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*entity.DueDelivery, error)
+	RecordAttempt(ctx context.Context, delivery *entity.Delivery) error
+}
@@ -0,0 +1,366 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"task-manager/internal/webhook"
+	"task-manager/internal/webhook/entity"
+	"task-manager/pkg/identity"
+	"time"
+)
+
+const webhookColumns = "id, user_id, url, secret, events, active, created_at, updated_at"
+
+const deliveryColumns = "id, webhook_id, event_id, event_type, payload, status, attempts, response_code, " +
+	"last_error, next_attempt_at, delivered_at, replay_of, created_at, updated_at"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWebhook(row rowScanner, w *entity.Webhook) error {
+	return row.Scan(
+		&w.ID,
+		&w.UserID,
+		&w.URL,
+		&w.Secret,
+		pq.Array(&w.Events),
+		&w.Active,
+		&w.CreatedAt,
+		&w.UpdatedAt,
+	)
+}
+
+func scanDelivery(row rowScanner, d *entity.Delivery, extra ...interface{}) error {
+	return row.Scan(append([]interface{}{
+		&d.ID,
+		&d.WebhookID,
+		&d.EventID,
+		&d.EventType,
+		(*[]byte)(&d.Payload), // через *[]byte database/sql копирует буфер драйвера
+		&d.Status,
+		&d.Attempts,
+		&d.ResponseCode,
+		&d.LastError,
+		&d.NextAttemptAt,
+		&d.DeliveredAt,
+		&d.ReplayOf,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+	}, extra...)...)
+}
+
+type Repository struct {
+	db  *sql.DB
+	log *zap.Logger
+}
+
+func NewRepository(db *sql.DB, log *zap.Logger) webhook.WebhookRepository {
+	return &Repository{
+		db:  db,
+		log: log.Named("webhook_repository"),
+	}
+}
+
+func (r *Repository) CreateWebhook(ctx context.Context, w *entity.Webhook) error {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	w.UserID = userID
+	query := `
+		INSERT INTO webhooks (user_id, url, secret, events, active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at`
+
+	err = r.db.QueryRowContext(ctx, query, w.UserID, w.URL, w.Secret, pq.Array(w.Events), w.Active).
+		Scan(&w.ID, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		r.log.Error("Failed to create webhook",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+		)
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	r.log.Info("Webhook created",
+		zap.Int64("webhook_id", w.ID),
+		zap.Int64("user_id", userID),
+	)
+	return nil
+}
+
+func (r *Repository) GetWebhook(ctx context.Context, id int64) (*entity.Webhook, error) {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var w entity.Webhook
+	query := "SELECT " + webhookColumns + " FROM webhooks WHERE id = $1 AND user_id = $2"
+
+	err = scanWebhook(r.db.QueryRowContext(ctx, query, id, userID), &w)
+	if err == sql.ErrNoRows {
+		return nil, webhook.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	return &w, nil
+}
+
+func (r *Repository) ListWebhooks(ctx context.Context) ([]entity.Webhook, error) {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + webhookColumns + " FROM webhooks WHERE user_id = $1 ORDER BY id"
+	return r.queryWebhooks(ctx, query, userID)
+}
+
+func (r *Repository) UpdateWebhook(ctx context.Context, w *entity.Webhook) error {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE webhooks SET url = $1, events = $2, active = $3, updated_at = NOW()
+		WHERE id = $4 AND user_id = $5
+		RETURNING updated_at`
+
+	err = r.db.QueryRowContext(ctx, query, w.URL, pq.Array(w.Events), w.Active, w.ID, userID).Scan(&w.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return webhook.ErrNotFound
+	}
+	if err != nil {
+		r.log.Error("Failed to update webhook",
+			zap.Error(err),
+			zap.Int64("webhook_id", w.ID),
+		)
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteWebhook(ctx context.Context, id int64) error {
+	userID, err := identity.RequireUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM webhooks WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		r.log.Error("Failed to delete webhook",
+			zap.Error(err),
+			zap.Int64("webhook_id", id),
+		)
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return webhook.ErrNotFound
+	}
+	return nil
+}
+
+// Subscribers возвращает активные вебхуки пользователя, подписанные на событие.
+// Пользователь берется из события, а не из контекста: события публикует и воркер.
+func (r *Repository) Subscribers(ctx context.Context, userID int64, eventType string) ([]entity.Webhook, error) {
+	query := "SELECT " + webhookColumns + " FROM webhooks WHERE user_id = $1 AND active AND $2 = ANY(events)"
+	return r.queryWebhooks(ctx, query, userID, eventType)
+}
+
+func (r *Repository) queryWebhooks(ctx context.Context, query string, args ...interface{}) ([]entity.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.log.Error("Failed to list webhooks", zap.Error(err))
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []entity.Webhook{}
+	for rows.Next() {
+		var w entity.Webhook
+		if err := scanWebhook(rows, &w); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, w)
+	}
+	return webhooks, rows.Err()
+}
+
+// CreateDeliveries ставит доставки в очередь одной транзакцией. Доставка события
+// на вебхук создается один раз: повторная для того же события пропускается, ID у нее
+// остается нулевым. Повторы (replay) не ограничиваются.
+func (r *Repository) CreateDeliveries(ctx context.Context, deliveries []*entity.Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, status, next_attempt_at, replay_of)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (webhook_id, event_id) WHERE replay_of IS NULL DO NOTHING
+		RETURNING id, created_at, updated_at`
+
+	for _, d := range deliveries {
+		err := tx.QueryRowContext(ctx, query,
+			d.WebhookID,
+			d.EventID,
+			d.EventType,
+			[]byte(d.Payload),
+			d.Status,
+			d.NextAttemptAt,
+			d.ReplayOf,
+		).Scan(&d.ID, &d.CreatedAt, &d.UpdatedAt)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			r.log.Error("Failed to create webhook delivery",
+				zap.Error(err),
+				zap.Int64("webhook_id", d.WebhookID),
+			)
+			return fmt.Errorf("failed to create webhook delivery: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) ListDeliveries(ctx context.Context, webhookID int64, limit, offset int) ([]entity.Delivery, error) {
+	if _, err := r.GetWebhook(ctx, webhookID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY id DESC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.QueryContext(ctx, query, webhookID, limit, offset)
+	if err != nil {
+		r.log.Error("Failed to list webhook deliveries",
+			zap.Error(err),
+			zap.Int64("webhook_id", webhookID),
+		)
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []entity.Delivery{}
+	for rows.Next() {
+		var d entity.Delivery
+		if err := scanDelivery(rows, &d); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r *Repository) GetDelivery(ctx context.Context, webhookID, id int64) (*entity.Delivery, error) {
+	if _, err := r.GetWebhook(ctx, webhookID); err != nil {
+		return nil, err
+	}
+
+	var d entity.Delivery
+	query := "SELECT " + deliveryColumns + " FROM webhook_deliveries WHERE id = $1 AND webhook_id = $2"
+
+	err := scanDelivery(r.db.QueryRowContext(ctx, query, id, webhookID), &d)
+	if err == sql.ErrNoRows {
+		return nil, webhook.ErrDeliveryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	return &d, nil
+}
+
+// ClaimDue забирает доставки, время попытки которых наступило, и сдвигает попытку
+// на lease вперед. SKIP LOCKED позволяет нескольким воркерам разбирать очередь без лока;
+// если воркер упал посреди отправки, доставка вернется в очередь по окончании аренды.
+func (r *Repository) ClaimDue(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+	lease time.Duration,
+) ([]*entity.DueDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = $2, updated_at = NOW()
+		FROM webhooks w
+		WHERE w.id = d.webhook_id AND d.id IN (
+			SELECT dd.id
+			FROM webhook_deliveries dd
+			JOIN webhooks ww ON ww.id = dd.webhook_id
+			WHERE dd.status = $3 AND dd.next_attempt_at <= $1 AND ww.active
+			ORDER BY dd.next_attempt_at, dd.id
+			LIMIT $4
+			FOR UPDATE OF dd SKIP LOCKED
+		)
+		RETURNING d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+			d.response_code, d.last_error, d.next_attempt_at, d.delivered_at, d.replay_of,
+			d.created_at, d.updated_at, w.url, w.secret`
+
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), entity.DeliveryPending, limit)
+	if err != nil {
+		r.log.Error("Failed to claim webhook deliveries", zap.Error(err))
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var due []*entity.DueDelivery
+	for rows.Next() {
+		var d entity.DueDelivery
+		if err := scanDelivery(rows, &d.Delivery, &d.URL, &d.Secret); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		due = append(due, &d)
+	}
+	return due, rows.Err()
+}
+
+// RecordAttempt сохраняет результат попытки доставки
+func (r *Repository) RecordAttempt(ctx context.Context, d *entity.Delivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, response_code = $3, last_error = $4,
+			next_attempt_at = $5, delivered_at = $6, updated_at = NOW()
+		WHERE id = $7
+		RETURNING updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		d.Status,
+		d.Attempts,
+		d.ResponseCode,
+		d.LastError,
+		d.NextAttemptAt,
+		d.DeliveredAt,
+		d.ID,
+	).Scan(&d.UpdatedAt)
+	if err != nil && err != sql.ErrNoRows {
+		r.log.Error("Failed to record webhook delivery attempt",
+			zap.Error(err),
+			zap.Int64("delivery_id", d.ID),
+		)
+		return fmt.Errorf("failed to record webhook delivery attempt: %w", err)
+	}
+	return nil
+}
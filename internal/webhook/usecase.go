@@ -0,0 +1,21 @@
+package webhook
+
+import (
+	"context"
+	"task-manager/internal/task"
+	"task-manager/internal/webhook/dtos"
+	"task-manager/internal/webhook/entity"
+	"time"
+)
+
+type WebhookUseCase interface {
+	task.EventHandler
+	CreateWebhook(ctx context.Context, req *dtos.CreateWebhookRequest) (*dtos.CreatedWebhook, error)
+	GetWebhook(ctx context.Context, id int64) (*entity.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]entity.Webhook, error)
+	UpdateWebhook(ctx context.Context, id int64, req *dtos.UpdateWebhookRequest) (*entity.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	ListDeliveries(ctx context.Context, webhookID int64, limit, offset int) ([]entity.Delivery, error)
+	ReplayDelivery(ctx context.Context, webhookID, deliveryID int64) (*entity.Delivery, error)
+	DispatchDue(ctx context.Context, now time.Time) (int, error)
+}
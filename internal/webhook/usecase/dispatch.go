@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"task-manager/internal/webhook"
+	"task-manager/internal/webhook/entity"
+	"time"
+)
+
+const (
+	// dispatchBatch - сколько доставок воркер забирает за один запуск
+	dispatchBatch = 50
+	// dispatchLease - через сколько доставка упавшего воркера вернется в очередь
+	dispatchLease = 15 * time.Minute
+	// maxAttempts - после стольких неудачных попыток доставка считается проваленной
+	maxAttempts = 8
+	// baseBackoff и maxBackoff - задержка перед повтором: baseBackoff * 2^(попытка-1), не больше maxBackoff
+	baseBackoff = 30 * time.Second
+	maxBackoff  = time.Hour
+)
+
+// DispatchDue отправляет доставки, время попытки которых наступило. Ответ 2xx - успех,
+// иначе попытка повторяется с экспоненциальной задержкой до maxAttempts.
+func (uc *webhookUseCase) DispatchDue(ctx context.Context, now time.Time) (int, error) {
+	due, err := uc.repo.ClaimDue(ctx, now, dispatchBatch, dispatchLease)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, d := range due {
+		if ctx.Err() != nil {
+			// Остальные доставки вернутся в очередь по окончании аренды
+			return delivered, ctx.Err()
+		}
+
+		code, sendErr := uc.send(ctx, d)
+		d.Attempts++
+		d.ResponseCode = code
+
+		finished := time.Now()
+		switch {
+		case sendErr == nil:
+			d.Status = entity.DeliverySucceeded
+			d.LastError = nil
+			d.NextAttemptAt = nil
+			d.DeliveredAt = &finished
+			delivered++
+		case d.Attempts >= maxAttempts:
+			message := attemptError(code, sendErr)
+			d.Status = entity.DeliveryFailed
+			d.LastError = &message
+			d.NextAttemptAt = nil
+		default:
+			message := attemptError(code, sendErr)
+			next := finished.Add(backoff(d.Attempts))
+			d.LastError = &message
+			d.NextAttemptAt = &next
+		}
+
+		if err := uc.repo.RecordAttempt(ctx, &d.Delivery); err != nil {
+			return delivered, err
+		}
+
+		uc.log.Debug("Webhook delivery attempted",
+			zap.Int64("delivery_id", d.ID),
+			zap.Int64("webhook_id", d.WebhookID),
+			zap.String("status", string(d.Status)),
+			zap.Int("attempts", d.Attempts),
+			zap.Error(sendErr),
+		)
+	}
+	return delivered, nil
+}
+
+// send выполняет одну попытку и возвращает код ответа, если он был получен
+func (uc *webhookUseCase) send(ctx context.Context, d *entity.DueDelivery) (*int, error) {
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "task-manager-webhooks/1.0")
+	req.Header.Set(webhook.EventHeader, d.EventType)
+	req.Header.Set(webhook.DeliveryHeader, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(webhook.TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(d.Secret, timestamp, d.Payload))
+
+	resp, err := uc.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	code := resp.StatusCode
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if code < 200 || code >= 300 {
+		return &code, fmt.Errorf("unexpected status %d", code)
+	}
+	return &code, nil
+}
+
+// attemptError - причина неудачной попытки для журнала доставок, который видит владелец
+// вебхука. Текст сетевой ошибки и тело ответа не сохраняются: через них можно узнать
+// об устройстве внутренней сети.
+func attemptError(code *int, err error) string {
+	var (
+		dnsErr *net.DNSError
+		netErr net.Error
+	)
+	switch {
+	case code != nil:
+		return fmt.Sprintf("unexpected status %d", *code)
+	case errors.Is(err, webhook.ErrForbiddenAddress):
+		return "destination address is not allowed"
+	case errors.As(err, &dnsErr):
+		return "host cannot be resolved"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "request timed out"
+	default:
+		return "connection failed"
+	}
+}
+
+// backoff - задержка перед попыткой номер attempt+1
+func backoff(attempt int) time.Duration {
+	delay := baseBackoff << (attempt - 1)
+	if delay <= 0 || delay > maxBackoff {
+		return maxBackoff
+	}
+	return delay
+}
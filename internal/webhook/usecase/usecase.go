@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go.uber.org/zap"
+	"net"
+	"net/http"
+	"net/url"
+	"task-manager/internal/task"
+	"task-manager/internal/webhook"
+	"task-manager/internal/webhook/dtos"
+	"task-manager/internal/webhook/entity"
+	"time"
+)
+
+type webhookUseCase struct {
+	repo   webhook.WebhookRepository
+	client *http.Client
+	log    *zap.Logger
+}
+
+func NewWebhookUseCase(repo webhook.WebhookRepository, client *http.Client, log *zap.Logger) webhook.WebhookUseCase {
+	return &webhookUseCase{
+		repo:   repo,
+		client: client,
+		log:    log.Named("webhook_usecase"),
+	}
+}
+
+func (uc *webhookUseCase) CreateWebhook(ctx context.Context, req *dtos.CreateWebhookRequest) (*dtos.CreatedWebhook, error) {
+	if err := checkURL(ctx, req.URL); err != nil {
+		return nil, err
+	}
+	if err := checkEvents(req.Events); err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		generated, err := generateSecret()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+
+	w := &entity.Webhook{
+		URL:    req.URL,
+		Secret: secret,
+		Events: req.Events,
+		Active: true,
+	}
+	if err := uc.repo.CreateWebhook(ctx, w); err != nil {
+		return nil, err
+	}
+	return &dtos.CreatedWebhook{Webhook: w, Secret: secret}, nil
+}
+
+func (uc *webhookUseCase) GetWebhook(ctx context.Context, id int64) (*entity.Webhook, error) {
+	return uc.repo.GetWebhook(ctx, id)
+}
+
+func (uc *webhookUseCase) ListWebhooks(ctx context.Context) ([]entity.Webhook, error) {
+	return uc.repo.ListWebhooks(ctx)
+}
+
+func (uc *webhookUseCase) UpdateWebhook(
+	ctx context.Context,
+	id int64,
+	req *dtos.UpdateWebhookRequest,
+) (*entity.Webhook, error) {
+	w, err := uc.repo.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if err := checkURL(ctx, *req.URL); err != nil {
+			return nil, err
+		}
+		w.URL = *req.URL
+	}
+	if req.Events != nil {
+		if err := checkEvents(req.Events); err != nil {
+			return nil, err
+		}
+		w.Events = req.Events
+	}
+	if req.Active != nil {
+		w.Active = *req.Active
+	}
+
+	if err := uc.repo.UpdateWebhook(ctx, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (uc *webhookUseCase) DeleteWebhook(ctx context.Context, id int64) error {
+	return uc.repo.DeleteWebhook(ctx, id)
+}
+
+func (uc *webhookUseCase) ListDeliveries(ctx context.Context, webhookID int64, limit, offset int) ([]entity.Delivery, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return uc.repo.ListDeliveries(ctx, webhookID, limit, offset)
+}
+
+// ReplayDelivery ставит в очередь новую доставку с тем же телом и ID события,
+// чтобы получатель мог распознать повтор
+func (uc *webhookUseCase) ReplayDelivery(ctx context.Context, webhookID, deliveryID int64) (*entity.Delivery, error) {
+	original, err := uc.repo.GetDelivery(ctx, webhookID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	replay := &entity.Delivery{
+		WebhookID:     original.WebhookID,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        entity.DeliveryPending,
+		NextAttemptAt: &now,
+		ReplayOf:      &original.ID,
+	}
+	if err := uc.repo.CreateDeliveries(ctx, []*entity.Delivery{replay}); err != nil {
+		return nil, err
+	}
+
+	uc.log.Info("Webhook delivery replayed",
+		zap.Int64("webhook_id", webhookID),
+		zap.Int64("delivery_id", deliveryID),
+		zap.Int64("replay_id", replay.ID),
+	)
+	return replay, nil
+}
+
+// Handle ставит событие в очередь доставки всем подписанным вебхукам владельца задачи.
+// При ошибке событие придет из стрима outbox повторно; доставка на вебхук создается
+// один раз на событие, поэтому повтор не размножает отправки.
+func (uc *webhookUseCase) Handle(ctx context.Context, event task.Event) error {
+	hooks, err := uc.repo.Subscribers(ctx, event.UserID, string(event.Type))
+	if err != nil {
+		uc.log.Error("Failed to find webhook subscribers",
+			zap.Error(err),
+			zap.String("event_id", event.ID.String()),
+		)
+		return err
+	}
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		uc.log.Error("Failed to marshal task event",
+			zap.Error(err),
+			zap.String("event_id", event.ID.String()),
+		)
+		return nil
+	}
+
+	deliveries := make([]*entity.Delivery, 0, len(hooks))
+	for _, hook := range hooks {
+		deliveries = append(deliveries, &entity.Delivery{
+			WebhookID:     hook.ID,
+			EventID:       event.ID,
+			EventType:     string(event.Type),
+			Payload:       payload,
+			Status:        entity.DeliveryPending,
+			NextAttemptAt: &event.OccurredAt,
+		})
+	}
+
+	if err := uc.repo.CreateDeliveries(ctx, deliveries); err != nil {
+		uc.log.Error("Failed to enqueue webhook deliveries",
+			zap.Error(err),
+			zap.String("event_id", event.ID.String()),
+			zap.String("event_type", string(event.Type)),
+		)
+		return err
+	}
+
+	uc.log.Debug("Webhook deliveries enqueued",
+		zap.String("event_id", event.ID.String()),
+		zap.String("event_type", string(event.Type)),
+		zap.Int("webhooks", len(deliveries)),
+	)
+	return nil
+}
+
+// lookupHost разрешает имя хоста вебхука
+var lookupHost = net.DefaultResolver.LookupNetIP
+
+// checkURL отклоняет URL, ведущие во внутреннюю сеть. Это первая линия защиты от SSRF:
+// DNS может начать отвечать иначе, поэтому адрес еще раз проверяется при отправке.
+func checkURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return webhook.InvalidField("url", "must be an absolute http or https URL")
+	}
+
+	addrs, err := lookupHost(ctx, "ip", u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return webhook.InvalidField("url", "host cannot be resolved")
+	}
+	for _, addr := range addrs {
+		if !webhook.PublicAddr(addr) {
+			return webhook.InvalidField("url", "must not point to a private, loopback or link-local address")
+		}
+	}
+	return nil
+}
+
+func checkEvents(events []string) error {
+	for _, e := range events {
+		if !task.EventType(e).Valid() {
+			return webhook.InvalidField("events", fmt.Sprintf("unknown event %q", e))
+		}
+	}
+	return nil
+}
+
+func generateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"task-manager/internal/webhook"
+	"testing"
+)
+
+func TestCheckURL(t *testing.T) {
+	hosts := map[string][]netip.Addr{
+		"hooks.example.com":    {netip.MustParseAddr("93.184.216.34")},
+		"internal.example.com": {netip.MustParseAddr("10.0.0.5")},
+		"mixed.example.com":    {netip.MustParseAddr("93.184.216.34"), netip.MustParseAddr("127.0.0.1")},
+	}
+	lookup := lookupHost
+	lookupHost = func(_ context.Context, _, host string) ([]netip.Addr, error) {
+		if addr, err := netip.ParseAddr(host); err == nil {
+			return []netip.Addr{addr}, nil
+		}
+		if addrs, ok := hosts[host]; ok {
+			return addrs, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	defer func() { lookupHost = lookup }()
+
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{url: "https://hooks.example.com/task", wantErr: false},
+		{url: "http://93.184.216.34:8080/hook", wantErr: false},
+		{url: "ftp://hooks.example.com/task", wantErr: true},
+		{url: "/relative", wantErr: true},
+		{url: "http://localhost.invalid/", wantErr: true},
+		{url: "http://127.0.0.1/", wantErr: true},
+		{url: "http://[::1]:9000/", wantErr: true},
+		{url: "http://169.254.169.254/latest/meta-data/", wantErr: true},
+		{url: "https://internal.example.com/", wantErr: true},
+		{url: "https://mixed.example.com/", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := checkURL(context.Background(), tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkURL(%q) = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// timeoutError - сетевая ошибка с истекшим таймаутом
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout on 10.1.2.3:443" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestAttemptError(t *testing.T) {
+	status := 502
+
+	tests := []struct {
+		name string
+		code *int
+		err  error
+		want string
+	}{
+		{name: "status only", code: &status, err: errors.New("unexpected status 502"), want: "unexpected status 502"},
+		{name: "forbidden address", err: fmt.Errorf("dial: %w: 10.0.0.1", webhook.ErrForbiddenAddress), want: "destination address is not allowed"},
+		{name: "dns", err: &net.DNSError{Err: "no such host", Name: "db.internal"}, want: "host cannot be resolved"},
+		{name: "timeout", err: fmt.Errorf("post: %w", timeoutError{}), want: "request timed out"},
+		{name: "deadline", err: context.DeadlineExceeded, want: "request timed out"},
+		{name: "other", err: errors.New("dial tcp 10.1.2.3:5432: connection refused"), want: "connection failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := attemptError(tt.code, tt.err); got != tt.want {
+				t.Errorf("attemptError = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
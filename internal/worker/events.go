@@ -0,0 +1,22 @@
+package worker
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"task-manager/internal/task"
+	"task-manager/internal/task/entity"
+)
+
+// EventsJob передает события задач из стрима outbox обработчику группы потребителей.
+// Токен лока не нужен: обработка at-least-once, и обработчики отбрасывают дубли по event_id.
+func EventsJob(consumer task.EventConsumer, log *zap.Logger) FencedJobFunc {
+	log = log.Named("events_job")
+
+	return func(ctx context.Context, _ entity.Fence) error {
+		handled, err := consumer.Consume(ctx)
+		if handled > 0 {
+			log.Debug("Task events handled", zap.Int("handled", handled))
+		}
+		return err
+	}
+}
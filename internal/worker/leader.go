@@ -0,0 +1,117 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"task-manager/internal/task/entity"
+	"task-manager/pkg/database/redis"
+	"time"
+)
+
+// FencedJobFunc - итерация джобы под локом лидера. fence передается в записи джобы,
+// чтобы они отклонялись, если лок уже перехвачен другой репликой.
+type FencedJobFunc func(ctx context.Context, fence entity.Fence) error
+
+// leader держит распределенный лок джобы: джобу выполняет одна реплика.
+// Лок держится между запусками: аренда на interval+ttl продлевается на каждом тике
+// и снимается только при остановке воркера, поэтому другая реплика перехватит лок,
+// только если лидер упал или не продлил аренду. Пока джоба работает, лок продлевается
+// в фоне; если продлить не удалось, контекст джобы отменяется.
+type leader struct {
+	client *redis.Client
+	name   string
+	lease  time.Duration
+	run    FencedJobFunc
+	lock   *redis.Lock
+	log    *zap.Logger
+}
+
+func newLeader(
+	client *redis.Client,
+	name string,
+	interval, ttl time.Duration,
+	run FencedJobFunc,
+	log *zap.Logger,
+) *leader {
+	return &leader{
+		client: client,
+		name:   name,
+		lease:  interval + ttl,
+		run:    run,
+		log:    log.Named("leader").With(zap.String("lock", name)),
+	}
+}
+
+// Run продлевает аренду или берет лок и выполняет джобу. Вызывается из одной горутины.
+func (l *leader) Run(ctx context.Context) error {
+	if l.lock != nil {
+		err := l.lock.Renew(ctx)
+		if errors.Is(err, redis.ErrLockLost) {
+			l.log.Warn("Lock lease expired, re-acquiring")
+			l.lock = nil
+		} else if err != nil {
+			return err
+		}
+	}
+
+	if l.lock == nil {
+		lock, err := l.client.AcquireLock(ctx, l.name, l.lease)
+		if errors.Is(err, redis.ErrLockNotAcquired) {
+			l.log.Debug("Lock is held by another replica, skipping run")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		l.lock = lock
+		l.log.Info("Lock acquired", zap.Int64("token", lock.Token()))
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	renewDone := make(chan struct{})
+	go func() {
+		defer close(renewDone)
+		renewLoop(jobCtx, l.lock, cancel, l.log)
+	}()
+
+	err := l.run(jobCtx, entity.Fence{Name: l.name, Token: l.lock.Token()})
+	cancel()
+	<-renewDone
+	return err
+}
+
+// Release снимает лок при остановке воркера, чтобы другая реплика не ждала
+// истечения аренды. Вызывается после того, как Run больше не выполняется.
+func (l *leader) Release(ctx context.Context) {
+	if l.lock == nil {
+		return
+	}
+	if err := l.lock.Release(ctx); err != nil && !errors.Is(err, redis.ErrLockLost) {
+		l.log.Warn("Failed to release lock", zap.Error(err))
+	}
+	l.lock = nil
+}
+
+func renewLoop(ctx context.Context, lock *redis.Lock, cancel context.CancelFunc, log *zap.Logger) {
+	ticker := time.NewTicker(lock.TTL() / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := lock.Renew(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Error("Lock lost, cancelling job", zap.Error(err))
+				cancel()
+				return
+			}
+		}
+	}
+}
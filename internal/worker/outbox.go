@@ -0,0 +1,31 @@
+package worker
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"task-manager/internal/task"
+	"task-manager/internal/task/entity"
+	"time"
+)
+
+// OutboxJob публикует события задач из outbox в Redis Stream и удаляет
+// подтвержденные события старше retention
+func OutboxJob(relay task.OutboxRelay, retention time.Duration, log *zap.Logger) FencedJobFunc {
+	log = log.Named("outbox_job")
+
+	return func(ctx context.Context, fence entity.Fence) error {
+		relayed, err := relay.Relay(ctx, fence)
+		if relayed > 0 {
+			log.Info("Outbox events published", zap.Int("published", relayed))
+		}
+		if err != nil {
+			return err
+		}
+
+		deleted, err := relay.Cleanup(ctx, time.Now().Add(-retention))
+		if deleted > 0 {
+			log.Info("Published outbox events cleaned up", zap.Int64("deleted", deleted))
+		}
+		return err
+	}
+}
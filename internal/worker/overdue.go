@@ -0,0 +1,43 @@
+package worker
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"task-manager/internal/task"
+	"task-manager/internal/task/entity"
+	"time"
+)
+
+// OverdueJob применяет политику обработки к просроченным задачам
+func OverdueJob(
+	uc task.TaskUseCase,
+	policy entity.OverduePolicy,
+	gracePeriod time.Duration,
+	log *zap.Logger,
+) FencedJobFunc {
+	log = log.Named("overdue_job")
+
+	return func(ctx context.Context, fence entity.Fence) error {
+		tasks, err := uc.ProcessOverdueTasks(ctx, fence, policy, gracePeriod)
+		if err != nil {
+			return err
+		}
+
+		for _, t := range tasks {
+			log.Info("Overdue task processed",
+				zap.String("policy", string(policy)),
+				zap.String("task_id", t.ID.String()),
+				zap.Int64("user_id", t.UserID),
+				zap.String("status", string(t.Status)),
+				zap.String("priority", string(t.Priority)),
+				zap.Time("due_date", t.DueDate),
+			)
+		}
+
+		log.Info("Overdue tasks run completed",
+			zap.String("policy", string(policy)),
+			zap.Int("processed", len(tasks)),
+		)
+		return nil
+	}
+}
@@ -0,0 +1,32 @@
+package worker
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"task-manager/internal/task"
+	"task-manager/internal/task/entity"
+	"time"
+)
+
+// RecurrenceJob создает следующие вхождения повторяющихся задач, срок которых наступил
+func RecurrenceJob(uc task.TaskUseCase, log *zap.Logger) FencedJobFunc {
+	log = log.Named("recurrence_job")
+
+	return func(ctx context.Context, fence entity.Fence) error {
+		tasks, err := uc.GenerateOccurrences(ctx, fence, time.Now())
+
+		for _, t := range tasks {
+			log.Info("Occurrence generated",
+				zap.String("task_id", t.ID.String()),
+				zap.String("series_id", t.SeriesID.String()),
+				zap.Int64("user_id", t.UserID),
+				zap.Time("due_date", t.DueDate),
+			)
+		}
+
+		log.Info("Recurring tasks run completed",
+			zap.Int("generated", len(tasks)),
+		)
+		return err
+	}
+}
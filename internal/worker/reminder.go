@@ -0,0 +1,21 @@
+package worker
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"task-manager/internal/task"
+	"time"
+)
+
+// ReminderJob отправляет напоминания, время которых наступило
+func ReminderJob(uc task.ReminderUseCase, log *zap.Logger) JobFunc {
+	log = log.Named("reminder_job")
+
+	return func(ctx context.Context) error {
+		sent, err := uc.DeliverDue(ctx, time.Now())
+		if sent > 0 {
+			log.Info("Reminders delivered", zap.Int("sent", sent))
+		}
+		return err
+	}
+}
@@ -0,0 +1,21 @@
+package worker
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"task-manager/internal/webhook"
+	"time"
+)
+
+// WebhookJob отправляет доставки вебхуков, время попытки которых наступило
+func WebhookJob(uc webhook.WebhookUseCase, log *zap.Logger) JobFunc {
+	log = log.Named("webhook_job")
+
+	return func(ctx context.Context) error {
+		delivered, err := uc.DispatchDue(ctx, time.Now())
+		if delivered > 0 {
+			log.Info("Webhooks delivered", zap.Int("delivered", delivered))
+		}
+		return err
+	}
+}
@@ -0,0 +1,134 @@
+package worker
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"task-manager/pkg/database/redis"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// JobFunc - одна итерация периодической джобы
+type JobFunc func(ctx context.Context) error
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      JobFunc
+	leader   *leader
+}
+
+// Worker запускает периодические джобы до получения SIGINT/SIGTERM
+type Worker struct {
+	jobs            []job
+	shutdownTimeout time.Duration
+	logger          *zap.Logger
+}
+
+func New(shutdownTimeout time.Duration, logger *zap.Logger) *Worker {
+	return &Worker{
+		shutdownTimeout: shutdownTimeout,
+		logger:          logger.Named("worker"),
+	}
+}
+
+// Add регистрирует джобу, которая выполняется сразу при старте и далее раз в interval
+func (w *Worker) Add(name string, interval time.Duration, run JobFunc) {
+	w.jobs = append(w.jobs, job{name: name, interval: interval, run: run})
+}
+
+// AddLeader регистрирует джобу, которую среди реплик выполняет только держатель
+// лока name (см. leader). Лок берется на interval+ttl и снимается при остановке воркера.
+func (w *Worker) AddLeader(
+	name string,
+	interval time.Duration,
+	client *redis.Client,
+	ttl time.Duration,
+	run FencedJobFunc,
+) {
+	l := newLeader(client, name, interval, ttl, run, w.logger)
+	w.jobs = append(w.jobs, job{name: name, interval: interval, run: l.Run, leader: l})
+}
+
+func (w *Worker) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, j := range w.jobs {
+		wg.Add(1)
+		go func(j job) {
+			defer wg.Done()
+			w.loop(ctx, j)
+		}(j)
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	w.logger.Info("Worker started", zap.Int("jobs", len(w.jobs)))
+	<-quit
+	w.logger.Info("Shutting down worker...")
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		w.releaseLocks()
+		w.logger.Info("Worker stopped gracefully")
+		return nil
+	case <-time.After(w.shutdownTimeout):
+		w.logger.Error("Forced shutdown, jobs did not finish in time",
+			zap.Duration("timeout", w.shutdownTimeout),
+		)
+		return context.DeadlineExceeded
+	}
+}
+
+// releaseLocks снимает локи лидера, чтобы другие реплики не ждали истечения аренды
+func (w *Worker) releaseLocks() {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	for _, j := range w.jobs {
+		if j.leader != nil {
+			j.leader.Release(ctx)
+		}
+	}
+}
+
+func (w *Worker) loop(ctx context.Context, j job) {
+	log := w.logger.With(zap.String("job", j.name))
+	log.Info("Job scheduled", zap.Duration("interval", j.interval))
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		startTime := time.Now()
+		if err := j.run(ctx); err != nil && ctx.Err() == nil {
+			log.Error("Job run failed",
+				zap.Error(err),
+				zap.Duration("duration", time.Since(startTime)),
+			)
+		} else {
+			log.Debug("Job run finished", zap.Duration("duration", time.Since(startTime)))
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info("Job stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_tasks_overdue_scan;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS archived_at,
+    DROP COLUMN IF EXISTS overdue_at;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS task_status_history (
+    id         BIGSERIAL PRIMARY KEY,
+    task_id    UUID        NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    actor_id   BIGINT      REFERENCES users (id) ON DELETE SET NULL,
+    old_status VARCHAR(20),
+    new_status VARCHAR(20) NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_status_history_task ON task_status_history (task_id, changed_at);
+CREATE INDEX IF NOT EXISTS idx_task_status_history_new_status ON task_status_history (new_status, changed_at);
+
+-- Начальная запись для уже существующих задач
+INSERT INTO task_status_history (task_id, actor_id, old_status, new_status, changed_at)
+SELECT id, user_id, NULL, status, created_at FROM tasks;
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS roles (
+    name        VARCHAR(50) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    name        VARCHAR(100) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role       VARCHAR(50)  NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL REFERENCES permissions (name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name, description) VALUES
+    ('user', 'Regular user'),
+    ('admin', 'Administrator')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO permissions (name, description) VALUES
+    ('tasks:read_all', 'Read tasks of all users'),
+    ('users:read', 'List users'),
+    ('users:manage', 'Assign roles and disable accounts')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'tasks:read_all'),
+    ('admin', 'users:read'),
+    ('admin', 'users:manage')
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS role        VARCHAR(50) NOT NULL DEFAULT 'user' REFERENCES roles (name),
+    ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMPTZ;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tasks_due_date_id;
+DROP INDEX IF EXISTS idx_tasks_user_due_date_id;
@@ -0,0 +1,5 @@
+-- Ключ keyset-пагинации (due_date, id); архивные задачи в списки не попадают
+CREATE INDEX IF NOT EXISTS idx_tasks_user_due_date_id ON tasks (user_id, due_date, id)
+    WHERE archived_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_due_date_id ON tasks (due_date, id)
+    WHERE archived_at IS NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_tasks_user_priority_rank;
+DROP INDEX IF EXISTS idx_tasks_user_updated_at_id;
+DROP INDEX IF EXISTS idx_tasks_user_created_at_id;
+DROP INDEX IF EXISTS idx_tasks_user_priority_due_date;
+DROP INDEX IF EXISTS idx_tasks_user_status_due_date;
@@ -0,0 +1,14 @@
+-- Индексы под фильтры и сортировки GET /tasks; архивные задачи в списки не попадают
+CREATE INDEX IF NOT EXISTS idx_tasks_user_status_due_date ON tasks (user_id, status, due_date, id)
+    WHERE archived_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_user_priority_due_date ON tasks (user_id, priority, due_date, id)
+    WHERE archived_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_user_created_at_id ON tasks (user_id, created_at, id)
+    WHERE archived_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_user_updated_at_id ON tasks (user_id, updated_at, id)
+    WHERE archived_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_user_priority_rank ON tasks (
+    user_id,
+    (CASE priority WHEN 'low' THEN 1 WHEN 'medium' THEN 2 WHEN 'high' THEN 3 ELSE 0 END),
+    id
+) WHERE archived_at IS NULL;
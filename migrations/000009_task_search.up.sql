@@ -0,0 +1,18 @@
+-- Конфигурация полнотекстового поиска задач. Чтобы сменить язык, новая миграция
+-- пересоздает task_search с нужным словарем (COPY = pg_catalog.russian и т.п.)
+-- и пересчитывает search_vector через UPDATE tasks SET title = title.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'task_search') THEN
+        CREATE TEXT SEARCH CONFIGURATION task_search (COPY = pg_catalog.english);
+    END IF;
+END
+$$;
+
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('task_search'::regconfig, COALESCE(title, '')), 'A') ||
+        setweight(to_tsvector('task_search'::regconfig, COALESCE(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_search_vector ON tasks USING GIN (search_vector);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS labels (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name       VARCHAR(50) NOT NULL,
+    color      VARCHAR(7)  NOT NULL DEFAULT '#808080',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Имена меток уникальны в пределах пользователя без учета регистра
+CREATE UNIQUE INDEX IF NOT EXISTS idx_labels_user_name ON labels (user_id, lower(name));
+
+CREATE TABLE IF NOT EXISTS task_labels (
+    task_id  UUID   NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    label_id BIGINT NOT NULL REFERENCES labels (id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, label_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_labels_label ON task_labels (label_id, task_id);
@@ -0,0 +1,17 @@
+-- Удаление родителя обрабатывается приложением (cascade или reparent),
+-- SET NULL страхует от висячих ссылок при удалении задач в обход него
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES tasks (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_parent ON tasks (parent_id) WHERE parent_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS checklist_items (
+    id         BIGSERIAL PRIMARY KEY,
+    task_id    UUID         NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    text       VARCHAR(200) NOT NULL,
+    done       BOOLEAN      NOT NULL DEFAULT FALSE,
+    position   INT          NOT NULL,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_checklist_items_task ON checklist_items (task_id, position);
@@ -0,0 +1,10 @@
+-- Ребро blocker_id -> blocked_id: задачу blocked_id нельзя начинать, пока blocker_id не выполнена
+CREATE TABLE IF NOT EXISTS task_dependencies (
+    blocker_id UUID        NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    blocked_id UUID        NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (blocker_id, blocked_id),
+    CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_blocked ON task_dependencies (blocked_id, blocker_id);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_tasks_series_occurrence;
+
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS occurrence,
+    DROP COLUMN IF EXISTS series_id;
+
+DROP TABLE IF EXISTS task_series;
@@ -0,0 +1,25 @@
+-- Серия повторяющейся задачи: правило RRULE и шаблон для следующих вхождений
+CREATE TABLE IF NOT EXISTS task_series (
+    id            UUID PRIMARY KEY,
+    user_id       BIGINT       NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    rule          TEXT         NOT NULL,
+    title         VARCHAR(100) NOT NULL,
+    description   TEXT,
+    priority      VARCHAR(10)  NOT NULL,
+    starts_at     TIMESTAMPTZ  NOT NULL,
+    last_due_date TIMESTAMPTZ  NOT NULL,
+    generated     INT          NOT NULL DEFAULT 1,
+    finished_at   TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+-- Выборка серий, которым пора создать следующее вхождение
+CREATE INDEX IF NOT EXISTS idx_task_series_due ON task_series (last_due_date) WHERE finished_at IS NULL;
+
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS series_id  UUID REFERENCES task_series (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS occurrence INT;
+
+-- Номер вхождения уникален в серии: повторная генерация (воркер и завершение задачи) не создаст дубль
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_series_occurrence ON tasks (series_id, occurrence);
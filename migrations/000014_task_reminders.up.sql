@@ -0,0 +1,11 @@
+-- Напоминание за offset_minutes до срока задачи. sent_due_date - срок, о котором уже
+-- напомнили: после переноса срока напоминание снова становится активным.
+CREATE TABLE IF NOT EXISTS task_reminders (
+    id             BIGSERIAL PRIMARY KEY,
+    task_id        UUID        NOT NULL REFERENCES tasks (id) ON DELETE CASCADE,
+    offset_minutes INT         NOT NULL CHECK (offset_minutes >= 0),
+    sent_at        TIMESTAMPTZ,
+    sent_due_date  TIMESTAMPTZ,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (task_id, offset_minutes)
+);
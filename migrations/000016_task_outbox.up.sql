@@ -0,0 +1,15 @@
+-- Outbox событий задач: строка пишется в той же транзакции, что и изменение задачи,
+-- релей воркера публикует ее в Redis Stream и проставляет published_at
+CREATE TABLE IF NOT EXISTS task_outbox (
+    id           BIGSERIAL PRIMARY KEY,
+    event_id     UUID        NOT NULL,
+    event_type   VARCHAR(50) NOT NULL,
+    user_id      BIGINT      NOT NULL,
+    task_id      UUID        NOT NULL,
+    payload      JSONB       NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_outbox_pending ON task_outbox (id) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_task_outbox_published ON task_outbox (published_at) WHERE published_at IS NOT NULL;
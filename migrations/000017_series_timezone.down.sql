@@ -0,0 +1,2 @@
+ALTER TABLE task_series
+    DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,3 @@
+-- Часовой пояс серии: правило повторения вычисляется в нем, чтобы время суток не сдвигалось при переходе на летнее время
+ALTER TABLE task_series
+    ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
@@ -0,0 +1,4 @@
+-- Одна доставка события на вебхук: события из стрима обрабатываются at-least-once,
+-- и повторная обработка не должна отправлять событие второй раз. Повторы (replay) не ограничены.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_deliveries_event
+    ON webhook_deliveries (webhook_id, event_id) WHERE replay_of IS NULL;
@@ -0,0 +1,6 @@
+-- Последний fencing token, с которым писала джоба под локом лидера. Запись с меньшим
+-- токеном пришла от прежнего лидера, чья аренда истекла, и отклоняется.
+CREATE TABLE IF NOT EXISTS job_fences (
+    name  VARCHAR(100) PRIMARY KEY,
+    token BIGINT       NOT NULL
+);
@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS idx_checklist_items_task ON checklist_items (task_id, position);
+
+ALTER TABLE checklist_items DROP CONSTRAINT IF EXISTS checklist_items_task_position_key;
@@ -0,0 +1,15 @@
+-- Позиции, совпавшие до появления блокировки при добавлении, разводим с сохранением порядка
+UPDATE checklist_items c
+SET position = n.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY task_id ORDER BY position, id) AS position
+    FROM checklist_items
+) n
+WHERE c.id = n.id AND c.position != n.position;
+
+-- DEFERRABLE: уникальность проверяется в конце оператора, а не на каждой строке,
+-- поэтому перестановка пунктов одним UPDATE не спотыкается о промежуточные дубли
+ALTER TABLE checklist_items
+    ADD CONSTRAINT checklist_items_task_position_key UNIQUE (task_id, position) DEFERRABLE;
+
+DROP INDEX IF EXISTS idx_checklist_items_task;
@@ -0,0 +1,78 @@
+package apperror
+
+import "errors"
+
+// Базовые виды ошибок. Доменные ошибки модулей оборачивают один из них,
+// по нему ErrorHandler выбирает HTTP-статус.
+var (
+	ErrInvalidInput = errors.New("invalid input")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+)
+
+// Public - ошибка, текст которой можно безопасно отдать клиенту
+type Public interface {
+	error
+	Kind() error
+	PublicMessage() string
+}
+
+// FieldError - ошибка валидации конкретного поля запроса
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error - доменная ошибка с видом и публичным сообщением.
+// Cause (если есть) попадает только в логи.
+type Error struct {
+	kind    error
+	message string
+	cause   error
+	fields  []FieldError
+}
+
+func New(kind error, message string) *Error {
+	return &Error{kind: kind, message: message}
+}
+
+func Wrap(kind error, message string, cause error) *Error {
+	return &Error{kind: kind, message: message, cause: cause}
+}
+
+// WithFields возвращает копию ошибки со списком ошибок по полям
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	clone := *e
+	clone.fields = fields
+	return &clone
+}
+
+// FieldErrors - ошибки по полям запроса, если они есть
+func (e *Error) FieldErrors() []FieldError {
+	return e.fields
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.message + ": " + e.cause.Error()
+	}
+	return e.message
+}
+
+func (e *Error) Unwrap() []error {
+	if e.cause != nil {
+		return []error{e.kind, e.cause}
+	}
+	return []error{e.kind}
+}
+
+func (e *Error) Kind() error {
+	return e.kind
+}
+
+func (e *Error) PublicMessage() string {
+	return e.message
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -13,6 +14,11 @@ type Config struct {
 	Postgres    Postgres
 	Redis       Redis
 	JWT         JWT
+	Worker      Worker
+	Task        Task
+	Notifier    Notifier
+	Webhook     Webhook
+	Live        Live
 	Environment string
 }
 
@@ -34,7 +40,58 @@ type Redis struct {
 }
 
 type JWT struct {
-	Secret string
+	Secret     string
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+// Worker - настройки фоновых джобов. OverdueGracePeriod откладывает только
+// разрушающие политики просрочек (archive, delete).
+type Worker struct {
+	OverdueInterval    time.Duration
+	OverduePolicy      string
+	OverdueGracePeriod time.Duration
+	RecurrenceInterval time.Duration
+	ReminderInterval   time.Duration
+	WebhookInterval    time.Duration
+	OutboxInterval     time.Duration
+	OutboxRetention    time.Duration
+	EventsInterval     time.Duration
+	ShutdownTimeout    time.Duration
+	LockTTL            time.Duration
+}
+
+// Notifier - канал доставки напоминаний: log, webhook или smtp
+type Notifier struct {
+	Kind           string
+	WebhookURL     string
+	WebhookTimeout time.Duration
+	SMTPAddr       string
+	SMTPFrom       string
+	SMTPUsername   string
+	SMTPPassword   string
+}
+
+// Webhook - исходящие вебхуки: Timeout - ожидание ответа получателя на одну попытку
+type Webhook struct {
+	Timeout time.Duration
+}
+
+// Live - стрим живых событий задач: Heartbeat - интервал комментариев, которые держат
+// соединение открытым, Backlog и BacklogTTL - сколько событий и как долго хранится
+// для дочитывания по Last-Event-ID
+type Live struct {
+	Heartbeat  time.Duration
+	Backlog    int
+	BacklogTTL time.Duration
+}
+
+// Task - правила задач. Workflow - таблица переходов статусов в формате
+// task.ParseTransitions.
+type Task struct {
+	MaxSubtaskDepth    int
+	ParentDeletePolicy string
+	Workflow           string
 }
 
 var cfg *Config
@@ -64,14 +121,101 @@ func Load() *Config {
 			TTL: parseDuration(getEnv("REDIS_TTL", "1h")),
 		},
 		JWT: JWT{
-			Secret: getEnv("JWT_SECRET", "super-secret-key"),
+			Secret:     getEnv("JWT_SECRET", "super-secret-key"),
+			AccessTTL:  parseDuration(getEnv("JWT_ACCESS_TTL", "15m")),
+			RefreshTTL: parseDuration(getEnv("JWT_REFRESH_TTL", "720h")),
+		},
+		Worker: Worker{
+			OverdueInterval:    parseDuration(getEnv("WORKER_OVERDUE_INTERVAL", "5m")),
+			OverduePolicy:      getEnv("WORKER_OVERDUE_POLICY", "mark"),
+			OverdueGracePeriod: parseDuration(getEnv("WORKER_OVERDUE_GRACE_PERIOD", "24h")),
+			RecurrenceInterval: parseDuration(getEnv("WORKER_RECURRENCE_INTERVAL", "5m")),
+			ReminderInterval:   parseDuration(getEnv("WORKER_REMINDER_INTERVAL", "30s")),
+			WebhookInterval:    parseDuration(getEnv("WORKER_WEBHOOK_INTERVAL", "5s")),
+			OutboxInterval:     parseDuration(getEnv("WORKER_OUTBOX_INTERVAL", "1s")),
+			OutboxRetention:    parseDuration(getEnv("WORKER_OUTBOX_RETENTION", "24h")),
+			EventsInterval:     parseDuration(getEnv("WORKER_EVENTS_INTERVAL", "1s")),
+			ShutdownTimeout:    parseDuration(getEnv("WORKER_SHUTDOWN_TIMEOUT", "30s")),
+			LockTTL:            parseDuration(getEnv("WORKER_LOCK_TTL", "30s")),
+		},
+		Task: Task{
+			MaxSubtaskDepth:    parseInt(getEnv("TASK_MAX_SUBTASK_DEPTH", "3")),
+			ParentDeletePolicy: getEnv("TASK_PARENT_DELETE_POLICY", "reparent"),
+			Workflow: getEnv("TASK_WORKFLOW",
+				"pending->in_progress,pending->done,in_progress->pending,in_progress->done,done->pending:reopen"),
+		},
+		Notifier: Notifier{
+			Kind:           getEnv("NOTIFIER", "log"),
+			WebhookURL:     getEnv("NOTIFIER_WEBHOOK_URL", ""),
+			WebhookTimeout: parseDuration(getEnv("NOTIFIER_WEBHOOK_TIMEOUT", "10s")),
+			SMTPAddr:       getEnv("SMTP_ADDR", ""),
+			SMTPFrom:       getEnv("SMTP_FROM", ""),
+			SMTPUsername:   getEnv("SMTP_USERNAME", ""),
+			SMTPPassword:   getEnv("SMTP_PASSWORD", ""),
+		},
+		Webhook: Webhook{
+			Timeout: parseDuration(getEnv("WEBHOOK_TIMEOUT", "10s")),
+		},
+		Live: Live{
+			Heartbeat:  parseDuration(getEnv("LIVE_HEARTBEAT_INTERVAL", "15s")),
+			Backlog:    parseInt(getEnv("LIVE_BACKLOG_SIZE", "1000")),
+			BacklogTTL: parseDuration(getEnv("LIVE_BACKLOG_TTL", "24h")),
 		},
 		Environment: getEnv("ENVIRONMENT", "development"),
 	}
 
+	cfg.validate()
 	return cfg
 }
 
+// validate останавливает запуск на настройках, с которыми сервис не сможет работать:
+// неположительный интервал роняет time.NewTicker, а нулевой TTL или таймаут молча
+// снимает ограничение, ради которого он задан
+func (c *Config) validate() {
+	positive := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"JWT_ACCESS_TTL", c.JWT.AccessTTL},
+		{"JWT_REFRESH_TTL", c.JWT.RefreshTTL},
+		{"WORKER_OVERDUE_INTERVAL", c.Worker.OverdueInterval},
+		{"WORKER_RECURRENCE_INTERVAL", c.Worker.RecurrenceInterval},
+		{"WORKER_REMINDER_INTERVAL", c.Worker.ReminderInterval},
+		{"WORKER_WEBHOOK_INTERVAL", c.Worker.WebhookInterval},
+		{"WORKER_OUTBOX_INTERVAL", c.Worker.OutboxInterval},
+		{"WORKER_EVENTS_INTERVAL", c.Worker.EventsInterval},
+		{"WORKER_SHUTDOWN_TIMEOUT", c.Worker.ShutdownTimeout},
+		{"WORKER_LOCK_TTL", c.Worker.LockTTL},
+		{"NOTIFIER_WEBHOOK_TIMEOUT", c.Notifier.WebhookTimeout},
+		{"WEBHOOK_TIMEOUT", c.Webhook.Timeout},
+		{"LIVE_HEARTBEAT_INTERVAL", c.Live.Heartbeat},
+		{"LIVE_BACKLOG_TTL", c.Live.BacklogTTL},
+	}
+	for _, d := range positive {
+		if d.value <= 0 {
+			log.Fatalf("%s must be positive, got %s", d.name, d.value)
+		}
+	}
+
+	nonNegative := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"REDIS_TTL", c.Redis.TTL},
+		{"WORKER_OVERDUE_GRACE_PERIOD", c.Worker.OverdueGracePeriod},
+		{"WORKER_OUTBOX_RETENTION", c.Worker.OutboxRetention},
+	}
+	for _, d := range nonNegative {
+		if d.value < 0 {
+			log.Fatalf("%s must not be negative, got %s", d.name, d.value)
+		}
+	}
+
+	if c.Live.Backlog <= 0 {
+		log.Fatalf("LIVE_BACKLOG_SIZE must be positive, got %d", c.Live.Backlog)
+	}
+}
+
 // Вспомогательные функции
 func getEnv(key, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
@@ -87,3 +231,11 @@ func parseDuration(value string) time.Duration {
 	}
 	return duration
 }
+
+func parseInt(value string) int {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Invalid integer format for %s: %v", value, err)
+	}
+	return n
+}
@@ -0,0 +1,345 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// lockID - ключ advisory lock, под которым выполняются миграции
+const lockID int64 = 7346211905
+
+var fileRe = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+var (
+	ErrDirty           = errors.New("database schema is dirty")
+	ErrVersionMismatch = errors.New("database schema version mismatch")
+	ErrNoMigration     = errors.New("migration not found")
+)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Version int64
+	Dirty   bool
+	Latest  int64
+	Pending []Migration
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	log        *zap.Logger
+}
+
+func New(db *sql.DB, fsys fs.FS, log *zap.Logger) (*Migrator, error) {
+	migrations, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+		log:        log.Named("migrator"),
+	}, nil
+}
+
+// load читает пары *.up.sql / *.down.sql и сортирует их по версии
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		match := fileRe.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %q: %w", entry.Name(), err)
+		}
+
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %q: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if match[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Latest возвращает версию последней вшитой миграции
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up применяет все непримененные миграции
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		version, dirty, err := m.version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("%w: version %d", ErrDirty, version)
+		}
+
+		applied := 0
+		for _, migration := range m.migrations {
+			if migration.Version <= version {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration.Version, migration.Up, migration.Version); err != nil {
+				return err
+			}
+			applied++
+		}
+
+		m.log.Info("Migrations applied",
+			zap.Int("applied", applied),
+			zap.Int64("version", m.Latest()),
+		)
+		return nil
+	})
+}
+
+// Down откатывает steps последних примененных миграций
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		version, dirty, err := m.version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("%w: version %d", ErrDirty, version)
+		}
+
+		for i := 0; i < steps && version > 0; i++ {
+			idx := m.index(version)
+			if idx < 0 {
+				return fmt.Errorf("%w: version %d", ErrNoMigration, version)
+			}
+
+			var previous int64
+			if idx > 0 {
+				previous = m.migrations[idx-1].Version
+			}
+
+			if err := m.apply(ctx, conn, version, m.migrations[idx].Down, previous); err != nil {
+				return err
+			}
+			version = previous
+		}
+
+		m.log.Info("Migrations rolled back", zap.Int64("version", version))
+		return nil
+	})
+}
+
+// Force выставляет версию схемы и снимает флаг dirty без выполнения миграций
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		if version != 0 && m.index(version) < 0 {
+			return fmt.Errorf("%w: version %d", ErrNoMigration, version)
+		}
+		if err := m.setVersion(ctx, conn, version, false); err != nil {
+			return err
+		}
+
+		m.log.Warn("Schema version forced", zap.Int64("version", version))
+		return nil
+	})
+}
+
+// Status возвращает текущую версию схемы и список непримененных миграций
+func (m *Migrator) Status(ctx context.Context) (*Status, error) {
+	var status *Status
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		version, dirty, err := m.version(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		status = &Status{Version: version, Dirty: dirty, Latest: m.Latest()}
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				status.Pending = append(status.Pending, migration)
+			}
+		}
+		return nil
+	})
+	return status, err
+}
+
+// Check проверяет, что схема БД соответствует вшитым миграциям
+func (m *Migrator) Check(ctx context.Context) error {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if status.Dirty {
+		return fmt.Errorf("%w: version %d", ErrDirty, status.Version)
+	}
+	if status.Version != status.Latest {
+		return fmt.Errorf("%w: database at %d, binary expects %d",
+			ErrVersionMismatch, status.Version, status.Latest)
+	}
+	return nil
+}
+
+func (m *Migrator) index(version int64) int {
+	for i, migration := range m.migrations {
+		if migration.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// withLock выполняет fn на отдельном соединении под advisory lock,
+// чтобы несколько реплик не накатывали миграции одновременно
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	m.log.Debug("Acquiring migration lock", zap.Int64("lock_id", lockID))
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockID); err != nil {
+			m.log.Error("Failed to release migration lock", zap.Error(err))
+		}
+	}()
+
+	if err := m.ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func (m *Migrator) ensureTable(ctx context.Context, conn *sql.Conn) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT  NOT NULL PRIMARY KEY,
+			dirty   BOOLEAN NOT NULL
+		)`
+
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+func (m *Migrator) version(ctx context.Context, conn *sql.Conn) (int64, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := conn.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").
+		Scan(&version, &dirty)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, dirty, nil
+}
+
+func (m *Migrator) setVersion(ctx context.Context, conn *sql.Conn, version int64, dirty bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations"); err != nil {
+		return fmt.Errorf("failed to reset schema version: %w", err)
+	}
+	if version > 0 || dirty {
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO schema_migrations (version, dirty) VALUES ($1, $2)", version, dirty); err != nil {
+			return fmt.Errorf("failed to set schema version: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// apply помечает схему dirty, выполняет SQL и фиксирует новую версию.
+// Если SQL упал посреди выполнения, схема остается dirty до ручного force.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, version int64, query string, target int64) error {
+	m.log.Info("Applying migration",
+		zap.Int64("version", version),
+		zap.Int64("target", target),
+	)
+
+	if err := m.setVersion(ctx, conn, version, true); err != nil {
+		return err
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		m.log.Error("Migration failed",
+			zap.Error(err),
+			zap.Int64("version", version),
+		)
+		return fmt.Errorf("migration %d failed: %w", version, err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations"); err != nil {
+		return fmt.Errorf("failed to reset schema version: %w", err)
+	}
+	if target > 0 {
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)", target); err != nil {
+			return fmt.Errorf("failed to set schema version: %w", err)
+		}
+	}
+	return tx.Commit()
+}
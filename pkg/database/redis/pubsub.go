@@ -0,0 +1,122 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/redis/go-redis/v9"
+	"sync"
+	"time"
+)
+
+// broadcastScript добавляет ARGV[3] в стрим KEYS[1] (поле data), обрезает стрим до
+// ARGV[1] записей, продлевает его срок жизни до ARGV[2] секунд и публикует запись
+// в канал KEYS[2]. Все атомарно: подписчик не увидит в канале запись, которой нет в стриме.
+var broadcastScript = redis.NewScript(`
+local id = redis.call("XADD", KEYS[1], "MAXLEN", ARGV[1], "*", "data", ARGV[3])
+redis.call("EXPIRE", KEYS[1], ARGV[2])
+redis.call("PUBLISH", KEYS[2], cjson.encode({id = id, data = ARGV[3]}))
+return id
+`)
+
+// StreamEntry - запись стрима, разосланная через Broadcast
+type StreamEntry struct {
+	ID   string `json:"id"`
+	Data string `json:"data"`
+}
+
+// Broadcast сохраняет data в ограниченном стриме stream и рассылает подписчикам канала
+// channel. Возвращает ID записи, по которому отстающий подписчик дочитает стрим.
+func (c *Client) Broadcast(
+	ctx context.Context,
+	stream, channel string,
+	maxLen int64,
+	ttl time.Duration,
+	data []byte,
+) (string, error) {
+	return broadcastScript.Run(ctx, c.client,
+		[]string{stream, channel},
+		maxLen, int64(ttl/time.Second), data,
+	).Text()
+}
+
+// StreamSince возвращает записи стрима после after в порядке добавления
+func (c *Client) StreamSince(ctx context.Context, stream, after string) ([]StreamEntry, error) {
+	messages, err := c.client.XRange(ctx, stream, "("+after, "+").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]StreamEntry, 0, len(messages))
+	for _, m := range messages {
+		data, _ := m.Values["data"].(string)
+		entries = append(entries, StreamEntry{ID: m.ID, Data: data})
+	}
+	return entries, nil
+}
+
+// StreamFirstID возвращает ID самой старой записи стрима, для пустого стрима - ""
+func (c *Client) StreamFirstID(ctx context.Context, stream string) (string, error) {
+	messages, err := c.client.XRangeN(ctx, stream, "-", "+", 1).Result()
+	if err != nil || len(messages) == 0 {
+		return "", err
+	}
+	return messages[0].ID, nil
+}
+
+// ChannelEntry - запись, полученная подпиской из канала Channel
+type ChannelEntry struct {
+	Channel string
+	StreamEntry
+}
+
+// Subscription - подписка на каналы, куда пишет Broadcast
+type Subscription struct {
+	pubsub  *redis.PubSub
+	entries chan ChannelEntry
+	done    chan struct{}
+	once    sync.Once
+}
+
+// PSubscribe подписывается на все каналы, подходящие под pattern, и дожидается
+// подтверждения от Redis: все, что разослано после возврата, попадет в Entries.
+// Одна подписка по шаблону заменяет отдельное соединение на каждый канал.
+func (c *Client) PSubscribe(ctx context.Context, pattern string) (*Subscription, error) {
+	pubsub := c.client.PSubscribe(ctx, pattern)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	s := &Subscription{
+		pubsub:  pubsub,
+		entries: make(chan ChannelEntry),
+		done:    make(chan struct{}),
+	}
+	go s.decode(pubsub.Channel())
+	return s, nil
+}
+
+func (s *Subscription) decode(messages <-chan *redis.Message) {
+	defer close(s.entries)
+	for m := range messages {
+		entry := ChannelEntry{Channel: m.Channel}
+		if err := json.Unmarshal([]byte(m.Payload), &entry.StreamEntry); err != nil {
+			continue
+		}
+		select {
+		case s.entries <- entry:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// Entries закрывается после Close
+func (s *Subscription) Entries() <-chan ChannelEntry {
+	return s.entries
+}
+
+func (s *Subscription) Close() error {
+	s.once.Do(func() { close(s.done) })
+	return s.pubsub.Close()
+}
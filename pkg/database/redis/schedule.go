@@ -0,0 +1,87 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+// claimScript забирает до ARGV[2] элементов со сроком <= ARGV[1] и переносит их срок
+// на ARGV[3] (аренда). Выбор и перенос атомарны, поэтому несколько воркеров не получат
+// один элемент, а элемент упавшего воркера вернется в выдачу после окончания аренды.
+var claimScript = redis.NewScript(`
+local due = redis.call("ZRANGEBYSCORE", KEYS[1], "-inf", ARGV[1], "LIMIT", 0, ARGV[2])
+for _, member in ipairs(due) do
+	redis.call("ZADD", KEYS[1], ARGV[3], member)
+end
+return due
+`)
+
+// ackScript удаляет элементы, только если их срок все еще равен аренде ARGV[1]:
+// перепланированный во время обработки элемент остается в расписании
+var ackScript = redis.NewScript(`
+local removed = 0
+for i = 2, #ARGV do
+	local score = redis.call("ZSCORE", KEYS[1], ARGV[i])
+	if score and tonumber(score) == tonumber(ARGV[1]) then
+		removed = removed + redis.call("ZREM", KEYS[1], ARGV[i])
+	end
+end
+return removed
+`)
+
+// Schedule добавляет элемент в отложенную очередь key (sorted set, score - время
+// срабатывания в миллисекундах) или переносит срок уже запланированного элемента
+func (c *Client) Schedule(ctx context.Context, key, member string, at time.Time) error {
+	return c.client.ZAdd(ctx, key, redis.Z{Score: float64(at.UnixMilli()), Member: member}).Err()
+}
+
+// Unschedule убирает элементы из отложенной очереди
+func (c *Client) Unschedule(ctx context.Context, key string, members ...string) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(members))
+	for _, member := range members {
+		args = append(args, member)
+	}
+	return c.client.ZRem(ctx, key, args...).Err()
+}
+
+// ClaimDue атомарно забирает до limit элементов со сроком не позже now и арендует
+// их до now+lease. Возвращает элементы и срок аренды, который нужно передать в Ack.
+func (c *Client) ClaimDue(
+	ctx context.Context,
+	key string,
+	now time.Time,
+	limit int,
+	lease time.Duration,
+) ([]string, time.Time, error) {
+	leaseUntil := now.Add(lease)
+	members, err := claimScript.Run(ctx, c.client, []string{key},
+		now.UnixMilli(), limit, leaseUntil.UnixMilli(),
+	).StringSlice()
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to claim due entries: %w", err)
+	}
+	return members, leaseUntil, nil
+}
+
+// Ack подтверждает обработку арендованных элементов и удаляет их из очереди
+func (c *Client) Ack(ctx context.Context, key string, leaseUntil time.Time, members ...string) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(members)+1)
+	args = append(args, leaseUntil.UnixMilli())
+	for _, member := range members {
+		args = append(args, member)
+	}
+	if err := ackScript.Run(ctx, c.client, []string{key}, args...).Err(); err != nil {
+		return fmt.Errorf("failed to ack entries: %w", err)
+	}
+	return nil
+}
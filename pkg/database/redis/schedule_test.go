@@ -0,0 +1,134 @@
+package redis
+
+import (
+	"context"
+	"github.com/redis/go-redis/v9"
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+// testClient подключается к Redis из TEST_REDIS_URL. Скрипты проверяются на настоящем
+// Redis, поэтому без него тесты пропускаются.
+func testClient(t *testing.T) *Client {
+	t.Helper()
+
+	url := os.Getenv("TEST_REDIS_URL")
+	if url == "" {
+		t.Skip("TEST_REDIS_URL is not set")
+	}
+	opt, err := redis.ParseURL(url)
+	if err != nil {
+		t.Fatalf("invalid TEST_REDIS_URL: %v", err)
+	}
+
+	client := redis.NewClient(opt)
+	t.Cleanup(func() { client.Close() })
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Skipf("redis is unavailable: %v", err)
+	}
+	return &Client{client: client}
+}
+
+func testKey(t *testing.T, c *Client) string {
+	key := "test:" + t.Name()
+	t.Cleanup(func() { c.client.Del(context.Background(), key) })
+	c.client.Del(context.Background(), key)
+	return key
+}
+
+func TestClaimDue(t *testing.T) {
+	c := testClient(t)
+	key := testKey(t, c)
+	ctx := context.Background()
+	now := time.UnixMilli(1_700_000_000_000)
+
+	for member, at := range map[string]time.Time{
+		"a": now.Add(-2 * time.Minute),
+		"b": now.Add(-time.Minute),
+		"c": now,
+		"d": now.Add(time.Minute),
+	} {
+		if err := c.Schedule(ctx, key, member, at); err != nil {
+			t.Fatalf("Schedule: %v", err)
+		}
+	}
+
+	// Лимит соблюдается, раньше отдаются самые старые
+	claimed, leaseUntil, err := c.ClaimDue(ctx, key, now, 2, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimDue: %v", err)
+	}
+	if !slices.Equal(claimed, []string{"a", "b"}) {
+		t.Errorf("claimed = %v, want [a b]", claimed)
+	}
+	if !leaseUntil.Equal(now.Add(time.Minute)) {
+		t.Errorf("lease until = %v, want %v", leaseUntil, now.Add(time.Minute))
+	}
+
+	// Арендованные элементы не выдаются повторно, будущие еще не сработали
+	claimed, _, err = c.ClaimDue(ctx, key, now, 10, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimDue: %v", err)
+	}
+	if !slices.Equal(claimed, []string{"c"}) {
+		t.Errorf("second claim = %v, want [c]", claimed)
+	}
+
+	// После окончания аренды неподтвержденные элементы возвращаются в выдачу
+	claimed, _, err = c.ClaimDue(ctx, key, now.Add(time.Minute), 10, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimDue: %v", err)
+	}
+	slices.Sort(claimed)
+	if !slices.Equal(claimed, []string{"a", "b", "c", "d"}) {
+		t.Errorf("claim after lease = %v, want [a b c d]", claimed)
+	}
+}
+
+func TestAck(t *testing.T) {
+	c := testClient(t)
+	key := testKey(t, c)
+	ctx := context.Background()
+	now := time.UnixMilli(1_700_000_000_000)
+
+	for _, member := range []string{"a", "b", "c"} {
+		if err := c.Schedule(ctx, key, member, now); err != nil {
+			t.Fatalf("Schedule: %v", err)
+		}
+	}
+	_, leaseUntil, err := c.ClaimDue(ctx, key, now, 10, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimDue: %v", err)
+	}
+
+	// "b" перепланировали во время обработки: подтверждение не должно его удалить
+	if err := c.Schedule(ctx, key, "b", now.Add(time.Hour)); err != nil {
+		t.Fatalf("Schedule: %v", err)
+	}
+
+	if err := c.Ack(ctx, key, leaseUntil, "a", "b", "missing"); err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+	if err := c.Ack(ctx, key, leaseUntil); err != nil {
+		t.Fatalf("Ack without members: %v", err)
+	}
+
+	members, err := c.client.ZRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		t.Fatalf("ZRange: %v", err)
+	}
+	slices.Sort(members)
+	if !slices.Equal(members, []string{"b", "c"}) {
+		t.Errorf("remaining = %v, want [b c]", members)
+	}
+
+	score, err := c.client.ZScore(ctx, key, "b").Result()
+	if err != nil {
+		t.Fatalf("ZScore: %v", err)
+	}
+	if int64(score) != now.Add(time.Hour).UnixMilli() {
+		t.Errorf("rescheduled score = %v, want %d", score, now.Add(time.Hour).UnixMilli())
+	}
+}
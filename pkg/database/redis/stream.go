@@ -0,0 +1,141 @@
+package redis
+
+import (
+	"context"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"strings"
+)
+
+// StreamAdd добавляет запись в конец стрима key и возвращает ее ID. Стрим
+// приблизительно обрезается до maxLen последних записей, чтобы не расти бесконечно.
+func (c *Client) StreamAdd(ctx context.Context, key string, maxLen int64, values map[string]interface{}) (string, error) {
+	return c.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: key,
+		MaxLen: maxLen,
+		Approx: true,
+		Values: values,
+	}).Result()
+}
+
+// fencedAddScript добавляет запись в стрим KEYS[1], только если fencing token ARGV[1]
+// не меньше последнего выданного лока (KEYS[2]). ARGV[3:] - пары поле/значение.
+var fencedAddScript = redis.NewScript(`
+local fence = tonumber(redis.call("GET", KEYS[2]) or "0")
+if tonumber(ARGV[1]) < fence then
+	return false
+end
+return redis.call("XADD", KEYS[1], "MAXLEN", "~", ARGV[2], "*", unpack(ARGV, 3))
+`)
+
+// StreamAddFenced работает как StreamAdd, но только для владельца лока lock с токеном
+// token. Если лок уже выдан с большим токеном, запись не добавляется и возвращается
+// ErrLockLost: прежний владелец не должен писать после того, как лок перехвачен.
+func (c *Client) StreamAddFenced(
+	ctx context.Context,
+	key string,
+	maxLen int64,
+	lock string,
+	token int64,
+	values map[string]interface{},
+) (string, error) {
+	args := make([]interface{}, 0, 2+2*len(values))
+	args = append(args, token, maxLen)
+	for field, value := range values {
+		args = append(args, field, value)
+	}
+
+	id, err := fencedAddScript.Run(ctx, c.client, []string{key, fenceKey(lock)}, args...).Text()
+	if err == redis.Nil {
+		return "", ErrLockLost
+	}
+	return id, err
+}
+
+// StreamCreateGroup создает группу потребителей group стрима key (и сам стрим, если
+// его нет). Группа получает записи, добавленные после создания. Существующая группа
+// не меняется.
+func (c *Client) StreamCreateGroup(ctx context.Context, key, group string) error {
+	err := c.client.XGroupCreateMkStream(ctx, key, group, "$").Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
+
+// StreamReadGroup возвращает до count записей стрима key для потребителя consumer
+// группы group, без ожидания. Сначала отдаются записи, выданные потребителю раньше и
+// не подтвержденные через StreamAck, и только когда их нет - новые. Data записи берется
+// из поля field; у записи, вытесненной из стрима до подтверждения, Data пустая.
+func (c *Client) StreamReadGroup(
+	ctx context.Context,
+	key, group, consumer, field string,
+	count int64,
+) ([]StreamEntry, error) {
+	for _, start := range []string{"0", ">"} {
+		streams, err := c.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: consumer,
+			Streams:  []string{key, start},
+			Count:    count,
+			Block:    -1,
+		}).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(streams) == 0 || len(streams[0].Messages) == 0 {
+			continue
+		}
+
+		entries := make([]StreamEntry, 0, len(streams[0].Messages))
+		for _, m := range streams[0].Messages {
+			data, _ := m.Values[field].(string)
+			entries = append(entries, StreamEntry{ID: m.ID, Data: data})
+		}
+		return entries, nil
+	}
+	return nil, nil
+}
+
+// StreamAck подтверждает обработку записей группой group
+func (c *Client) StreamAck(ctx context.Context, key, group string, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return c.client.XAck(ctx, key, group, ids...).Err()
+}
+
+// ValidStreamID проверяет формат ID записи стрима: <миллисекунды>-<номер>
+func ValidStreamID(id string) bool {
+	_, _, ok := parseStreamID(id)
+	return ok
+}
+
+// StreamIDAfter сообщает, что запись a добавлена в стрим позже записи b
+func StreamIDAfter(a, b string) bool {
+	aMs, aSeq, _ := parseStreamID(a)
+	bMs, bSeq, _ := parseStreamID(b)
+	if aMs != bMs {
+		return aMs > bMs
+	}
+	return aSeq > bSeq
+}
+
+func parseStreamID(id string) (ms, seq uint64, ok bool) {
+	msPart, seqPart, found := strings.Cut(id, "-")
+	if !found {
+		return 0, 0, false
+	}
+	ms, err := strconv.ParseUint(msPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	seq, err = strconv.ParseUint(seqPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return ms, seq, true
+}
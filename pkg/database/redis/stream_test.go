@@ -0,0 +1,107 @@
+package redis
+
+import (
+	"context"
+	"testing"
+)
+
+func TestValidStreamID(t *testing.T) {
+	tests := map[string]bool{
+		"1700000000000-0":        true,
+		"0-0":                    true,
+		"1-18446744073709551615": true,
+		"":                       false,
+		"1700000000000":          false,
+		"-1":                     false,
+		"1-":                     false,
+		"a-1":                    false,
+		"1-1-1":                  false,
+		"-1-1":                   false,
+	}
+	for id, want := range tests {
+		if got := ValidStreamID(id); got != want {
+			t.Errorf("ValidStreamID(%q) = %v, want %v", id, got, want)
+		}
+	}
+}
+
+func TestStreamIDAfter(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "2-0", b: "1-0", want: true},
+		{a: "1-0", b: "2-0", want: false},
+		{a: "1-2", b: "1-1", want: true},
+		{a: "1-1", b: "1-1", want: false},
+		// Сравнение числовое, а не строковое
+		{a: "10-0", b: "9-0", want: true},
+		{a: "1-10", b: "1-9", want: true},
+		{a: "1700000000001-0", b: "1700000000000-99", want: true},
+	}
+	for _, tt := range tests {
+		if got := StreamIDAfter(tt.a, tt.b); got != tt.want {
+			t.Errorf("StreamIDAfter(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestStreamReadGroup(t *testing.T) {
+	c := testClient(t)
+	key := testKey(t, c)
+	ctx := context.Background()
+
+	if _, err := c.StreamAdd(ctx, key, 100, map[string]interface{}{"payload": "before"}); err != nil {
+		t.Fatalf("StreamAdd: %v", err)
+	}
+	if err := c.StreamCreateGroup(ctx, key, "g"); err != nil {
+		t.Fatalf("StreamCreateGroup: %v", err)
+	}
+	// Повторное создание группы - не ошибка
+	if err := c.StreamCreateGroup(ctx, key, "g"); err != nil {
+		t.Fatalf("StreamCreateGroup again: %v", err)
+	}
+
+	for _, payload := range []string{"a", "b"} {
+		if _, err := c.StreamAdd(ctx, key, 100, map[string]interface{}{"payload": payload}); err != nil {
+			t.Fatalf("StreamAdd: %v", err)
+		}
+	}
+
+	// Группа видит только записи после создания
+	entries, err := c.StreamReadGroup(ctx, key, "g", "c", "payload", 1)
+	if err != nil {
+		t.Fatalf("StreamReadGroup: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Data != "a" {
+		t.Fatalf("first read = %v, want [a]", entries)
+	}
+
+	// Неподтвержденная запись выдается снова раньше новых
+	again, err := c.StreamReadGroup(ctx, key, "g", "c", "payload", 10)
+	if err != nil {
+		t.Fatalf("StreamReadGroup: %v", err)
+	}
+	if len(again) != 1 || again[0].ID != entries[0].ID {
+		t.Fatalf("read before ack = %v, want %s again", again, entries[0].ID)
+	}
+
+	if err := c.StreamAck(ctx, key, "g", entries[0].ID); err != nil {
+		t.Fatalf("StreamAck: %v", err)
+	}
+	next, err := c.StreamReadGroup(ctx, key, "g", "c", "payload", 10)
+	if err != nil {
+		t.Fatalf("StreamReadGroup: %v", err)
+	}
+	if len(next) != 1 || next[0].Data != "b" {
+		t.Fatalf("read after ack = %v, want [b]", next)
+	}
+
+	if err := c.StreamAck(ctx, key, "g", next[0].ID); err != nil {
+		t.Fatalf("StreamAck: %v", err)
+	}
+	empty, err := c.StreamReadGroup(ctx, key, "g", "c", "payload", 10)
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("drained read = %v, %v; want empty", empty, err)
+	}
+}
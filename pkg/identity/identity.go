@@ -0,0 +1,31 @@
+package identity
+
+import (
+	"context"
+	"task-manager/pkg/apperror"
+)
+
+// ErrNoUser - в контексте нет пользователя, от имени которого выполняется запрос
+var ErrNoUser = apperror.New(apperror.ErrUnauthorized, "user is not present in context")
+
+type ctxKey struct{}
+
+// WithUserID кладет ID текущего пользователя в контекст запроса
+func WithUserID(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, ctxKey{}, userID)
+}
+
+// UserID достает ID текущего пользователя из контекста
+func UserID(ctx context.Context) (int64, bool) {
+	userID, ok := ctx.Value(ctxKey{}).(int64)
+	return userID, ok
+}
+
+// RequireUserID достает ID текущего пользователя или возвращает ErrNoUser
+func RequireUserID(ctx context.Context) (int64, error) {
+	userID, ok := UserID(ctx)
+	if !ok {
+		return 0, ErrNoUser
+	}
+	return userID, nil
+}
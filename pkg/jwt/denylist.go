@@ -0,0 +1,80 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"task-manager/pkg/database/redis"
+	"time"
+)
+
+// Denylist хранит в Redis отозванные access token (по jti) и поколение токенов
+// пользователя. Токены с поколением меньше текущего считаются отозванными.
+type Denylist struct {
+	redis *redis.Client
+}
+
+func NewDenylist(redis *redis.Client) *Denylist {
+	return &Denylist{redis: redis}
+}
+
+func denylistKey(jti string) string {
+	return "jwt:denylist:" + jti
+}
+
+func generationKey(userID int64) string {
+	return fmt.Sprintf("jwt:generation:%d", userID)
+}
+
+// Revoke заносит токен в denylist до момента его естественного истечения
+func (d *Denylist) Revoke(ctx context.Context, claims *Claims) error {
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return fmt.Errorf("token has no jti or expiry")
+	}
+
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if ttl <= 0 {
+		return nil
+	}
+	return d.redis.SetWithTTL(ctx, denylistKey(claims.ID), []byte("1"), ttl)
+}
+
+// IsRevoked проверяет jti по denylist и поколение токена
+func (d *Denylist) IsRevoked(ctx context.Context, claims *Claims) (bool, error) {
+	if claims.ID != "" {
+		revoked, err := d.redis.Exists(ctx, denylistKey(claims.ID))
+		if err != nil {
+			return false, fmt.Errorf("failed to check denylist: %w", err)
+		}
+		if revoked {
+			return true, nil
+		}
+	}
+
+	generation, err := d.Generation(ctx, claims.UserID)
+	if err != nil {
+		return false, err
+	}
+	return claims.Generation < generation, nil
+}
+
+// Generation возвращает текущее поколение токенов пользователя
+func (d *Denylist) Generation(ctx context.Context, userID int64) (int64, error) {
+	data, err := d.redis.Get(ctx, generationKey(userID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get token generation: %w", err)
+	}
+	if data == nil {
+		return 0, nil
+	}
+	return strconv.ParseInt(string(data), 10, 64)
+}
+
+// BumpGeneration инвалидирует все ранее выпущенные токены пользователя
+func (d *Denylist) BumpGeneration(ctx context.Context, userID int64) (int64, error) {
+	generation, err := d.redis.Incr(ctx, generationKey(userID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to bump token generation: %w", err)
+	}
+	return generation, nil
+}
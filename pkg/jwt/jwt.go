@@ -5,24 +5,49 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 type Claims struct {
-	UserID int64 `json:"user_id"`
+	UserID      int64    `json:"user_id"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions,omitempty"`
+	// Generation - поколение токенов пользователя на момент выпуска, см. Denylist.BumpGeneration
+	Generation int64 `json:"gen"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID int64, cfg *config.Config) (string, error) {
+// Subject - данные пользователя, которые попадают в access token
+type Subject struct {
+	UserID      int64
+	Role        string
+	Permissions []string
+	Generation  int64
+}
+
+// GenerateToken выпускает короткоживущий access token и возвращает время его истечения
+func GenerateToken(sub Subject, cfg *config.Config) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(cfg.JWT.AccessTTL)
 	claims := Claims{
-		UserID: userID,
+		UserID:      sub.UserID,
+		Role:        sub.Role,
+		Permissions: sub.Permissions,
+		Generation:  sub.Generation,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24)), // Токен на 24 часа
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
 			Issuer:    "task-manager",
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(cfg.JWT.Secret))
+	signed, err := token.SignedString([]byte(cfg.JWT.Secret))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
 }
 
 func ParseToken(tokenString string, cfg *config.Config) (*Claims, error) {
@@ -39,3 +64,13 @@ func ParseToken(tokenString string, cfg *config.Config) (*Claims, error) {
 
 	return nil, jwt.ErrInvalidKey
 }
+
+// HasPermission проверяет, выдано ли право в токене
+func (c *Claims) HasPermission(permission string) bool {
+	for _, p := range c.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -3,38 +3,83 @@ package middleware
 import (
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+	"net/http"
 	"strings"
 	"task-manager/pkg/config"
+	"task-manager/pkg/identity"
 	"task-manager/pkg/jwt"
+	"task-manager/pkg/response"
 )
 
-func AuthMiddleware(cfg *config.Config, log *zap.Logger) gin.HandlerFunc {
+// ClaimsKey - ключ, под которым в gin.Context лежат claims текущего токена
+const ClaimsKey = "claims"
+
+func AuthMiddleware(cfg *config.Config, denylist *jwt.Denylist, log *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tokenString := c.GetHeader("Authorization")
-		log.Debug("Authorization header", zap.String("header", tokenString))
 
 		if len(tokenString) > 7 && strings.HasPrefix(tokenString, "Bearer ") {
 			tokenString = tokenString[7:]
 		} else {
 			log.Error("Invalid Authorization header format")
-			c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+			response.Abort(c, http.StatusUnauthorized, "missing or invalid access token")
 			return
 		}
 
-		log.Debug("Token extracted", zap.String("token", tokenString))
-
 		claims, err := jwt.ParseToken(tokenString, cfg)
 		if err != nil {
 			log.Error("Token validation failed",
 				zap.Error(err),
-				zap.String("token", tokenString),
 			)
-			c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized"})
+			response.Abort(c, http.StatusUnauthorized, "missing or invalid access token")
+			return
+		}
+
+		revoked, err := denylist.IsRevoked(c.Request.Context(), claims)
+		if err != nil {
+			log.Error("Token revocation check failed",
+				zap.Error(err),
+				zap.Int64("user_id", claims.UserID),
+			)
+			response.Abort(c, http.StatusServiceUnavailable, "")
+			return
+		}
+		if revoked {
+			log.Warn("Revoked token presented",
+				zap.Int64("user_id", claims.UserID),
+				zap.String("jti", claims.ID),
+			)
+			response.Abort(c, http.StatusUnauthorized, "missing or invalid access token")
 			return
 		}
 
 		log.Debug("Token is valid", zap.Any("claims", claims))
 		c.Set("user_id", claims.UserID)
+		c.Set("role", claims.Role)
+		c.Set(ClaimsKey, claims)
+		c.Request = c.Request.WithContext(identity.WithUserID(c.Request.Context(), claims.UserID))
+		c.Next()
+	}
+}
+
+// WebSocketTokenProtocol - подпротокол, в паре с которым браузер передает access-токен:
+// new WebSocket(url, ["access_token", token]). Заголовок Authorization браузер
+// при открытии WebSocket выставить не может.
+const WebSocketTokenProtocol = "access_token"
+
+// WebSocketToken переносит access-токен из Sec-WebSocket-Protocol в Authorization,
+// чтобы WebSocket проверялся тем же AuthMiddleware. Явный Authorization не трогает.
+func WebSocketToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			protocols := strings.Split(c.GetHeader("Sec-WebSocket-Protocol"), ",")
+			for i := 0; i+1 < len(protocols); i++ {
+				if strings.TrimSpace(protocols[i]) == WebSocketTokenProtocol {
+					c.Request.Header.Set("Authorization", "Bearer "+strings.TrimSpace(protocols[i+1]))
+					break
+				}
+			}
+		}
 		c.Next()
 	}
 }
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"task-manager/pkg/response"
+)
+
+// ErrorHandler переводит ошибку, добавленную хендлером через c.Error, в ответ problem+json.
+// Текст неклассифицированных ошибок клиенту не отдается.
+func ErrorHandler(log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		status := response.Error(c, err)
+
+		if status >= http.StatusInternalServerError {
+			log.Error("Request failed",
+				zap.Error(err),
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.FullPath()),
+				zap.String("request_id", c.GetString(response.RequestIDKey)),
+			)
+		} else {
+			log.Debug("Request rejected",
+				zap.Error(err),
+				zap.Int("status", status),
+				zap.String("path", c.FullPath()),
+				zap.String("request_id", c.GetString(response.RequestIDKey)),
+			)
+		}
+	}
+}
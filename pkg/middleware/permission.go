@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"task-manager/pkg/jwt"
+	"task-manager/pkg/response"
+)
+
+// RequirePermission пропускает запрос, только если в токене есть право permission.
+// Должен стоять после AuthMiddleware.
+func RequirePermission(permission string, log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := c.Value(ClaimsKey).(*jwt.Claims)
+		if !ok {
+			log.Error("Permission check without authenticated claims",
+				zap.String("permission", permission),
+			)
+			response.Abort(c, http.StatusUnauthorized, "missing or invalid access token")
+			return
+		}
+
+		if !claims.HasPermission(permission) {
+			log.Warn("Permission denied",
+				zap.Int64("user_id", claims.UserID),
+				zap.String("role", claims.Role),
+				zap.String("permission", permission),
+				zap.String("path", c.FullPath()),
+			)
+			response.Abort(c, http.StatusForbidden, "missing permission "+permission)
+			return
+		}
+
+		c.Next()
+	}
+}
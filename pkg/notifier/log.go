@@ -0,0 +1,25 @@
+package notifier
+
+import (
+	"context"
+	"go.uber.org/zap"
+)
+
+// Log пишет уведомления в лог. Используется по умолчанию и в разработке.
+type Log struct {
+	log *zap.Logger
+}
+
+func NewLog(log *zap.Logger) *Log {
+	return &Log{log: log.Named("notifier")}
+}
+
+func (n *Log) Notify(_ context.Context, msg Message) error {
+	n.log.Info("Notification",
+		zap.Int64("user_id", msg.UserID),
+		zap.String("subject", msg.Subject),
+		zap.String("body", msg.Body),
+		zap.Any("meta", msg.Meta),
+	)
+	return nil
+}
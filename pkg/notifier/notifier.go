@@ -0,0 +1,49 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"net/http"
+	"task-manager/pkg/config"
+)
+
+// Message - уведомление пользователю. Канал доставки сам решает, какие поля ему нужны:
+// SMTP пишет на Email, webhook отправляет сообщение целиком.
+type Message struct {
+	UserID  int64             `json:"user_id"`
+	Email   string            `json:"email"`
+	Subject string            `json:"subject"`
+	Body    string            `json:"body"`
+	Meta    map[string]string `json:"meta,omitempty"`
+}
+
+// Notifier - канал доставки уведомлений
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+const (
+	KindLog     = "log"
+	KindWebhook = "webhook"
+	KindSMTP    = "smtp"
+)
+
+// New создает канал доставки, выбранный в конфигурации
+func New(cfg config.Notifier, log *zap.Logger) (Notifier, error) {
+	switch cfg.Kind {
+	case KindLog, "":
+		return NewLog(log), nil
+	case KindWebhook:
+		if cfg.WebhookURL == "" {
+			return nil, fmt.Errorf("webhook notifier requires NOTIFIER_WEBHOOK_URL")
+		}
+		return NewWebhook(cfg.WebhookURL, &http.Client{Timeout: cfg.WebhookTimeout}), nil
+	case KindSMTP:
+		if cfg.SMTPAddr == "" || cfg.SMTPFrom == "" {
+			return nil, fmt.Errorf("smtp notifier requires SMTP_ADDR and SMTP_FROM")
+		}
+		return NewSMTP(cfg.SMTPAddr, cfg.SMTPFrom, cfg.SMTPUsername, cfg.SMTPPassword), nil
+	}
+	return nil, fmt.Errorf("unknown notifier %q", cfg.Kind)
+}
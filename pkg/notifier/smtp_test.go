@@ -0,0 +1,141 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"mime"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+// fakeSMTP - минимальный SMTP-сервер без TLS и авторизации. Принимает одно письмо
+// и отдает его в канал; rejectRcpt отвечает ошибкой на RCPT TO.
+type fakeSMTP struct {
+	listener   net.Listener
+	rejectRcpt bool
+	mail       chan fakeMail
+}
+
+type fakeMail struct {
+	from string
+	to   []string
+	data string
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	s := &fakeSMTP{listener: listener, mail: make(chan fakeMail, 1)}
+	t.Cleanup(func() { listener.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *fakeSMTP) addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *fakeSMTP) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 fake ESMTP")
+
+	var mail fakeMail
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			tp.PrintfLine("250 fake")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			mail.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+			tp.PrintfLine("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			if s.rejectRcpt {
+				tp.PrintfLine("550 mailbox unavailable")
+				continue
+			}
+			mail.to = append(mail.to, strings.Trim(line[len("RCPT TO:"):], "<> "))
+			tp.PrintfLine("250 OK")
+		case cmd == "DATA":
+			tp.PrintfLine("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			mail.data = string(data)
+			tp.PrintfLine("250 queued")
+			s.mail <- mail
+		case cmd == "QUIT":
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func TestSMTPNotify(t *testing.T) {
+	server := newFakeSMTP(t)
+	n := NewSMTP(server.addr(), "tasks@example.com", "", "")
+
+	msg := Message{UserID: 1, Email: "user@example.com", Subject: "Напоминание", Body: "line 1\nline 2"}
+	if err := n.Notify(context.Background(), msg); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	mail := <-server.mail
+	if mail.from != "tasks@example.com" {
+		t.Errorf("from = %q", mail.from)
+	}
+	if len(mail.to) != 1 || mail.to[0] != "user@example.com" {
+		t.Errorf("to = %v", mail.to)
+	}
+
+	headers, err := textproto.NewReader(bufio.NewReader(strings.NewReader(mail.data))).ReadMIMEHeader()
+	if err != nil {
+		t.Fatalf("failed to parse headers: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(headers.Get("Subject"))
+	if err != nil || subject != msg.Subject {
+		t.Errorf("Subject = %q (%v), want %q", subject, err, msg.Subject)
+	}
+	if got := headers.Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if !strings.HasSuffix(mail.data, "line 1\nline 2\n") {
+		t.Errorf("body = %q", mail.data)
+	}
+}
+
+func TestSMTPNotifyRejected(t *testing.T) {
+	server := newFakeSMTP(t)
+	server.rejectRcpt = true
+
+	err := NewSMTP(server.addr(), "tasks@example.com", "", "").
+		Notify(context.Background(), Message{UserID: 1, Email: "user@example.com"})
+	if err == nil {
+		t.Fatal("expected error for rejected recipient")
+	}
+}
+
+func TestSMTPNotifyWithoutEmail(t *testing.T) {
+	err := NewSMTP("127.0.0.1:1", "tasks@example.com", "", "").Notify(context.Background(), Message{UserID: 1})
+	if err == nil {
+		t.Fatal("expected error for message without email")
+	}
+}
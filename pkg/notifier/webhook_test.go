@@ -0,0 +1,70 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebhookNotify(t *testing.T) {
+	msg := Message{UserID: 7, Email: "user@example.com", Subject: "Reminder", Body: "soon", Meta: map[string]string{"task_id": "42"}}
+
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "200 is success", status: http.StatusOK},
+		{name: "204 is success", status: http.StatusNoContent},
+		{name: "redirect is failure", status: http.StatusFound, wantErr: true},
+		{name: "client error is failure", status: http.StatusBadRequest, wantErr: true},
+		{name: "server error is failure", status: http.StatusBadGateway, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Message
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("method = %s, want POST", r.Method)
+				}
+				if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+					t.Errorf("Content-Type = %q, want application/json", ct)
+				}
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Errorf("failed to decode body: %v", err)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			client := &http.Client{
+				Timeout: time.Second,
+				CheckRedirect: func(*http.Request, []*http.Request) error {
+					return http.ErrUseLastResponse
+				},
+			}
+			err := NewWebhook(server.URL, client).Notify(context.Background(), msg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.UserID != msg.UserID || got.Subject != msg.Subject || got.Meta["task_id"] != "42" {
+				t.Errorf("received %+v, want %+v", got, msg)
+			}
+		})
+	}
+}
+
+func TestWebhookNotifyUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	err := NewWebhook(url, &http.Client{Timeout: time.Second}).Notify(context.Background(), Message{})
+	if err == nil {
+		t.Fatal("expected error for closed server")
+	}
+}
@@ -0,0 +1,11 @@
+package rbac
+
+// Роли и права, заведенные миграциями. Набор прав роли хранится в таблице role_permissions.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+
+	PermTasksReadAll = "tasks:read_all"
+	PermUsersRead    = "users:read"
+	PermUsersManage  = "users:manage"
+)
@@ -0,0 +1,58 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"task-manager/pkg/apperror"
+)
+
+// messages - источник текстов ошибок полей, задается через SetValidator
+var messages interface {
+	Message(fe validator.FieldError) string
+}
+
+// SetValidator задает валидатор, из которого берутся тексты ошибок полей
+func SetValidator(v interface {
+	Message(fe validator.FieldError) string
+}) {
+	messages = v
+}
+
+// InvalidBody превращает ошибку ShouldBindJSON в доменную ошибку
+// со списком ошибок по полям
+func InvalidBody(err error) error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]apperror.FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, apperror.FieldError{
+				Field:   fe.Field(),
+				Message: fieldMessage(fe),
+			})
+		}
+		return apperror.Wrap(apperror.ErrValidation, "request validation failed", err).WithFields(fields...)
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return apperror.Wrap(apperror.ErrInvalidInput, "malformed JSON body", err)
+	case errors.As(err, &typeErr):
+		return apperror.Wrap(apperror.ErrInvalidInput, "invalid request body", err).WithFields(apperror.FieldError{
+			Field:   typeErr.Field,
+			Message: fmt.Sprintf("must be %s", typeErr.Type.String()),
+		})
+	default:
+		return apperror.Wrap(apperror.ErrInvalidInput, "invalid request body", err)
+	}
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	if messages != nil {
+		return messages.Message(fe)
+	}
+	return fmt.Sprintf("failed on %s validation", fe.Tag())
+}
@@ -0,0 +1,109 @@
+package response
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"task-manager/pkg/apperror"
+)
+
+// ContentType - тип ответа с ошибкой по RFC 7807
+const ContentType = "application/problem+json"
+
+// RequestIDKey - ключ, под которым в gin.Context лежит ID запроса
+const RequestIDKey = "request_id"
+
+// Problem - тело ответа с ошибкой по RFC 7807
+type Problem struct {
+	Type      string                `json:"type"`
+	Title     string                `json:"title"`
+	Status    int                   `json:"status"`
+	Detail    string                `json:"detail,omitempty"`
+	Instance  string                `json:"instance,omitempty"`
+	RequestID string                `json:"request_id,omitempty"`
+	Errors    []apperror.FieldError `json:"errors,omitempty"`
+}
+
+// problemTypes - URI типа проблемы для каждого HTTP-статуса
+var problemTypes = map[int]string{
+	http.StatusBadRequest:          "/problems/invalid-input",
+	http.StatusUnauthorized:        "/problems/unauthorized",
+	http.StatusForbidden:           "/problems/forbidden",
+	http.StatusNotFound:            "/problems/not-found",
+	http.StatusConflict:            "/problems/conflict",
+	http.StatusUnprocessableEntity: "/problems/validation-error",
+	http.StatusServiceUnavailable:  "/problems/service-unavailable",
+}
+
+// New собирает Problem для текущего запроса
+func New(c *gin.Context, status int, detail string) Problem {
+	problemType, ok := problemTypes[status]
+	if !ok {
+		problemType = "about:blank"
+	}
+
+	return Problem{
+		Type:      problemType,
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  c.Request.URL.Path,
+		RequestID: c.GetString(RequestIDKey),
+	}
+}
+
+// Write отправляет Problem и прерывает цепочку хендлеров
+func Write(c *gin.Context, problem Problem) {
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(problem.Status, problem)
+}
+
+// Abort отвечает ошибкой с заданным статусом и описанием
+func Abort(c *gin.Context, status int, detail string) {
+	Write(c, New(c, status, detail))
+}
+
+// Error отвечает по доменной ошибке. Текст неклассифицированных ошибок не раскрывается.
+// Возвращает отправленный статус.
+func Error(c *gin.Context, err error) int {
+	problem := FromError(c, err)
+	Write(c, problem)
+	return problem.Status
+}
+
+// FromError собирает Problem по доменной ошибке, не отправляя ее. Нужен там, где
+// ошибка уходит не HTTP-ответом, например в сообщении WebSocket.
+func FromError(c *gin.Context, err error) Problem {
+	var public apperror.Public
+	if !errors.As(err, &public) {
+		return New(c, http.StatusInternalServerError, "")
+	}
+
+	problem := New(c, StatusFor(public.Kind()), public.PublicMessage())
+
+	var withFields interface{ FieldErrors() []apperror.FieldError }
+	if errors.As(err, &withFields) {
+		problem.Errors = withFields.FieldErrors()
+	}
+	return problem
+}
+
+// StatusFor возвращает HTTP-статус для вида ошибки
+func StatusFor(kind error) int {
+	switch {
+	case errors.Is(kind, apperror.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(kind, apperror.ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(kind, apperror.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(kind, apperror.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(kind, apperror.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(kind, apperror.ErrConflict):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,235 @@
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Freq - частота повторения
+type Freq string
+
+const (
+	Daily   Freq = "DAILY"
+	Weekly  Freq = "WEEKLY"
+	Monthly Freq = "MONTHLY"
+)
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Rule - подмножество iCalendar RRULE (RFC 5545): FREQ=DAILY|WEEKLY|MONTHLY,
+// INTERVAL, BYDAY (только для WEEKLY), UNTIL и COUNT. Неделя начинается с понедельника.
+// UNTIL без времени - полночь UTC этой даты; сравнивается с локальной датой вхождения.
+type Rule struct {
+	Freq     Freq
+	Interval int
+	ByDay    []time.Weekday
+	Until    *time.Time
+	Count    int
+
+	untilDate bool
+}
+
+// Parse разбирает строку вида "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10".
+// Префикс "RRULE:" допускается.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	rule := &Rule{Interval: 1}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("%w: duplicate %s", ErrInvalidRule, key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "FREQ":
+			switch Freq(value) {
+			case Daily, Weekly, Monthly:
+				rule.Freq = Freq(value)
+			default:
+				return nil, fmt.Errorf("%w: unsupported FREQ %s", ErrInvalidRule, value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: INTERVAL must be a positive integer", ErrInvalidRule)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: COUNT must be a positive integer", ErrInvalidRule)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, dateOnly, err := parseUntil(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: UNTIL must be YYYYMMDD or YYYYMMDDTHHMMSSZ", ErrInvalidRule)
+			}
+			rule.Until = &until
+			rule.untilDate = dateOnly
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := weekdays[strings.TrimSpace(day)]
+				if !ok {
+					return nil, fmt.Errorf("%w: unsupported BYDAY value %q", ErrInvalidRule, day)
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unsupported part %s", ErrInvalidRule, key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL are mutually exclusive", ErrInvalidRule)
+	}
+	if len(rule.ByDay) > 0 && rule.Freq != Weekly {
+		return nil, fmt.Errorf("%w: BYDAY is only supported with FREQ=WEEKLY", ErrInvalidRule)
+	}
+
+	sort.Slice(rule.ByDay, func(i, j int) bool {
+		return weekdayIndex(rule.ByDay[i]) < weekdayIndex(rule.ByDay[j])
+	})
+	return rule, nil
+}
+
+// parseUntil возвращает момент UNTIL и признак того, что указана только дата
+func parseUntil(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, false, nil
+	}
+	t, err := time.Parse("20060102", value)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t, true, nil
+}
+
+// String возвращает правило в каноническом виде
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			days = append(days, strings.ToUpper(day.String()[:2]))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil && r.untilDate {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	} else if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next возвращает вхождение, следующее за prev. start - первое вхождение серии,
+// generated - сколько вхождений уже создано. false означает, что серия закончилась.
+// Время суток и часовой пояс берутся из prev: чтобы вхождения не сдвигались при
+// переходе на летнее время, start и prev передаются в часовом поясе серии.
+func (r *Rule) Next(start, prev time.Time, generated int) (time.Time, bool) {
+	if r.Count > 0 && generated >= r.Count {
+		return time.Time{}, false
+	}
+
+	var next time.Time
+	switch r.Freq {
+	case Daily:
+		next = prev.AddDate(0, 0, r.Interval)
+	case Weekly:
+		next = r.nextWeekly(prev)
+	case Monthly:
+		next = r.nextMonthly(start, prev)
+	default:
+		return time.Time{}, false
+	}
+
+	if r.Until != nil && r.afterUntil(next) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// afterUntil проверяет, что вхождение позже UNTIL. Дата без времени включает весь
+// день и сравнивается с датой вхождения в его часовом поясе.
+func (r *Rule) afterUntil(next time.Time) bool {
+	if !r.untilDate {
+		return next.After(*r.Until)
+	}
+	year, month, day := next.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).After(*r.Until)
+}
+
+func (r *Rule) nextWeekly(prev time.Time) time.Time {
+	if len(r.ByDay) == 0 {
+		return prev.AddDate(0, 0, 7*r.Interval)
+	}
+
+	// Следующий день из BYDAY в этой же неделе
+	current := weekdayIndex(prev.Weekday())
+	for _, day := range r.ByDay {
+		if idx := weekdayIndex(day); idx > current {
+			return prev.AddDate(0, 0, idx-current)
+		}
+	}
+
+	// Иначе первый день из BYDAY через INTERVAL недель
+	monday := prev.AddDate(0, 0, -current)
+	return monday.AddDate(0, 0, 7*r.Interval+weekdayIndex(r.ByDay[0]))
+}
+
+// nextMonthly сохраняет число месяца первого вхождения. Месяцы без такого числа
+// пропускаются, как того требует RFC 5545 (31-е число бывает не в каждом месяце).
+func (r *Rule) nextMonthly(start, prev time.Time) time.Time {
+	day := start.Day()
+	for step := r.Interval; ; step += r.Interval {
+		first := time.Date(prev.Year(), prev.Month(), 1, prev.Hour(), prev.Minute(), prev.Second(), prev.Nanosecond(), prev.Location())
+		month := first.AddDate(0, step, 0)
+		if day <= daysIn(month) {
+			return month.AddDate(0, 0, day-1)
+		}
+	}
+}
+
+func daysIn(month time.Time) int {
+	return time.Date(month.Year(), month.Month()+1, 0, 0, 0, 0, 0, month.Location()).Day()
+}
+
+// weekdayIndex - номер дня недели, начиная с понедельника (0)
+func weekdayIndex(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
@@ -0,0 +1,221 @@
+package rrule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "daily", input: "FREQ=DAILY", want: "FREQ=DAILY"},
+		{name: "prefix and lower case", input: "RRULE:freq=weekly;interval=2", want: "FREQ=WEEKLY;INTERVAL=2"},
+		{name: "interval 1 is omitted", input: "FREQ=DAILY;INTERVAL=1", want: "FREQ=DAILY"},
+		{name: "byday is sorted from monday", input: "FREQ=WEEKLY;BYDAY=SU,TH,MO", want: "FREQ=WEEKLY;BYDAY=MO,TH,SU"},
+		{name: "count", input: "FREQ=MONTHLY;COUNT=3", want: "FREQ=MONTHLY;COUNT=3"},
+		{name: "until date", input: "FREQ=DAILY;UNTIL=20260110", want: "FREQ=DAILY;UNTIL=20260110"},
+		{name: "until date-time", input: "FREQ=DAILY;UNTIL=20260110T120000Z", want: "FREQ=DAILY;UNTIL=20260110T120000Z"},
+		{name: "empty", input: "", wantErr: true},
+		{name: "no freq", input: "INTERVAL=2", wantErr: true},
+		{name: "unsupported freq", input: "FREQ=YEARLY", wantErr: true},
+		{name: "zero interval", input: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{name: "negative count", input: "FREQ=DAILY;COUNT=-1", wantErr: true},
+		{name: "count with until", input: "FREQ=DAILY;COUNT=2;UNTIL=20260110", wantErr: true},
+		{name: "byday with daily", input: "FREQ=DAILY;BYDAY=MO", wantErr: true},
+		{name: "unknown weekday", input: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{name: "duplicate part", input: "FREQ=DAILY;FREQ=WEEKLY", wantErr: true},
+		{name: "malformed until", input: "FREQ=DAILY;UNTIL=2026-01-10", wantErr: true},
+		{name: "unsupported part", input: "FREQ=DAILY;BYMONTH=1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidRule) {
+					t.Fatalf("err = %v, want ErrInvalidRule", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := rule.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// occurrences возвращает первые n вхождений правила, начиная со start (включительно)
+func occurrences(t *testing.T, spec string, start time.Time, n int) []time.Time {
+	t.Helper()
+
+	rule, err := Parse(spec)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", spec, err)
+	}
+
+	out := []time.Time{start}
+	for prev := start; len(out) < n; {
+		next, ok := rule.Next(start, prev, len(out))
+		if !ok {
+			break
+		}
+		out = append(out, next)
+		prev = next
+	}
+	return out
+}
+
+func TestNext(t *testing.T) {
+	date := func(y int, m time.Month, d, hh, mm int) time.Time {
+		return time.Date(y, m, d, hh, mm, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		n     int
+		want  []time.Time
+	}{
+		{
+			name:  "daily with interval",
+			rule:  "FREQ=DAILY;INTERVAL=3",
+			start: date(2026, 1, 30, 9, 0),
+			n:     3,
+			want:  []time.Time{date(2026, 1, 30, 9, 0), date(2026, 2, 2, 9, 0), date(2026, 2, 5, 9, 0)},
+		},
+		{
+			name:  "count stops the series",
+			rule:  "FREQ=DAILY;COUNT=2",
+			start: date(2026, 1, 1, 9, 0),
+			n:     5,
+			want:  []time.Time{date(2026, 1, 1, 9, 0), date(2026, 1, 2, 9, 0)},
+		},
+		{
+			name:  "until date includes the whole day",
+			rule:  "FREQ=DAILY;UNTIL=20260103",
+			start: date(2026, 1, 1, 23, 0),
+			n:     5,
+			want:  []time.Time{date(2026, 1, 1, 23, 0), date(2026, 1, 2, 23, 0), date(2026, 1, 3, 23, 0)},
+		},
+		{
+			name:  "until date-time is exact",
+			rule:  "FREQ=DAILY;UNTIL=20260103T090000Z",
+			start: date(2026, 1, 1, 10, 0),
+			n:     5,
+			want:  []time.Time{date(2026, 1, 1, 10, 0), date(2026, 1, 2, 10, 0)},
+		},
+		{
+			name:  "weekly without byday",
+			rule:  "FREQ=WEEKLY;INTERVAL=2",
+			start: date(2026, 1, 7, 9, 0),
+			n:     3,
+			want:  []time.Time{date(2026, 1, 7, 9, 0), date(2026, 1, 21, 9, 0), date(2026, 2, 4, 9, 0)},
+		},
+		{
+			// 2026-01-05 - понедельник
+			name:  "byday walks the week in order",
+			rule:  "FREQ=WEEKLY;BYDAY=FR,MO,WE",
+			start: date(2026, 1, 5, 9, 0),
+			n:     5,
+			want: []time.Time{
+				date(2026, 1, 5, 9, 0), date(2026, 1, 7, 9, 0), date(2026, 1, 9, 9, 0),
+				date(2026, 1, 12, 9, 0), date(2026, 1, 14, 9, 0),
+			},
+		},
+		{
+			name:  "byday with interval jumps from sunday",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,SU",
+			start: date(2026, 1, 6, 9, 0),
+			n:     4,
+			want: []time.Time{
+				date(2026, 1, 6, 9, 0), date(2026, 1, 11, 9, 0),
+				date(2026, 1, 20, 9, 0), date(2026, 1, 25, 9, 0),
+			},
+		},
+		{
+			name:  "monthly on the 31st skips short months",
+			rule:  "FREQ=MONTHLY",
+			start: date(2026, 1, 31, 9, 0),
+			n:     4,
+			want:  []time.Time{date(2026, 1, 31, 9, 0), date(2026, 3, 31, 9, 0), date(2026, 5, 31, 9, 0), date(2026, 7, 31, 9, 0)},
+		},
+		{
+			name:  "monthly on the 29th skips february of a common year",
+			rule:  "FREQ=MONTHLY",
+			start: date(2027, 1, 29, 9, 0),
+			n:     3,
+			want:  []time.Time{date(2027, 1, 29, 9, 0), date(2027, 3, 29, 9, 0), date(2027, 4, 29, 9, 0)},
+		},
+		{
+			name:  "monthly on the 29th keeps february of a leap year",
+			rule:  "FREQ=MONTHLY",
+			start: date(2028, 1, 29, 9, 0),
+			n:     3,
+			want:  []time.Time{date(2028, 1, 29, 9, 0), date(2028, 2, 29, 9, 0), date(2028, 3, 29, 9, 0)},
+		},
+		{
+			name:  "monthly with interval",
+			rule:  "FREQ=MONTHLY;INTERVAL=2",
+			start: date(2026, 11, 15, 9, 0),
+			n:     3,
+			want:  []time.Time{date(2026, 11, 15, 9, 0), date(2027, 1, 15, 9, 0), date(2027, 3, 15, 9, 0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := occurrences(t, tt.rule, tt.start, tt.n)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d occurrences %v, want %d %v", len(got), got, len(tt.want), tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("occurrence %d = %v, want %v", i+1, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNextKeepsLocalTimeAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+
+	// В ночь на 2026-03-29 Берлин переходит с UTC+1 на UTC+2
+	start := time.Date(2026, 3, 28, 9, 0, 0, 0, loc)
+	got := occurrences(t, "FREQ=DAILY", start, 2)
+	if len(got) != 2 {
+		t.Fatalf("got %d occurrences, want 2", len(got))
+	}
+	if h := got[1].In(loc).Hour(); h != 9 {
+		t.Errorf("local hour after DST = %d, want 9", h)
+	}
+	if d := got[1].Sub(got[0]); d != 23*time.Hour {
+		t.Errorf("gap across DST = %v, want 23h", d)
+	}
+}
+
+func TestUntilDateUsesLocalDate(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+
+	// 2026-01-03 20:00 в Нью-Йорке - это уже 4 января по UTC, но по местной дате входит в UNTIL
+	start := time.Date(2026, 1, 2, 20, 0, 0, 0, loc)
+	got := occurrences(t, "FREQ=DAILY;UNTIL=20260103", start, 5)
+	if len(got) != 2 {
+		t.Fatalf("got %d occurrences %v, want 2", len(got), got)
+	}
+}
@@ -0,0 +1,111 @@
+package validation
+
+import (
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Validator - единый слой валидации DTO на базе go-playground/validator.
+// Реализует binding.StructValidator, поэтому ShouldBindJSON проверяет теги validate.
+type Validator struct {
+	validate *validator.Validate
+	mu       sync.RWMutex
+	messages map[string]string
+}
+
+func New() *Validator {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	validate.SetTagName("validate")
+
+	// В ошибках используем имена полей из json-тегов
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		switch name {
+		case "-":
+			return ""
+		case "":
+			return field.Name
+		}
+		return name
+	})
+
+	return &Validator{
+		validate: validate,
+		messages: map[string]string{},
+	}
+}
+
+// ValidateStruct проверяет структуру или указатель на структуру, остальные значения пропускает
+func (v *Validator) ValidateStruct(obj any) error {
+	if obj == nil {
+		return nil
+	}
+
+	value := reflect.ValueOf(obj)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	return v.validate.Struct(obj)
+}
+
+func (v *Validator) Engine() any {
+	return v.validate
+}
+
+// RegisterEnum регистрирует тег, который пропускает только значения, принятые valid
+func (v *Validator) RegisterEnum(tag, message string, valid func(string) bool) error {
+	err := v.validate.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+		return valid(fl.Field().String())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to register %s validator: %w", tag, err)
+	}
+
+	v.mu.Lock()
+	v.messages[tag] = message
+	v.mu.Unlock()
+	return nil
+}
+
+// Message возвращает человекочитаемое описание ошибки поля
+func (v *Validator) Message(fe validator.FieldError) string {
+	v.mu.RLock()
+	message, ok := v.messages[fe.Tag()]
+	v.mu.RUnlock()
+	if ok {
+		return message
+	}
+
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email"
+	case "min":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at least %s characters", fe.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "max":
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be at most %s characters", fe.Param())
+		}
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	case "timezone":
+		return "must be an IANA time zone like Europe/Moscow"
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", fe.Param())
+	default:
+		return fmt.Sprintf("failed on %s validation", fe.Tag())
+	}
+}